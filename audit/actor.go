@@ -0,0 +1,106 @@
+package audit
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"os/user"
+	"strings"
+)
+
+// Actor is who performed an operation.
+type Actor struct {
+	// Name is the user name.
+	Name string `json:"name,omitempty"`
+	// Email is the user's email address, if known.
+	Email string `json:"email,omitempty"`
+	// Source is where the actor was determined from: "env", "github-actions",
+	// "gitlab-ci", "ci", "git", "os" or "gateway".
+	Source string `json:"source"`
+	// RunURL links to the CI run that performed the operation.
+	RunURL string `json:"runUrl,omitempty"`
+}
+
+// String returns the actor's name, or its email address if it has no name.
+func (a Actor) String() string {
+	switch {
+	case a.Name != "" && a.Email != "":
+		return a.Name + " <" + a.Email + ">"
+	case a.Name != "":
+		return a.Name
+	}
+	return a.Email
+}
+
+// DetectActor determines the actor of the current process:
+//
+//   - $CWS_AUDIT_ACTOR, if set
+//   - the triggering user on GitHub Actions or GitLab CI, with the run URL
+//   - user.name and user.email from git config
+//   - the operating system user
+func DetectActor() Actor {
+	return detectActor(os.Getenv, gitConfig)
+}
+
+// detectActor implements DetectActor with the given environment and git
+// config lookups.
+func detectActor(getenv func(string) string, git func(key string) string) Actor {
+	if name := getenv("CWS_AUDIT_ACTOR"); name != "" {
+		return Actor{Name: name, Source: "env"}
+	}
+	switch {
+	case getenv("GITHUB_ACTIONS") == "true":
+		a := Actor{Name: getenv("GITHUB_ACTOR"), Source: "github-actions"}
+		if server, repo, run := getenv("GITHUB_SERVER_URL"), getenv("GITHUB_REPOSITORY"), getenv("GITHUB_RUN_ID"); server != "" && repo != "" && run != "" {
+			a.RunURL = server + "/" + repo + "/actions/runs/" + run
+		}
+		return a
+	case getenv("GITLAB_CI") == "true":
+		return Actor{
+			Name:   getenv("GITLAB_USER_LOGIN"),
+			Email:  getenv("GITLAB_USER_EMAIL"),
+			Source: "gitlab-ci",
+			RunURL: getenv("CI_JOB_URL"),
+		}
+	}
+
+	source := "git"
+	if getenv("CI") == "true" {
+		source = "ci"
+	}
+	if name, email := git("user.name"), git("user.email"); name != "" || email != "" {
+		return Actor{Name: name, Email: email, Source: source}
+	}
+	name := getenv("USER")
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	if source == "git" {
+		source = "os"
+	}
+	return Actor{Name: name, Source: source}
+}
+
+// gitConfig returns the value of a git config key, or "" if git is not
+// available or the key is unset.
+func gitConfig(key string) string {
+	out, err := exec.Command("git", "config", "--get", key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+type actorKey struct{}
+
+// WithActor returns a context whose audited operations are attributed to
+// actor instead of the Transport's default actor.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set by WithActor.
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	a, ok := ctx.Value(actorKey{}).(Actor)
+	return a, ok
+}
@@ -0,0 +1,367 @@
+// Package audit keeps a tamper-evident log of the operations that change
+// Chrome Web Store items: uploads, publishes, cancelled submissions and
+// deploy percentage changes. Every entry records who performed the
+// operation and includes the hash of the previous entry, so that editing,
+// reordering or removing entries breaks the chain.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/H0R15H0/chrome-webstore-api-v2/internal/filelock"
+)
+
+// Operation identifies an audited operation.
+type Operation string
+
+const (
+	// OperationUpload uploads a package.
+	OperationUpload Operation = "upload"
+	// OperationPublish submits an item for publishing.
+	OperationPublish Operation = "publish"
+	// OperationCancelSubmission cancels a pending submission.
+	OperationCancelSubmission Operation = "cancel-submission"
+	// OperationSetDeployPercentage sets the published deploy percentage.
+	OperationSetDeployPercentage Operation = "set-deploy-percentage"
+)
+
+// Entry is a single audit log entry.
+type Entry struct {
+	// Seq is the position of the entry in the log, starting at 1.
+	Seq int64 `json:"seq"`
+	// Time is when the response was received.
+	Time time.Time `json:"time"`
+	// Actor is who performed the operation.
+	Actor Actor `json:"actor"`
+	// Operation is the operation performed.
+	Operation Operation `json:"operation"`
+	// Item is the item the operation applied to.
+	Item chromewebstore.ItemName `json:"item"`
+	// Query is the encoded query string of the request.
+	Query string `json:"query,omitempty"`
+	// Request is the JSON request body, if any.
+	Request json.RawMessage `json:"request,omitempty"`
+	// PolicyOverride is the policy override that let the operation
+	// through, if any.
+	PolicyOverride *chromewebstore.PolicyOverride `json:"policyOverride,omitempty"`
+	// PackageSHA256 is the hex-encoded SHA-256 digest of the uploaded
+	// package.
+	PackageSHA256 string `json:"packageSha256,omitempty"`
+	// PackageSize is the size of the uploaded package in bytes.
+	PackageSize int64 `json:"packageSize,omitempty"`
+	// StatusCode is the HTTP status code of the response, or zero if no
+	// response was received.
+	StatusCode int `json:"statusCode,omitempty"`
+	// State is the upload state or item state of the response.
+	State string `json:"state,omitempty"`
+	// RequestID is the request ID assigned by the store.
+	RequestID string `json:"requestId,omitempty"`
+	// Error describes why the operation failed, if it did.
+	Error string `json:"error,omitempty"`
+	// Prev is the hash of the previous entry, or "" for the first entry.
+	Prev string `json:"prev"`
+	// Hash is the hex-encoded SHA-256 digest of the entry with Hash unset.
+	Hash string `json:"hash"`
+}
+
+// computeHash returns the hash of e.
+func (e Entry) computeHash() (string, error) {
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", fmt.Errorf("audit: failed to marshal entry: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// FileName is the name of the audit log in the default location.
+const FileName = "audit.jsonl"
+
+// DefaultPath returns the default audit log location: $CWS_AUDIT_FILE if
+// set, otherwise cws/audit.jsonl in the user configuration directory.
+func DefaultPath() (string, error) {
+	if path := os.Getenv("CWS_AUDIT_FILE"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("audit: %w", err)
+	}
+	return filepath.Join(dir, "cws", FileName), nil
+}
+
+// Log is an audit log file in JSON Lines format.
+type Log struct {
+	path string
+	mu   sync.Mutex
+}
+
+// New returns a log backed by the file at path. The file is created on the
+// first Append.
+func New(path string) *Log {
+	return &Log{path: path}
+}
+
+// Path returns the path of the audit log.
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Append chains e to the last entry and adds it to the log. Seq, Prev and
+// Hash are set by Append; if e.Time is zero, the current time is used.
+// Appends are serialized between processes with a lock file next to the
+// log.
+func (l *Log) Append(e Entry) (Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return Entry{}, fmt.Errorf("audit: %w", err)
+	}
+	unlock, err := filelock.Lock(l.path + ".lock")
+	if err != nil {
+		return Entry{}, fmt.Errorf("audit: %w", err)
+	}
+	defer unlock()
+
+	f, err := os.OpenFile(l.path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return Entry{}, fmt.Errorf("audit: %w", err)
+	}
+	defer f.Close()
+
+	last, err := lastLine(f)
+	if err != nil {
+		return Entry{}, err
+	}
+	e.Seq, e.Prev = 1, ""
+	if last != nil {
+		var prev Entry
+		if err := json.Unmarshal(last, &prev); err != nil {
+			return Entry{}, fmt.Errorf("audit: failed to parse last entry: %w", err)
+		}
+		e.Seq, e.Prev = prev.Seq+1, prev.Hash
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	e.Time = e.Time.UTC().Round(0)
+	if e.Hash, err = e.computeHash(); err != nil {
+		return Entry{}, err
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return Entry{}, fmt.Errorf("audit: failed to marshal entry: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return Entry{}, fmt.Errorf("audit: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return Entry{}, fmt.Errorf("audit: %w", err)
+	}
+	return e, nil
+}
+
+// writable checks that entries can be appended to the log, creating it if
+// needed.
+func (l *Log) writable() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("audit: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("audit: %w", err)
+	}
+	return f.Close()
+}
+
+// lastLine returns the last non-empty line of f, or nil if f is empty.
+func lastLine(f *os.File) ([]byte, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("audit: %w", err)
+	}
+	const chunk = 4096
+	var buf []byte
+	for end := info.Size(); end > 0; {
+		start := max(end-chunk, 0)
+		b := make([]byte, end-start)
+		if _, err := f.ReadAt(b, start); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("audit: %w", err)
+		}
+		buf = append(b, buf...)
+		end = start
+		trimmed := bytes.TrimRight(buf, "\n")
+		if i := bytes.LastIndexByte(trimmed, '\n'); i >= 0 {
+			return trimmed[i+1:], nil
+		}
+		if end == 0 && len(trimmed) > 0 {
+			return trimmed, nil
+		}
+	}
+	return nil, nil
+}
+
+// TamperError reports where the hash chain of a log is broken.
+type TamperError struct {
+	// Line is the line number of the offending entry.
+	Line int
+	// Seq is the sequence number recorded in the offending entry.
+	Seq int64
+	// Reason describes the inconsistency.
+	Reason string
+}
+
+// Error returns the error message.
+func (e *TamperError) Error() string {
+	return fmt.Sprintf("audit: line %d (seq %d): %s", e.Line, e.Seq, e.Reason)
+}
+
+// Verify reads the whole log and checks its hash chain. It returns the
+// entries read, and a *TamperError at the first entry that was modified,
+// reordered or follows removed entries. Removing entries from the end of
+// the log cannot be detected from the log alone; compare the hash of the
+// last entry with one recorded elsewhere, such as by VerifyHead.
+func (l *Log) Verify() ([]Entry, error) {
+	var entries []Entry
+	err := l.scan(func(line int, raw []byte) error {
+		var e Entry
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return &TamperError{Line: line, Reason: fmt.Sprintf("invalid entry: %v", err)}
+		}
+		want, prev := int64(1), ""
+		if n := len(entries); n > 0 {
+			want, prev = entries[n-1].Seq+1, entries[n-1].Hash
+		}
+		if e.Seq != want {
+			return &TamperError{Line: line, Seq: e.Seq, Reason: fmt.Sprintf("expected seq %d", want)}
+		}
+		if e.Prev != prev {
+			return &TamperError{Line: line, Seq: e.Seq, Reason: "previous hash does not match the previous entry"}
+		}
+		hash, err := e.computeHash()
+		if err != nil {
+			return err
+		}
+		if hash != e.Hash {
+			return &TamperError{Line: line, Seq: e.Seq, Reason: "entry hash does not match its contents"}
+		}
+		entries = append(entries, e)
+		return nil
+	})
+	return entries, err
+}
+
+// VerifyHead verifies the log and checks that it still contains the entry
+// with the given hash, recorded earlier from Head, and entries after it
+// only if they were appended since. It detects entries removed from the
+// end of the log.
+func (l *Log) VerifyHead(head string) ([]Entry, error) {
+	entries, err := l.Verify()
+	if err != nil {
+		return entries, err
+	}
+	if !slices.ContainsFunc(entries, func(e Entry) bool { return e.Hash == head }) {
+		return entries, &TamperError{Line: len(entries), Seq: int64(len(entries)), Reason: fmt.Sprintf("no entry has hash %s; entries may have been removed", head)}
+	}
+	return entries, nil
+}
+
+// Head returns the hash of the last entry in entries, or "".
+func Head(entries []Entry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	return entries[len(entries)-1].Hash
+}
+
+// Filter selects entries.
+type Filter struct {
+	// Items selects entries of the given items. If empty, all items match.
+	Items []chromewebstore.ItemName
+	// Actor selects entries whose actor name or email equals Actor.
+	Actor string
+	// Operations selects entries of the given operations. If empty, all
+	// operations match.
+	Operations []Operation
+	// Since and Until bound the entry time. Zero values are unbounded.
+	Since, Until time.Time
+}
+
+// Matches reports whether e is selected by f.
+func (f *Filter) Matches(e Entry) bool {
+	switch {
+	case len(f.Items) > 0 && !slices.Contains(f.Items, e.Item):
+		return false
+	case f.Actor != "" && f.Actor != e.Actor.Name && f.Actor != e.Actor.Email:
+		return false
+	case len(f.Operations) > 0 && !slices.Contains(f.Operations, e.Operation):
+		return false
+	case !f.Since.IsZero() && e.Time.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !e.Time.Before(f.Until):
+		return false
+	}
+	return true
+}
+
+// Entries returns the entries selected by f in log order without verifying
+// the chain. A missing log yields no entries.
+func (l *Log) Entries(f Filter) ([]Entry, error) {
+	var entries []Entry
+	err := l.scan(func(line int, raw []byte) error {
+		var e Entry
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return fmt.Errorf("audit: %s:%d: %w", l.path, line, err)
+		}
+		if f.Matches(e) {
+			entries = append(entries, e)
+		}
+		return nil
+	})
+	return entries, err
+}
+
+// scan calls fn with every non-empty line of the log. A missing log has no
+// lines.
+func (l *Log) scan(fn func(line int, raw []byte) error) error {
+	f, err := os.Open(l.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("audit: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if err := fn(line, scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("audit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package audit
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+const (
+	itemA = chromewebstore.ItemName("publishers/pub/items/aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	itemB = chromewebstore.ItemName("publishers/pub/items/bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+)
+
+// newTestLog returns a log with four entries.
+func newTestLog(t *testing.T) *Log {
+	t.Helper()
+	log := New(filepath.Join(t.TempDir(), "audit", FileName))
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for i, e := range []Entry{
+		{Operation: OperationUpload, Item: itemA, Actor: Actor{Name: "alice", Source: "git"}, PackageSHA256: "abc", State: "SUCCEEDED"},
+		{Operation: OperationPublish, Item: itemA, Actor: Actor{Name: "alice", Source: "git"}, Request: []byte(`{ "publishType": "STAGED_PUBLISH" }`), State: "PENDING_REVIEW"},
+		{Operation: OperationUpload, Item: itemB, Actor: Actor{Name: "bot", Email: "bot@example.com", Source: "github-actions"}},
+		{Operation: OperationSetDeployPercentage, Item: itemA, Actor: Actor{Name: "bob", Source: "git"}, Query: "deployPercentage=50"},
+	} {
+		e.Time = base.Add(time.Duration(i) * time.Hour)
+		got, err := log.Append(e)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got.Seq != int64(i+1) {
+			t.Errorf("expected seq %d, got %d", i+1, got.Seq)
+		}
+	}
+	return log
+}
+
+func TestAppendAndVerify(t *testing.T) {
+	log := newTestLog(t)
+
+	entries, err := log.Verify()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(entries))
+	}
+	if entries[0].Prev != "" {
+		t.Errorf("expected the first entry to have no previous hash, got %s", entries[0].Prev)
+	}
+	for i := 1; i < len(entries); i++ {
+		if entries[i].Prev != entries[i-1].Hash {
+			t.Errorf("expected entry %d to chain to entry %d", i+1, i)
+		}
+	}
+	if _, err := log.VerifyHead(entries[2].Hash); err != nil {
+		t.Errorf("expected a known head to verify, got %v", err)
+	}
+	if _, err := os.Stat(log.Path() + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("expected the lock file to be removed, got %v", err)
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(lines [][]byte) [][]byte
+		line   int
+	}{
+		{"modified", func(lines [][]byte) [][]byte {
+			lines[1] = bytes.Replace(lines[1], []byte("STAGED_PUBLISH"), []byte("DEFAULT_PUBLISH"), 1)
+			return lines
+		}, 2},
+		{"removed", func(lines [][]byte) [][]byte {
+			return append(lines[:1], lines[2:]...)
+		}, 2},
+		{"reordered", func(lines [][]byte) [][]byte {
+			lines[1], lines[2] = lines[2], lines[1]
+			return lines
+		}, 2},
+		{"actor changed", func(lines [][]byte) [][]byte {
+			lines[3] = bytes.Replace(lines[3], []byte(`"bob"`), []byte(`"eve"`), 1)
+			return lines
+		}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := newTestLog(t)
+			data, err := os.ReadFile(log.Path())
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := tt.tamper(bytes.Split(bytes.TrimSpace(data), []byte("\n")))
+			if err := os.WriteFile(log.Path(), append(bytes.Join(lines, []byte("\n")), '\n'), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = log.Verify()
+			var tamperErr *TamperError
+			if !errors.As(err, &tamperErr) {
+				t.Fatalf("expected a TamperError, got %v", err)
+			}
+			if tamperErr.Line != tt.line {
+				t.Errorf("expected line %d, got %d (%v)", tt.line, tamperErr.Line, err)
+			}
+		})
+	}
+}
+
+func TestVerifyHeadDetectsTruncation(t *testing.T) {
+	log := newTestLog(t)
+	entries, _ := log.Verify()
+	head := Head(entries)
+
+	data, _ := os.ReadFile(log.Path())
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	os.WriteFile(log.Path(), append(bytes.Join(lines[:3], []byte("\n")), '\n'), 0o644)
+
+	if _, err := log.Verify(); err != nil {
+		t.Errorf("expected a truncated log to verify on its own, got %v", err)
+	}
+	var tamperErr *TamperError
+	if _, err := log.VerifyHead(head); !errors.As(err, &tamperErr) {
+		t.Errorf("expected a TamperError, got %v", err)
+	}
+}
+
+func TestEntries(t *testing.T) {
+	log := newTestLog(t)
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []int64
+	}{
+		{"all", Filter{}, []int64{1, 2, 3, 4}},
+		{"item", Filter{Items: []chromewebstore.ItemName{itemB}}, []int64{3}},
+		{"actor name", Filter{Actor: "alice"}, []int64{1, 2}},
+		{"actor email", Filter{Actor: "bot@example.com"}, []int64{3}},
+		{"operation", Filter{Operations: []Operation{OperationUpload}}, []int64{1, 3}},
+		{"time range", Filter{
+			Since: time.Date(2025, 6, 1, 13, 0, 0, 0, time.UTC),
+			Until: time.Date(2025, 6, 1, 15, 0, 0, 0, time.UTC),
+		}, []int64{2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := log.Entries(tt.filter)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			var got []int64
+			for _, e := range entries {
+				got = append(got, e.Seq)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected seqs %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("expected seqs %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestMissingLog(t *testing.T) {
+	log := New(filepath.Join(t.TempDir(), "missing.jsonl"))
+	entries, err := log.Verify()
+	if err != nil || len(entries) != 0 {
+		t.Errorf("expected no entries and no error, got %d, %v", len(entries), err)
+	}
+}
+
+func TestDetectActor(t *testing.T) {
+	noGit := func(string) string { return "" }
+	git := func(key string) string {
+		return map[string]string{"user.name": "Alice", "user.email": "alice@example.com"}[key]
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		git  func(string) string
+		want Actor
+	}{
+		{"override", map[string]string{"CWS_AUDIT_ACTOR": "release-team", "GITHUB_ACTIONS": "true"}, git,
+			Actor{Name: "release-team", Source: "env"}},
+		{"github actions", map[string]string{
+			"GITHUB_ACTIONS": "true", "GITHUB_ACTOR": "octocat", "GITHUB_SERVER_URL": "https://github.com",
+			"GITHUB_REPOSITORY": "org/ext", "GITHUB_RUN_ID": "42",
+		}, git, Actor{Name: "octocat", Source: "github-actions", RunURL: "https://github.com/org/ext/actions/runs/42"}},
+		{"gitlab ci", map[string]string{
+			"GITLAB_CI": "true", "GITLAB_USER_LOGIN": "dev", "GITLAB_USER_EMAIL": "dev@example.com", "CI_JOB_URL": "https://gitlab.example.com/job/1",
+		}, git, Actor{Name: "dev", Email: "dev@example.com", Source: "gitlab-ci", RunURL: "https://gitlab.example.com/job/1"}},
+		{"git", nil, git, Actor{Name: "Alice", Email: "alice@example.com", Source: "git"}},
+		{"other ci", map[string]string{"CI": "true"}, git, Actor{Name: "Alice", Email: "alice@example.com", Source: "ci"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detectActor(func(k string) string { return tt.env[k] }, tt.git)
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	if got := detectActor(func(string) string { return "" }, noGit); got.Source != "os" || strings.TrimSpace(got.Name) == "" {
+		t.Errorf("expected the OS user, got %+v", got)
+	}
+}
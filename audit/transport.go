@@ -0,0 +1,199 @@
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+// operations maps the custom method suffixes of request paths to the
+// operations they perform.
+var operations = map[string]Operation{
+	":upload":                       OperationUpload,
+	":publish":                      OperationPublish,
+	":cancelSubmission":             OperationCancelSubmission,
+	":setPublishedDeployPercentage": OperationSetDeployPercentage,
+}
+
+// maxResponseBytes limits the response bodies read for auditing.
+const maxResponseBytes = 1 << 20
+
+// Transport is an http.RoundTripper recording the audited operations sent
+// through it in a Log. Other requests are passed through unchanged. Use it
+// as the transport of the HTTP client given to chromewebstore.NewClient,
+// wrapping the OAuth transport.
+type Transport struct {
+	// Base performs the requests. If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+	// Log receives the entries.
+	Log *Log
+	// Actor is recorded for requests whose context has no actor set by
+	// WithActor.
+	Actor Actor
+	// OnError, if set, is called when an entry cannot be appended. The
+	// response is returned regardless, as the operation has been performed,
+	// unless the request carries a policy override.
+	OnError func(error)
+}
+
+// RoundTrip implements http.RoundTripper. Requests let through by a
+// policy override are only sent if the log is writable, and fail if their
+// entry cannot be appended, so that no override goes unrecorded.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	op, item, ok := audited(req)
+	if !ok {
+		return base.RoundTrip(req)
+	}
+
+	e := Entry{Operation: op, Item: item, Query: req.URL.RawQuery, Actor: t.Actor}
+	if actor, ok := ActorFromContext(req.Context()); ok {
+		e.Actor = actor
+	}
+	if override, ok := chromewebstore.AppliedPolicyOverride(req.Context()); ok {
+		e.PolicyOverride = &override
+		if err := t.Log.writable(); err != nil {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, fmt.Errorf("audit: policy override cannot be recorded: %w", err)
+		}
+	}
+
+	out := req.Clone(req.Context())
+	var digest *hashingReader
+	switch {
+	case req.Body == nil || req.Body == http.NoBody:
+	case op == OperationUpload:
+		digest = &hashingReader{r: req.Body, h: sha256.New()}
+		out.Body = digest
+	default:
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if json.Valid(body) {
+			e.Request = body
+		}
+		out.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := base.RoundTrip(out)
+	e.Time = time.Now()
+	if digest != nil {
+		e.PackageSHA256, e.PackageSize = digest.sum()
+	}
+	if err != nil {
+		e.Error = err.Error()
+		t.append(e)
+		return nil, err
+	}
+
+	e.StatusCode = resp.StatusCode
+	e.RequestID = resp.Header.Get(chromewebstore.RequestIDHeader)
+	data, readErr := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+	if readErr != nil {
+		e.Error = readErr.Error()
+	}
+	var result struct {
+		UploadState string `json:"uploadState"`
+		State       string `json:"state"`
+		Error       *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	json.Unmarshal(data, &result)
+	e.State = result.State
+	if result.UploadState != "" {
+		e.State = result.UploadState
+	}
+	if resp.StatusCode >= 300 {
+		e.Error = fmt.Sprintf("HTTP %d", resp.StatusCode)
+		if result.Error != nil && result.Error.Message != "" {
+			e.Error += ": " + result.Error.Message
+		}
+	}
+	if err := t.append(e); err != nil && e.PolicyOverride != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("audit: %s was sent but its policy override could not be recorded: %w", op, err)
+	}
+	return resp, nil
+}
+
+// append adds e to the log, reporting failures to OnError unless e carries
+// a policy override, in which case the failure is returned.
+func (t *Transport) append(e Entry) error {
+	_, err := t.Log.Append(e)
+	if err != nil && e.PolicyOverride == nil && t.OnError != nil {
+		t.OnError(err)
+	}
+	return err
+}
+
+// audited returns the operation and item of req, and whether it is audited.
+func audited(req *http.Request) (Operation, chromewebstore.ItemName, bool) {
+	if req.Method != http.MethodPost {
+		return "", "", false
+	}
+	path := req.URL.Path
+	i := strings.LastIndexByte(path, ':')
+	if i < 0 {
+		return "", "", false
+	}
+	op, ok := operations[path[i:]]
+	if !ok {
+		return "", "", false
+	}
+	j := strings.Index(path, "publishers/")
+	if j < 0 || j > i {
+		return "", "", false
+	}
+	return op, chromewebstore.ItemName(path[j:i]), true
+}
+
+// hashingReader hashes the bytes read through it. The transport may read
+// the body in another goroutine, so access is synchronized.
+type hashingReader struct {
+	mu sync.Mutex
+	r  io.ReadCloser
+	h  hash.Hash
+	n  int64
+}
+
+func (r *hashingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.mu.Lock()
+	r.h.Write(p[:n])
+	r.n += int64(n)
+	r.mu.Unlock()
+	return n, err
+}
+
+func (r *hashingReader) Close() error {
+	return r.r.Close()
+}
+
+// sum returns the hex-encoded digest and the number of bytes read so far.
+func (r *hashingReader) sum() (string, int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return hex.EncodeToString(r.h.Sum(nil)), r.n
+}
@@ -0,0 +1,158 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(chromewebstore.RequestIDHeader, "req-"+r.Method)
+		switch {
+		case strings.HasSuffix(r.URL.Path, ":upload"):
+			w.Write([]byte(`{"itemId":"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa","uploadState":"SUCCEEDED"}`))
+		case strings.HasSuffix(r.URL.Path, ":publish"):
+			w.Write([]byte(`{"itemId":"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa","state":"PENDING_REVIEW"}`))
+		case strings.HasSuffix(r.URL.Path, ":cancelSubmission"):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":400,"message":"nothing to cancel"}}`))
+		default:
+			w.Write([]byte(`{"itemId":"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"}`))
+		}
+	}))
+	defer server.Close()
+
+	log := New(filepath.Join(t.TempDir(), FileName))
+	var appendErrs []error
+	transport := &Transport{
+		Log:     log,
+		Actor:   Actor{Name: "alice", Source: "git"},
+		OnError: func(err error) { appendErrs = append(appendErrs, err) },
+	}
+	client := chromewebstore.NewClient(&http.Client{Transport: transport})
+	client.SetBaseURL(server.URL)
+	client.SetUploadBaseURL(server.URL)
+	ctx := context.Background()
+
+	pkg := "PK\x03\x04 package"
+	upload, err := client.Media.Upload(itemA).Context(ctx).Media(strings.NewReader(pkg), chromewebstore.MediaTypeZIP).Do()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if upload.UploadState != chromewebstore.UploadStateSucceeded {
+		t.Errorf("expected the response to be passed through, got %+v", upload)
+	}
+	if _, err := client.Publishers.Items.FetchStatus(itemA).Context(ctx).Do(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	client.SetPolicy(chromewebstore.PolicyFunc(func(ctx context.Context, req *chromewebstore.PolicyRequest) ([]string, error) {
+		return []string{"no-fridays"}, nil
+	}))
+	botCtx := WithActor(ctx, Actor{Name: "key:ci", Source: "gateway"})
+	botCtx = chromewebstore.WithPolicyOverride(botCtx, chromewebstore.PolicyOverride{Reason: "hotfix"})
+	if _, err := client.Publishers.Items.Publish(itemA).Context(botCtx).PublishType(chromewebstore.PublishTypeStaged).DeployPercentage(10).Do(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := client.Publishers.Items.CancelSubmission(itemA).Context(ctx).Do(); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(appendErrs) > 0 {
+		t.Fatalf("expected no append errors, got %v", appendErrs)
+	}
+
+	entries, err := log.Verify()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+
+	sum := sha256.Sum256([]byte(pkg))
+	up := entries[0]
+	if up.Operation != OperationUpload || up.Item != itemA || up.State != "SUCCEEDED" || up.Actor.Name != "alice" {
+		t.Errorf("unexpected upload entry %+v", up)
+	}
+	if up.PackageSHA256 != hex.EncodeToString(sum[:]) || up.PackageSize != int64(len(pkg)) {
+		t.Errorf("expected package digest %x and size %d, got %s and %d", sum, len(pkg), up.PackageSHA256, up.PackageSize)
+	}
+	if up.RequestID != "req-POST" {
+		t.Errorf("expected request ID req-POST, got %s", up.RequestID)
+	}
+
+	pub := entries[1]
+	if pub.Operation != OperationPublish || pub.State != "PENDING_REVIEW" || pub.Actor.Name != "key:ci" {
+		t.Errorf("unexpected publish entry %+v", pub)
+	}
+	if pub.PolicyOverride == nil || pub.PolicyOverride.Reason != "hotfix" || pub.PolicyOverride.Rules[0] != "no-fridays" {
+		t.Errorf("expected the applied policy override to be recorded, got %+v", pub.PolicyOverride)
+	}
+	if up.PolicyOverride != nil {
+		t.Errorf("expected no policy override on the upload, got %+v", up.PolicyOverride)
+	}
+	if !strings.Contains(string(pub.Request), `"publishType":"STAGED_PUBLISH"`) || !strings.Contains(string(pub.Request), `"deployPercentage":10`) {
+		t.Errorf("expected the request body to be recorded, got %s", pub.Request)
+	}
+
+	cancel := entries[2]
+	if cancel.Operation != OperationCancelSubmission || cancel.StatusCode != http.StatusBadRequest || cancel.Error != "HTTP 400: nothing to cancel" {
+		t.Errorf("unexpected cancel entry %+v", cancel)
+	}
+}
+
+func TestTransportUnrecordedOverride(t *testing.T) {
+	sent := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"state":"PUBLISHED"}`))
+	}))
+	defer server.Close()
+
+	// A regular file in place of the log directory makes the log unwritable.
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var appendErrs []error
+	transport := &Transport{
+		Log:     New(filepath.Join(blocker, FileName)),
+		OnError: func(err error) { appendErrs = append(appendErrs, err) },
+	}
+	client := chromewebstore.NewClient(&http.Client{Transport: transport})
+	client.SetBaseURL(server.URL)
+	client.SetPolicy(chromewebstore.PolicyFunc(func(ctx context.Context, req *chromewebstore.PolicyRequest) ([]string, error) {
+		if req.Override != nil {
+			return []string{"no-fridays"}, nil
+		}
+		return nil, nil
+	}))
+
+	ctx := chromewebstore.WithPolicyOverride(context.Background(), chromewebstore.PolicyOverride{Reason: "hotfix"})
+	_, err := client.Publishers.Items.Publish(itemA).Context(ctx).Do()
+	if err == nil || !strings.Contains(err.Error(), "policy override cannot be recorded") {
+		t.Fatalf("expected the overridden publish to fail, got %v", err)
+	}
+	if sent != 0 {
+		t.Errorf("expected the overridden publish not to be sent, got %d requests", sent)
+	}
+
+	if _, err := client.Publishers.Items.Publish(itemA).Context(context.Background()).Do(); err != nil {
+		t.Fatalf("expected a publish without override to succeed, got %v", err)
+	}
+	if sent != 1 || len(appendErrs) != 1 {
+		t.Errorf("expected the publish to be sent and the append failure reported, got %d requests and %v", sent, appendErrs)
+	}
+}
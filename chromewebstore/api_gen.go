@@ -0,0 +1,549 @@
+// Code generated by cwsgen from the Chrome Web Store discovery document. DO NOT EDIT.
+
+package chromewebstore
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// ItemState represents the state of a Chrome Web Store item revision.
+type ItemState string
+
+const (
+	// ItemStateUnspecified: Default value. This value is unused.
+	ItemStateUnspecified ItemState = "STATE_UNSPECIFIED"
+	// ItemStatePendingReview: The item is pending review.
+	ItemStatePendingReview ItemState = "PENDING_REVIEW"
+	// ItemStateStaged: The item has been approved and is ready to be published.
+	ItemStateStaged ItemState = "STAGED"
+	// ItemStatePublished: The item is published publicly.
+	ItemStatePublished ItemState = "PUBLISHED"
+	// ItemStatePublishedToTesters: The item is published to trusted testers.
+	ItemStatePublishedToTesters ItemState = "PUBLISHED_TO_TESTERS"
+	// ItemStateRejected: The item has been rejected for publishing.
+	ItemStateRejected ItemState = "REJECTED"
+	// ItemStateCancelled: The item submission has been cancelled.
+	ItemStateCancelled ItemState = "CANCELLED"
+)
+
+// PublishType specifies the type of publishing.
+type PublishType string
+
+const (
+	// PublishTypeUnspecified: Default value. This is the same as DEFAULT_PUBLISH.
+	PublishTypeUnspecified PublishType = "PUBLISH_TYPE_UNSPECIFIED"
+	// PublishTypeDefault: The submission will be published immediately after being
+	// approved.
+	PublishTypeDefault PublishType = "DEFAULT_PUBLISH"
+	// PublishTypeStaged: After approval the submission will be staged and can then
+	// be published by the developer.
+	PublishTypeStaged PublishType = "STAGED_PUBLISH"
+)
+
+// UploadState represents the state of an upload operation.
+type UploadState string
+
+const (
+	// UploadStateUnspecified: The default value.
+	UploadStateUnspecified UploadState = "UPLOAD_STATE_UNSPECIFIED"
+	// UploadStateSucceeded: The upload succeeded.
+	UploadStateSucceeded UploadState = "SUCCEEDED"
+	// UploadStateInProgress: The upload is currently being processed.
+	UploadStateInProgress UploadState = "IN_PROGRESS"
+	// UploadStateFailed: The upload failed.
+	UploadStateFailed UploadState = "FAILED"
+	// UploadStateNotFound: Used as the value of `lastAsyncUploadState` in a
+	// `fetchStatus` response indicating that an upload attempt was not found.
+	UploadStateNotFound UploadState = "NOT_FOUND"
+)
+
+// CancelSubmissionResponse: Response message for `CancelSubmission`. This is an
+// empty response.
+type CancelSubmissionResponse struct {
+	ServerResponse `json:"-"`
+}
+
+// DeployInfo: Deployment information for a specific release channel. Used in
+// publish requests.
+type DeployInfo struct {
+	// DeployPercentage: Required. The current deploy percentage for the release
+	// channel (nonnegative number between 0 and 100).
+	DeployPercentage int `json:"deployPercentage,omitempty"`
+}
+
+// DistributionChannel: Deployment information for a specific release channel.
+type DistributionChannel struct {
+	// DeployPercentage: The current deploy percentage for the release channel
+	// (nonnegative number between 0 and 100).
+	DeployPercentage int `json:"deployPercentage,omitempty"`
+	// CrxVersion: The extension version provided in the manifest of the uploaded
+	// package.
+	CrxVersion string `json:"crxVersion,omitempty"`
+}
+
+// ItemRevisionStatus: Details on the status of an item revision.
+type ItemRevisionStatus struct {
+	// State: Output only. Current state of the item.
+	State ItemState `json:"state,omitempty"`
+	// DistributionChannels: Details on the package of the item.
+	DistributionChannels []DistributionChannel `json:"distributionChannels,omitempty"`
+}
+
+// ItemStatus: Response message for `FetchItemStatus`.
+type ItemStatus struct {
+	ServerResponse `json:"-"`
+
+	// Name: The name of the requested item.
+	Name string `json:"name,omitempty"`
+	// ItemID: Output only. The ID of the item.
+	ItemID string `json:"itemId,omitempty"`
+	// PublicKey: The public key of the item, which may be generated by the store.
+	PublicKey string `json:"publicKey,omitempty"`
+	// Warned: If true, the item has been warned for a policy violation and will be
+	// taken down if not resolved.
+	Warned bool `json:"warned,omitempty"`
+	// TakenDown: If true, the item has been taken down for a policy violation.
+	TakenDown bool `json:"takenDown,omitempty"`
+	// LastAsyncUploadState: Output only. The state of the last async upload for an
+	// item. Only set when there has been an async upload for the item in the past.
+	LastAsyncUploadState UploadState `json:"lastAsyncUploadState,omitempty"`
+	// SubmittedItemRevisionStatus: Status of the current submitted revision of the
+	// item. Will be unset if there is no pending submission.
+	SubmittedItemRevisionStatus *ItemRevisionStatus `json:"submittedItemRevisionStatus,omitempty"`
+	// PublishedItemRevisionStatus: Status of the current published revision of the
+	// item. Will be unset if the item is not published.
+	PublishedItemRevisionStatus *ItemRevisionStatus `json:"publishedItemRevisionStatus,omitempty"`
+}
+
+// PublishRequest: Request message for PublishItem.
+type PublishRequest struct {
+	// PublishType: Optional. Use this to control if the item is published
+	// immediately on approval or staged for publishing in the future. Defaults to
+	// `DEFAULT_PUBLISH` if unset.
+	PublishType PublishType `json:"publishType,omitempty"`
+	// SkipReview: Optional. Whether to attempt to skip item review. The API will
+	// validate if the item qualifies and return a validation error if the item
+	// requires review.
+	SkipReview bool `json:"skipReview,omitempty"`
+	// DeployInfos: Optional. Additional deploy information including the desired
+	// initial percentage rollout. Defaults to the current value saved in the
+	// developer dashboard if unset.
+	DeployInfos []DeployInfo `json:"deployInfos,omitempty"`
+}
+
+// PublishResponse: Response message for `PublishItem`.
+type PublishResponse struct {
+	ServerResponse `json:"-"`
+
+	// Name: The name of the item that was submitted.
+	Name string `json:"name,omitempty"`
+	// ItemID: Output only. The ID of the item.
+	ItemID string `json:"itemId,omitempty"`
+	// State: Output only. The current state of the submission.
+	State ItemState `json:"state,omitempty"`
+}
+
+// SetPublishedDeployPercentageRequest: Request message for
+// `SetPublishedDeployPercentage`.
+type SetPublishedDeployPercentageRequest struct {
+	// DeployPercentage: Required. Unscaled percentage value for the published
+	// revision (nonnegative number between 0 and 100). It must be larger than the
+	// existing target percentage.
+	DeployPercentage int `json:"deployPercentage,omitempty"`
+}
+
+// SetPublishedDeployPercentageResponse: Response message for
+// `SetPublishedDeployPercentage`. This is an empty response.
+type SetPublishedDeployPercentageResponse struct {
+	ServerResponse `json:"-"`
+}
+
+// UploadResponse: Response message for `UploadItemPackage`.
+type UploadResponse struct {
+	ServerResponse `json:"-"`
+
+	// Name: The name of the item the package was uploaded to.
+	Name string `json:"name,omitempty"`
+	// ItemID: Output only. The ID of the item the package was uploaded to.
+	ItemID string `json:"itemId,omitempty"`
+	// UploadState: Output only. The state of the upload. If `upload_state` is
+	// `UPLOAD_IN_PROGRESS`, you can poll for updates using the fetchStatus method.
+	UploadState UploadState `json:"uploadState,omitempty"`
+	// CrxVersion: The extension version provided in the manifest of the uploaded
+	// package. This will not be set if the upload is still in progress
+	// (`upload_state` is `UPLOAD_IN_PROGRESS`).
+	CrxVersion string `json:"crxVersion,omitempty"`
+}
+
+// MediaService provides access to the media resource.
+type MediaService struct {
+	client *Client
+}
+
+// newMediaService creates a new MediaService.
+func newMediaService(c *Client) *MediaService {
+	s := &MediaService{client: c}
+	return s
+}
+
+// Upload returns an UploadCall. Upload a new package to an existing item.
+func (s *MediaService) Upload(name ItemName) *UploadCall {
+	return newUploadCall(s.client, name)
+}
+
+// PublishersService provides access to the publishers resource.
+type PublishersService struct {
+	client *Client
+
+	// Items provides access to the publishers.items resource.
+	Items *ItemsService
+}
+
+// newPublishersService creates a new PublishersService.
+func newPublishersService(c *Client) *PublishersService {
+	s := &PublishersService{client: c}
+	s.Items = newItemsService(c)
+	return s
+}
+
+// ItemsService provides access to the publishers.items resource.
+type ItemsService struct {
+	client *Client
+}
+
+// newItemsService creates a new ItemsService.
+func newItemsService(c *Client) *ItemsService {
+	s := &ItemsService{client: c}
+	return s
+}
+
+// CancelSubmission returns a CancelSubmissionCall. Cancel the current active
+// submission of an item if present. This can be used to cancel the review of a
+// pending submission.
+func (s *ItemsService) CancelSubmission(name ItemName) *CancelSubmissionCall {
+	return newCancelSubmissionCall(s.client, name)
+}
+
+// FetchStatus returns a FetchStatusCall. Fetch the status of an item.
+func (s *ItemsService) FetchStatus(name ItemName) *FetchStatusCall {
+	return newFetchStatusCall(s.client, name)
+}
+
+// Publish returns a PublishCall. Submit the item to be published in the store.
+// The item will be submitted for review unless `skip_review` is set to true, or
+// the item is staged from a previous submission with `publish_type` set to
+// `STAGED_PUBLISH`.
+func (s *ItemsService) Publish(name ItemName) *PublishCall {
+	return newPublishCall(s.client, name)
+}
+
+// SetPublishedDeployPercentage returns a SetPublishedDeployPercentageCall. Set
+// a higher target deploy percentage for the item's published revision. This
+// will be updated without the item being submitted for review. This is only
+// available to items with over 10,000 seven-day active users.
+func (s *ItemsService) SetPublishedDeployPercentage(name ItemName) *SetPublishedDeployPercentageCall {
+	return newSetPublishedDeployPercentageCall(s.client, name)
+}
+
+// CancelSubmissionCall represents a call to the
+// chromewebstore.publishers.items.cancelSubmission method.
+type CancelSubmissionCall struct {
+	call
+}
+
+// newCancelSubmissionCall creates a new CancelSubmissionCall.
+func newCancelSubmissionCall(client *Client, name ItemName) *CancelSubmissionCall {
+	c := &CancelSubmissionCall{
+		call: newCall(client, name),
+	}
+	return c
+}
+
+// Context sets the context for the request.
+func (c *CancelSubmissionCall) Context(ctx context.Context) *CancelSubmissionCall {
+	c.ctx = ctx
+	return c
+}
+
+// Fields selects the fields included in the response using the partial
+// response syntax. Fields not selected are left at their zero values.
+func (c *CancelSubmissionCall) Fields(fields ...string) *CancelSubmissionCall {
+	c.setFields(fields)
+	return c
+}
+
+// QuotaUser sets an arbitrary string identifying the user the request is
+// made for, so that quota is enforced per user.
+func (c *CancelSubmissionCall) QuotaUser(quotaUser string) *CancelSubmissionCall {
+	c.setQuotaUser(quotaUser)
+	return c
+}
+
+// PrettyPrint sets whether the server indents the response.
+func (c *CancelSubmissionCall) PrettyPrint(prettyPrint bool) *CancelSubmissionCall {
+	c.setPrettyPrint(prettyPrint)
+	return c
+}
+
+// Do executes the chromewebstore.publishers.items.cancelSubmission request.
+func (c *CancelSubmissionCall) Do() (*CancelSubmissionResponse, error) {
+	if err := c.runPreflight(); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/v2/%s:cancelSubmission", c.name)
+	urlStr := buildURL(c.client.baseURL, path, c.params)
+
+	resp, err := c.client.doRequest(c.ctx, http.MethodPost, urlStr, c.header, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result CancelSubmissionResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// FetchStatusCall represents a call to the
+// chromewebstore.publishers.items.fetchStatus method.
+type FetchStatusCall struct {
+	call
+}
+
+// newFetchStatusCall creates a new FetchStatusCall.
+func newFetchStatusCall(client *Client, name ItemName) *FetchStatusCall {
+	c := &FetchStatusCall{
+		call: newCall(client, name),
+	}
+	return c
+}
+
+// Context sets the context for the request.
+func (c *FetchStatusCall) Context(ctx context.Context) *FetchStatusCall {
+	c.ctx = ctx
+	return c
+}
+
+// Fields selects the fields included in the response using the partial
+// response syntax. Fields not selected are left at their zero values.
+func (c *FetchStatusCall) Fields(fields ...string) *FetchStatusCall {
+	c.setFields(fields)
+	return c
+}
+
+// QuotaUser sets an arbitrary string identifying the user the request is
+// made for, so that quota is enforced per user.
+func (c *FetchStatusCall) QuotaUser(quotaUser string) *FetchStatusCall {
+	c.setQuotaUser(quotaUser)
+	return c
+}
+
+// PrettyPrint sets whether the server indents the response.
+func (c *FetchStatusCall) PrettyPrint(prettyPrint bool) *FetchStatusCall {
+	c.setPrettyPrint(prettyPrint)
+	return c
+}
+
+// Projection sets the projection parameter. Optional. The revision to return.
+// Valid values are "DRAFT" or "PUBLISHED".
+func (c *FetchStatusCall) Projection(projection string) *FetchStatusCall {
+	c.params.Set("projection", projection)
+	return c
+}
+
+// Do executes the chromewebstore.publishers.items.fetchStatus request.
+func (c *FetchStatusCall) Do() (*ItemStatus, error) {
+	if err := c.runPreflight(); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/v2/%s:fetchStatus", c.name)
+	urlStr := buildURL(c.client.baseURL, path, c.params)
+
+	resp, err := c.client.doRequest(c.ctx, http.MethodGet, urlStr, c.header, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ItemStatus
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// PublishCall represents a call to the chromewebstore.publishers.items.publish
+// method.
+type PublishCall struct {
+	call
+	request *PublishRequest
+}
+
+// newPublishCall creates a new PublishCall.
+func newPublishCall(client *Client, name ItemName) *PublishCall {
+	c := &PublishCall{
+		call:    newCall(client, name),
+		request: &PublishRequest{},
+	}
+	return c
+}
+
+// Context sets the context for the request.
+func (c *PublishCall) Context(ctx context.Context) *PublishCall {
+	c.ctx = ctx
+	return c
+}
+
+// Fields selects the fields included in the response using the partial
+// response syntax. Fields not selected are left at their zero values.
+func (c *PublishCall) Fields(fields ...string) *PublishCall {
+	c.setFields(fields)
+	return c
+}
+
+// QuotaUser sets an arbitrary string identifying the user the request is
+// made for, so that quota is enforced per user.
+func (c *PublishCall) QuotaUser(quotaUser string) *PublishCall {
+	c.setQuotaUser(quotaUser)
+	return c
+}
+
+// PrettyPrint sets whether the server indents the response.
+func (c *PublishCall) PrettyPrint(prettyPrint bool) *PublishCall {
+	c.setPrettyPrint(prettyPrint)
+	return c
+}
+
+// PublishType sets the publishType field of the request. Optional. Use this to
+// control if the item is published immediately on approval or staged for
+// publishing in the future. Defaults to `DEFAULT_PUBLISH` if unset.
+func (c *PublishCall) PublishType(publishType PublishType) *PublishCall {
+	c.request.PublishType = publishType
+	return c
+}
+
+// SkipReview sets the skipReview field of the request. Optional. Whether to
+// attempt to skip item review. The API will validate if the item qualifies and
+// return a validation error if the item requires review.
+func (c *PublishCall) SkipReview(skipReview bool) *PublishCall {
+	c.request.SkipReview = skipReview
+	return c
+}
+
+// DeployInfos sets the deployInfos field of the request. Optional. Additional
+// deploy information including the desired initial percentage rollout. Defaults
+// to the current value saved in the developer dashboard if unset.
+func (c *PublishCall) DeployInfos(deployInfos []DeployInfo) *PublishCall {
+	c.request.DeployInfos = deployInfos
+	return c
+}
+
+// Do executes the chromewebstore.publishers.items.publish request.
+func (c *PublishCall) Do() (*PublishResponse, error) {
+	ctx, err := c.runPolicy(c.policyRequest())
+	if err != nil {
+		return nil, err
+	}
+	if err := c.runPreflight(); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/v2/%s:publish", c.name)
+	urlStr := buildURL(c.client.baseURL, path, c.params)
+
+	resp, err := c.client.doRequest(ctx, http.MethodPost, urlStr, c.header, c.request)
+	if err != nil {
+		return nil, err
+	}
+
+	var result PublishResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// SetPublishedDeployPercentageCall represents a call to the
+// chromewebstore.publishers.items.setPublishedDeployPercentage method.
+type SetPublishedDeployPercentageCall struct {
+	call
+}
+
+// newSetPublishedDeployPercentageCall creates a new
+// SetPublishedDeployPercentageCall.
+func newSetPublishedDeployPercentageCall(client *Client, name ItemName) *SetPublishedDeployPercentageCall {
+	c := &SetPublishedDeployPercentageCall{
+		call: newCall(client, name),
+	}
+	c.params.Set("deployPercentage", "100")
+	return c
+}
+
+// Context sets the context for the request.
+func (c *SetPublishedDeployPercentageCall) Context(ctx context.Context) *SetPublishedDeployPercentageCall {
+	c.ctx = ctx
+	return c
+}
+
+// Fields selects the fields included in the response using the partial
+// response syntax. Fields not selected are left at their zero values.
+func (c *SetPublishedDeployPercentageCall) Fields(fields ...string) *SetPublishedDeployPercentageCall {
+	c.setFields(fields)
+	return c
+}
+
+// QuotaUser sets an arbitrary string identifying the user the request is
+// made for, so that quota is enforced per user.
+func (c *SetPublishedDeployPercentageCall) QuotaUser(quotaUser string) *SetPublishedDeployPercentageCall {
+	c.setQuotaUser(quotaUser)
+	return c
+}
+
+// PrettyPrint sets whether the server indents the response.
+func (c *SetPublishedDeployPercentageCall) PrettyPrint(prettyPrint bool) *SetPublishedDeployPercentageCall {
+	c.setPrettyPrint(prettyPrint)
+	return c
+}
+
+// DeployPercentage sets the deployPercentage parameter. Required. Unscaled
+// percentage value for the published revision (nonnegative number between 0 and
+// 100). It must be larger than the existing target percentage.
+func (c *SetPublishedDeployPercentageCall) DeployPercentage(deployPercentage int) *SetPublishedDeployPercentageCall {
+	c.params.Set("deployPercentage", strconv.Itoa(deployPercentage))
+	return c
+}
+
+// Do executes the chromewebstore.publishers.items.setPublishedDeployPercentage
+// request.
+func (c *SetPublishedDeployPercentageCall) Do() (*SetPublishedDeployPercentageResponse, error) {
+	ctx, err := c.runPolicy(c.policyRequest())
+	if err != nil {
+		return nil, err
+	}
+	if err := c.runPreflight(); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/v2/%s:setPublishedDeployPercentage", c.name)
+	urlStr := buildURL(c.client.baseURL, path, c.params)
+
+	resp, err := c.client.doRequest(ctx, http.MethodPost, urlStr, c.header, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result SetPublishedDeployPercentageResponse
+	if err := parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,91 @@
+package chromewebstore
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// RequestIDHeader is the response header carrying the ID Google assigns to
+// a request. Include it when contacting support about a failed call.
+const RequestIDHeader = "X-Goog-Request-Id"
+
+// call holds the state shared by every call type: the target item, the
+// context, query parameters and per-call headers.
+type call struct {
+	client *Client
+	name   ItemName
+	ctx    context.Context
+	params url.Values
+	header http.Header
+	// preflight, if set, is run by Do before sending the request.
+	preflight func() error
+}
+
+// newCall creates a call for the item.
+func newCall(c *Client, name ItemName) call {
+	return call{
+		client: c,
+		name:   name,
+		ctx:    context.Background(),
+		params: make(url.Values),
+		header: make(http.Header),
+	}
+}
+
+// Header returns the HTTP headers sent with the request. Headers set by
+// the client, such as Content-Type and Accept, take precedence.
+func (c *call) Header() http.Header {
+	return c.header
+}
+
+// setFields sets the fields parameter selecting a partial response.
+func (c *call) setFields(fields []string) {
+	if len(fields) == 0 {
+		c.params.Del("fields")
+		return
+	}
+	c.params.Set("fields", strings.Join(fields, ","))
+}
+
+// setQuotaUser sets the quotaUser parameter.
+func (c *call) setQuotaUser(quotaUser string) {
+	c.params.Set("quotaUser", quotaUser)
+}
+
+// setPrettyPrint sets the prettyPrint parameter.
+func (c *call) setPrettyPrint(prettyPrint bool) {
+	c.params.Set("prettyPrint", strconv.FormatBool(prettyPrint))
+}
+
+// runPreflight runs the pre-flight check, if any.
+func (c *call) runPreflight() error {
+	if c.preflight == nil {
+		return nil
+	}
+	return c.preflight()
+}
+
+// ServerResponse describes the HTTP response a result was decoded from.
+// It is embedded in every response type and is not part of their JSON
+// encoding.
+type ServerResponse struct {
+	// HTTPStatusCode is the HTTP status code of the response.
+	HTTPStatusCode int
+	// Header contains the response headers.
+	Header http.Header
+}
+
+// RequestID returns the request ID assigned by the server, or "" if the
+// response did not include one.
+func (r ServerResponse) RequestID() string {
+	return r.Header.Get(RequestIDHeader)
+}
+
+// setServerResponse records the response a result was decoded from.
+func (r *ServerResponse) setServerResponse(resp *http.Response) {
+	r.HTTPStatusCode = resp.StatusCode
+	r.Header = resp.Header
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 const (
@@ -25,6 +26,17 @@ type Client struct {
 	baseURL string
 	// uploadBaseURL is the base URL for upload requests.
 	uploadBaseURL string
+	// limiter limits API requests. It is nil if requests are not limited.
+	limiter *RateLimiter
+	// uploadLimiter limits upload requests. It is nil if uploads are not limited.
+	uploadLimiter *RateLimiter
+	// requestTimeout bounds each API request, including reading the response.
+	requestTimeout time.Duration
+	// uploadTimeout bounds each upload request.
+	uploadTimeout time.Duration
+	// policy checks publishes and deploy percentage changes. It is nil if
+	// they are not checked.
+	policy Policy
 
 	// Publishers provides access to publishers resources.
 	Publishers *PublishersService
@@ -63,8 +75,56 @@ func (c *Client) SetUploadBaseURL(uploadBaseURL string) {
 	c.uploadBaseURL = uploadBaseURL
 }
 
-// doRequest performs an HTTP request and returns the response.
-func (c *Client) doRequest(ctx context.Context, method, urlStr string, body interface{}) (*http.Response, error) {
+// SetRateLimit limits requests to the API base URL to requestsPerSecond on
+// average with bursts of up to burst requests. Callers block until a
+// request may be made, and the rate is lowered temporarily after 429
+// responses. A requestsPerSecond of zero or less removes the limit.
+func (c *Client) SetRateLimit(requestsPerSecond float64, burst int) {
+	c.limiter = newRateLimiterOrNil(requestsPerSecond, burst)
+}
+
+// SetUploadRateLimit limits requests to the upload base URL like
+// SetRateLimit does for the API base URL.
+func (c *Client) SetUploadRateLimit(requestsPerSecond float64, burst int) {
+	c.uploadLimiter = newRateLimiterOrNil(requestsPerSecond, burst)
+}
+
+// SetRateLimiter sets the limiter for requests to the API base URL. Sharing
+// a limiter between clients makes them share a single budget. A nil
+// limiter removes the limit.
+func (c *Client) SetRateLimiter(l *RateLimiter) {
+	c.limiter = l
+}
+
+// SetUploadRateLimiter sets the limiter for requests to the upload base URL.
+// A nil limiter removes the limit.
+func (c *Client) SetUploadRateLimiter(l *RateLimiter) {
+	c.uploadLimiter = l
+}
+
+// SetRequestTimeout bounds every request to the API base URL, including
+// reading its response, independently of the call's context. Zero removes
+// the bound.
+func (c *Client) SetRequestTimeout(d time.Duration) {
+	c.requestTimeout = d
+}
+
+// SetUploadTimeout bounds every upload request like SetRequestTimeout does
+// for API requests. Zero removes the bound.
+func (c *Client) SetUploadTimeout(d time.Duration) {
+	c.uploadTimeout = d
+}
+
+func newRateLimiterOrNil(requestsPerSecond float64, burst int) *RateLimiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	return NewRateLimiter(requestsPerSecond, burst)
+}
+
+// doRequest performs an HTTP request with the given extra headers and
+// returns the response.
+func (c *Client) doRequest(ctx context.Context, method, urlStr string, header http.Header, body interface{}) (*http.Response, error) {
 	var bodyReader io.Reader
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
@@ -79,30 +139,82 @@ func (c *Client) doRequest(ctx context.Context, method, urlStr string, body inte
 		return nil, fmt.Errorf("chromewebstore: failed to create request: %w", err)
 	}
 
+	setHeader(req, header)
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
 	req.Header.Set("Accept", "application/json")
 
-	return c.httpClient.Do(req)
+	return c.do(req, c.limiter, c.requestTimeout)
 }
 
 // doRequestWithMedia performs an HTTP request with media upload.
-func (c *Client) doRequestWithMedia(ctx context.Context, method, urlStr string, media io.Reader, mediaType string) (*http.Response, error) {
+func (c *Client) doRequestWithMedia(ctx context.Context, method, urlStr string, header http.Header, media io.Reader, mediaType string) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, method, urlStr, media)
 	if err != nil {
 		return nil, fmt.Errorf("chromewebstore: failed to create request: %w", err)
 	}
 
+	setHeader(req, header)
 	if mediaType != "" {
 		req.Header.Set("Content-Type", mediaType)
 	}
 	req.Header.Set("Accept", "application/json")
 
-	return c.httpClient.Do(req)
+	return c.do(req, c.uploadLimiter, c.uploadTimeout)
 }
 
-// parseResponse parses the HTTP response into the target struct.
+// setHeader copies header into the request headers.
+func setHeader(req *http.Request, header http.Header) {
+	for key, values := range header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+}
+
+// do sends req after waiting for limiter, if set, and reports the response
+// status to it. A non-zero timeout bounds the request until its response
+// body is closed.
+func (c *Client) do(req *http.Request, limiter *RateLimiter, timeout time.Duration) (*http.Response, error) {
+	if limiter != nil {
+		if err := limiter.Wait(req.Context()); err != nil {
+			return nil, fmt.Errorf("chromewebstore: rate limit wait: %w", err)
+		}
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if timeout > 0 {
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(req.Context(), timeout)
+		req = req.WithContext(ctx)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if limiter != nil {
+		limiter.Observe(resp.StatusCode)
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases a request's timeout when its response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and releases the timeout.
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// parseResponse parses the HTTP response into the target struct. Targets
+// embedding ServerResponse also record the status code and headers.
 func parseResponse(resp *http.Response, target interface{}) error {
 	defer resp.Body.Close()
 
@@ -121,6 +233,9 @@ func parseResponse(resp *http.Response, target interface{}) error {
 			return fmt.Errorf("chromewebstore: failed to unmarshal response: %w", err)
 		}
 	}
+	if r, ok := target.(interface{ setServerResponse(*http.Response) }); ok {
+		r.setServerResponse(resp)
+	}
 
 	return nil
 }
@@ -1,9 +1,12 @@
 package chromewebstore
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestNewClient(t *testing.T) {
@@ -69,6 +72,26 @@ func TestSetUploadBaseURL(t *testing.T) {
 	}
 }
 
+func TestSetRequestTimeout(t *testing.T) {
+	server := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+
+	client := NewClient(nil)
+	client.SetBaseURL(server.URL)
+	client.SetRequestTimeout(20 * time.Millisecond)
+
+	_, err := client.Publishers.Items.FetchStatus(NewItemName("pub", "item")).Context(context.Background()).Do()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
 func newTestServer(handler http.HandlerFunc) *httptest.Server {
 	return httptest.NewServer(handler)
 }
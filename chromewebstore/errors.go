@@ -27,6 +27,8 @@ type APIError struct {
 	Body string
 	// Message provides a human-readable error message.
 	Message string
+	// Header contains the response headers.
+	Header http.Header
 }
 
 // Error returns the error message.
@@ -37,6 +39,12 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("chromewebstore: HTTP %d: %s", e.StatusCode, e.Status)
 }
 
+// RequestID returns the request ID assigned by the server, or "" if the
+// response did not include one.
+func (e *APIError) RequestID() string {
+	return e.Header.Get(RequestIDHeader)
+}
+
 // IsNotFound returns true if the error is a 404 Not Found error.
 func (e *APIError) IsNotFound() bool {
 	return e.StatusCode == http.StatusNotFound
@@ -63,6 +71,7 @@ func newAPIError(resp *http.Response, body []byte) *APIError {
 		StatusCode: resp.StatusCode,
 		Status:     resp.Status,
 		Body:       string(body),
+		Header:     resp.Header,
 	}
 
 	// Try to parse the error response as Google API error format
@@ -73,3 +82,22 @@ func newAPIError(resp *http.Response, body []byte) *APIError {
 
 	return apiErr
 }
+
+// DeployPercentageError is returned by pre-flight validation when a
+// requested deploy percentage is not accepted by the API.
+type DeployPercentageError struct {
+	// Current is the current published deploy percentage, or -1 if unknown.
+	Current int
+	// Requested is the requested deploy percentage.
+	Requested int
+	// Reason describes why the value was rejected.
+	Reason string
+}
+
+// Error returns the error message.
+func (e *DeployPercentageError) Error() string {
+	if e.Current < 0 {
+		return fmt.Sprintf("chromewebstore: invalid deploy percentage %d: %s", e.Requested, e.Reason)
+	}
+	return fmt.Sprintf("chromewebstore: invalid deploy percentage %d (current %d): %s", e.Requested, e.Current, e.Reason)
+}
@@ -0,0 +1,9 @@
+package chromewebstore
+
+// The API types, services and most Call builders are generated from the
+// checked-in discovery document. Update discovery.json and run go generate
+// to pick up new methods, fields and enum values. Calls that need more
+// than the document describes, such as media upload, are written by hand
+// and listed in codegen.json.
+
+//go:generate go run ../internal/codegen/cwsgen -discovery discovery.json -config codegen.json -o api_gen.go
@@ -0,0 +1,23 @@
+package chromewebstore
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/internal/codegen"
+)
+
+func TestGeneratedCodeUpToDate(t *testing.T) {
+	want, err := codegen.GenerateFiles("discovery.json", "codegen.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := os.ReadFile("api_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("api_gen.go is out of date; run go generate ./chromewebstore")
+	}
+}
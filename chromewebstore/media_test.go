@@ -1,12 +1,18 @@
 package chromewebstore
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
 	"encoding/json"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/pack"
 )
 
 func TestUpload(t *testing.T) {
@@ -104,3 +110,45 @@ func TestUploadWithCustomMediaType(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestUploadDirectory(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(`{"name": "x", "version": "1.0"}`), 0o644)
+	os.WriteFile(filepath.Join(dir, ".DS_Store"), []byte("junk"), 0o644)
+
+	server := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/zip" {
+			t.Errorf("expected Content-Type application/zip, got %s", r.Header.Get("Content-Type"))
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("failed to read body: %v", err)
+		}
+		zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		if err != nil {
+			t.Fatalf("expected a ZIP body: %v", err)
+		}
+		if len(zr.File) != 1 || zr.File[0].Name != "manifest.json" {
+			t.Errorf("unexpected entries %v", zr.File)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(UploadResponse{UploadState: UploadStateSucceeded})
+	})
+	defer server.Close()
+
+	client := NewClient(nil)
+	client.SetUploadBaseURL(server.URL)
+
+	itemName := NewItemName("test-publisher", "test-item")
+	resp, err := client.Media.Upload(itemName).Directory(dir, pack.Options{}).Do()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.UploadState != UploadStateSucceeded {
+		t.Errorf("expected upload state %s, got %s", UploadStateSucceeded, resp.UploadState)
+	}
+}
@@ -5,27 +5,31 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"net/url"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/pack"
+)
+
+const (
+	// MediaTypeZIP is the media type of ZIP packages.
+	MediaTypeZIP = "application/zip"
+	// MediaTypeCRX is the media type of signed CRX packages used for verified uploads.
+	MediaTypeCRX = "application/x-chrome-extension"
 )
 
 // UploadCall represents a call to upload an extension package.
 type UploadCall struct {
-	client    *Client
-	name      ItemName
-	ctx       context.Context
-	params    url.Values
+	call
 	media     io.Reader
 	mediaType string
+	dir       string
+	packOpts  pack.Options
 }
 
 // newUploadCall creates a new UploadCall.
 func newUploadCall(c *Client, name ItemName) *UploadCall {
 	return &UploadCall{
-		client:    c,
-		name:      name,
-		ctx:       context.Background(),
-		params:    make(url.Values),
-		mediaType: "application/zip",
+		call:      newCall(c, name),
+		mediaType: MediaTypeZIP,
 	}
 }
 
@@ -35,25 +39,63 @@ func (c *UploadCall) Context(ctx context.Context) *UploadCall {
 	return c
 }
 
+// Fields selects the fields included in the response using the partial
+// response syntax. Fields not selected are left at their zero values.
+func (c *UploadCall) Fields(fields ...string) *UploadCall {
+	c.setFields(fields)
+	return c
+}
+
+// QuotaUser sets an arbitrary string identifying the user the request is
+// made for, so that quota is enforced per user.
+func (c *UploadCall) QuotaUser(quotaUser string) *UploadCall {
+	c.setQuotaUser(quotaUser)
+	return c
+}
+
+// PrettyPrint sets whether the server indents the response.
+func (c *UploadCall) PrettyPrint(prettyPrint bool) *UploadCall {
+	c.setPrettyPrint(prettyPrint)
+	return c
+}
+
 // Media sets the media to upload and its content type.
 func (c *UploadCall) Media(media io.Reader, mediaType string) *UploadCall {
 	c.media = media
+	c.dir = ""
 	if mediaType != "" {
 		c.mediaType = mediaType
 	}
 	return c
 }
 
+// Directory sets an unpacked extension directory to upload. The directory
+// is packed with pack.Reader and streamed to the server without being
+// written to disk.
+func (c *UploadCall) Directory(dir string, opts pack.Options) *UploadCall {
+	c.dir = dir
+	c.packOpts = opts
+	c.media = nil
+	c.mediaType = MediaTypeZIP
+	return c
+}
+
 // Do executes the upload request.
 func (c *UploadCall) Do() (*UploadResponse, error) {
-	if c.media == nil {
+	media := c.media
+	if c.dir != "" {
+		r := pack.Reader(c.dir, c.packOpts)
+		defer r.Close()
+		media = r
+	}
+	if media == nil {
 		return nil, fmt.Errorf("chromewebstore: media is required for upload")
 	}
 
 	path := fmt.Sprintf("/v2/%s:upload", c.name)
 	urlStr := buildURL(c.client.uploadBaseURL, path, c.params)
 
-	resp, err := c.client.doRequestWithMedia(c.ctx, http.MethodPost, urlStr, c.media, c.mediaType)
+	resp, err := c.client.doRequestWithMedia(c.ctx, http.MethodPost, urlStr, c.header, media, c.mediaType)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,128 @@
+package chromewebstore
+
+import (
+	"context"
+	"strconv"
+)
+
+// PolicyOperation identifies an operation checked by a Policy.
+type PolicyOperation string
+
+const (
+	// PolicyOperationPublish is checked before PublishCall.Do sends its
+	// request.
+	PolicyOperationPublish PolicyOperation = "publish"
+	// PolicyOperationSetDeployPercentage is checked before
+	// SetPublishedDeployPercentageCall.Do sends its request.
+	PolicyOperationSetDeployPercentage PolicyOperation = "set-deploy-percentage"
+)
+
+// PolicyRequest describes an operation about to be sent.
+type PolicyRequest struct {
+	// Operation is the operation.
+	Operation PolicyOperation
+	// Item is the item the operation applies to.
+	Item ItemName
+	// Publish is the request body of PolicyOperationPublish.
+	Publish *PublishRequest
+	// DeployPercentage is the requested percentage of
+	// PolicyOperationSetDeployPercentage.
+	DeployPercentage int
+	// Override is the override attached to the call's context with
+	// WithPolicyOverride, or nil.
+	Override *PolicyOverride
+}
+
+// PolicyOverride asks a Policy to let an operation through despite
+// violating some of its rules.
+type PolicyOverride struct {
+	// Reason explains why the rules are overridden. It is recorded in the
+	// audit log.
+	Reason string `json:"reason"`
+	// Rules are the names of the rules overridden. If empty, every rule
+	// that allows overrides is overridden.
+	Rules []string `json:"rules,omitempty"`
+}
+
+// Policy decides whether publishes and deploy percentage changes may be
+// sent. It is checked by Do before the request is sent, and before
+// pre-flight validation.
+type Policy interface {
+	// Check returns an error explaining why req must not be sent, or the
+	// names of the rules whose violations were overridden by req.Override.
+	Check(ctx context.Context, req *PolicyRequest) (overridden []string, err error)
+}
+
+// PolicyFunc adapts a function to the Policy interface.
+type PolicyFunc func(ctx context.Context, req *PolicyRequest) ([]string, error)
+
+// Check calls f.
+func (f PolicyFunc) Check(ctx context.Context, req *PolicyRequest) ([]string, error) {
+	return f(ctx, req)
+}
+
+// SetPolicy sets the policy checked before publishes and deploy percentage
+// changes. A nil policy removes the check.
+func (c *Client) SetPolicy(p Policy) {
+	c.policy = p
+}
+
+type (
+	policyOverrideKey  struct{}
+	appliedOverrideKey struct{}
+)
+
+// WithPolicyOverride returns a context whose calls request override from
+// the client's policy.
+func WithPolicyOverride(ctx context.Context, override PolicyOverride) context.Context {
+	return context.WithValue(ctx, policyOverrideKey{}, override)
+}
+
+// PolicyOverrideFromContext returns the override set by WithPolicyOverride.
+func PolicyOverrideFromContext(ctx context.Context) (PolicyOverride, bool) {
+	o, ok := ctx.Value(policyOverrideKey{}).(PolicyOverride)
+	return o, ok
+}
+
+// AppliedPolicyOverride returns the override applied to the request made
+// with ctx, listing only the rules that were actually overridden. Do sets
+// it on the contexts of requests that the policy let through because of an
+// override, so that HTTP transports such as audit loggers can record it.
+func AppliedPolicyOverride(ctx context.Context) (PolicyOverride, bool) {
+	o, ok := ctx.Value(appliedOverrideKey{}).(PolicyOverride)
+	return o, ok
+}
+
+// runPolicy checks req against the client's policy, if any, and returns
+// the context of the request. When the policy overrides violations, the
+// applied override is attached to it; the call's own context is left
+// unchanged so that it is checked again by every Do.
+func (c *call) runPolicy(req *PolicyRequest) (context.Context, error) {
+	if c.client.policy == nil {
+		return c.ctx, nil
+	}
+	override, ok := PolicyOverrideFromContext(c.ctx)
+	if ok {
+		req.Override = &override
+	}
+	overridden, err := c.client.policy.Check(c.ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(overridden) > 0 {
+		return context.WithValue(c.ctx, appliedOverrideKey{}, PolicyOverride{Reason: override.Reason, Rules: overridden}), nil
+	}
+	return c.ctx, nil
+}
+
+// policyRequest describes the publish for the client's policy.
+func (c *PublishCall) policyRequest() *PolicyRequest {
+	return &PolicyRequest{Operation: PolicyOperationPublish, Item: c.name, Publish: c.request}
+}
+
+// policyRequest describes the deploy percentage change for the client's
+// policy.
+func (c *SetPublishedDeployPercentageCall) policyRequest() *PolicyRequest {
+	percentage, _ := strconv.Atoi(c.params.Get("deployPercentage"))
+	return &PolicyRequest{Operation: PolicyOperationSetDeployPercentage, Item: c.name, DeployPercentage: percentage}
+}
@@ -0,0 +1,109 @@
+package chromewebstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+// contextTransport records the contexts of the requests sent through it.
+type contextTransport struct {
+	contexts []context.Context
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.contexts = append(t.contexts, req.Context())
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestPolicy(t *testing.T) {
+	requests := 0
+	server := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+
+	errBlocked := errors.New("blocked")
+	var checked []*PolicyRequest
+	transport := &contextTransport{}
+	client := NewClient(&http.Client{Transport: transport})
+	client.SetBaseURL(server.URL)
+	client.SetPolicy(PolicyFunc(func(ctx context.Context, req *PolicyRequest) ([]string, error) {
+		checked = append(checked, req)
+		if req.Override != nil {
+			return []string{"no-fridays"}, nil
+		}
+		return nil, errBlocked
+	}))
+	name := NewItemName("pub", "item")
+
+	_, err := client.Publishers.Items.Publish(name).PublishType(PublishTypeStaged).SkipReview(true).Do()
+	if !errors.Is(err, errBlocked) {
+		t.Fatalf("expected the policy error, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected no request to be sent, got %d", requests)
+	}
+	if req := checked[0]; req.Operation != PolicyOperationPublish || req.Item != name || req.Publish.PublishType != PublishTypeStaged || !req.Publish.SkipReview {
+		t.Errorf("unexpected policy request %+v", req)
+	}
+
+	ctx := WithPolicyOverride(context.Background(), PolicyOverride{Reason: "hotfix"})
+	if _, err := client.Publishers.Items.SetPublishedDeployPercentage(name).Context(ctx).DeployPercentage(40).Do(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if req := checked[1]; req.Operation != PolicyOperationSetDeployPercentage || req.DeployPercentage != 40 || req.Override.Reason != "hotfix" {
+		t.Errorf("unexpected policy request %+v", req)
+	}
+	applied, ok := AppliedPolicyOverride(transport.contexts[0])
+	if !ok || applied.Reason != "hotfix" || !slices.Equal(applied.Rules, []string{"no-fridays"}) {
+		t.Errorf("expected the applied override on the request context, got %+v", applied)
+	}
+
+	if _, err := client.Publishers.Items.FetchStatus(name).Do(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(checked) != 2 {
+		t.Errorf("expected fetchStatus not to be checked, got %d checks", len(checked))
+	}
+}
+
+func TestPolicyRepeatedDo(t *testing.T) {
+	server := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+
+	violating := true
+	transport := &contextTransport{}
+	client := NewClient(&http.Client{Transport: transport})
+	client.SetBaseURL(server.URL)
+	client.SetPolicy(PolicyFunc(func(ctx context.Context, req *PolicyRequest) ([]string, error) {
+		if violating {
+			return []string{"no-fridays"}, nil
+		}
+		return nil, nil
+	}))
+
+	ctx := WithPolicyOverride(context.Background(), PolicyOverride{Reason: "hotfix"})
+	call := client.Publishers.Items.Publish(NewItemName("pub", "item")).Context(ctx)
+	if _, err := call.Do(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	violating = false
+	if _, err := call.Do(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, ok := AppliedPolicyOverride(transport.contexts[0]); !ok {
+		t.Error("expected the applied override on the first request")
+	}
+	if applied, ok := AppliedPolicyOverride(transport.contexts[1]); ok {
+		t.Errorf("expected no applied override on the second request, got %+v", applied)
+	}
+}
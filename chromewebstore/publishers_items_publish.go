@@ -1,70 +1,37 @@
 package chromewebstore
 
-import (
-	"context"
-	"fmt"
-	"net/http"
-	"net/url"
-)
-
-// PublishCall represents a call to publish an item.
-type PublishCall struct {
-	client  *Client
-	name    ItemName
-	ctx     context.Context
-	params  url.Values
-	request *PublishRequest
-}
-
-// newPublishCall creates a new PublishCall.
-func newPublishCall(c *Client, name ItemName) *PublishCall {
-	return &PublishCall{
-		client:  c,
-		name:    name,
-		ctx:     context.Background(),
-		params:  make(url.Values),
-		request: &PublishRequest{},
-	}
-}
-
-// Context sets the context for the request.
-func (c *PublishCall) Context(ctx context.Context) *PublishCall {
-	c.ctx = ctx
-	return c
-}
-
-// PublishType sets the publish type (IMMEDIATE or STAGED).
-func (c *PublishCall) PublishType(publishType PublishType) *PublishCall {
-	c.request.PublishType = publishType
-	return c
-}
-
-// SkipReview sets whether to attempt bypassing review.
-func (c *PublishCall) SkipReview(skip bool) *PublishCall {
-	c.request.SkipReview = skip
-	return c
-}
-
 // DeployPercentage sets the deploy percentage for staged rollout.
 func (c *PublishCall) DeployPercentage(percentage int) *PublishCall {
 	c.request.DeployInfos = []DeployInfo{{DeployPercentage: percentage}}
 	return c
 }
 
-// Do executes the publish request.
-func (c *PublishCall) Do() (*PublishResponse, error) {
-	path := fmt.Sprintf("/v2/%s:publish", c.name)
-	urlStr := buildURL(c.client.baseURL, path, c.params)
-
-	resp, err := c.client.doRequest(c.ctx, http.MethodPost, urlStr, c.request)
-	if err != nil {
-		return nil, err
+// Validate enables pre-flight validation. When enabled, Do returns a
+// *DeployPercentageError without sending the request if the deploy
+// percentage is out of range.
+func (c *PublishCall) Validate(validate bool) *PublishCall {
+	c.preflight = nil
+	if validate {
+		c.preflight = c.validateDeployInfos
 	}
+	return c
+}
 
-	var result PublishResponse
-	if err := parseResponse(resp, &result); err != nil {
-		return nil, err
+// Check runs the policy check and pre-flight validation of Do without
+// sending the request, for example to vet a publish scheduled for later.
+func (c *PublishCall) Check() error {
+	if _, err := c.runPolicy(c.policyRequest()); err != nil {
+		return err
 	}
+	return c.runPreflight()
+}
 
-	return &result, nil
+// validateDeployInfos checks the requested deploy percentages.
+func (c *PublishCall) validateDeployInfos() error {
+	for _, info := range c.request.DeployInfos {
+		if err := checkDeployPercentageRange(info.DeployPercentage); err != nil {
+			return err
+		}
+	}
+	return nil
 }
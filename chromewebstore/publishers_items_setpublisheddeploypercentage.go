@@ -1,61 +1,72 @@
 package chromewebstore
 
-import (
-	"context"
-	"fmt"
-	"net/http"
-	"net/url"
-)
-
-// SetPublishedDeployPercentageCall represents a call to set the deploy percentage.
-type SetPublishedDeployPercentageCall struct {
-	client           *Client
-	name             ItemName
-	ctx              context.Context
-	params           url.Values
-	deployPercentage int
-}
+import "strconv"
 
-// newSetPublishedDeployPercentageCall creates a new SetPublishedDeployPercentageCall.
-func newSetPublishedDeployPercentageCall(c *Client, name ItemName) *SetPublishedDeployPercentageCall {
-	return &SetPublishedDeployPercentageCall{
-		client:           c,
-		name:             name,
-		ctx:              context.Background(),
-		params:           make(url.Values),
-		deployPercentage: 100,
+// Validate enables pre-flight validation. When enabled, Do fetches the
+// current published deploy percentage and returns a *DeployPercentageError
+// without sending the request if the requested value is out of range or
+// does not exceed the current value. When disabled, only the range is
+// checked.
+func (c *SetPublishedDeployPercentageCall) Validate(validate bool) *SetPublishedDeployPercentageCall {
+	c.preflight = c.validateDeployPercentageRange
+	if validate {
+		c.preflight = c.validateDeployPercentage
 	}
-}
-
-// Context sets the context for the request.
-func (c *SetPublishedDeployPercentageCall) Context(ctx context.Context) *SetPublishedDeployPercentageCall {
-	c.ctx = ctx
 	return c
 }
 
-// DeployPercentage sets the deploy percentage (0-100).
-func (c *SetPublishedDeployPercentageCall) DeployPercentage(percent int) *SetPublishedDeployPercentageCall {
-	c.deployPercentage = percent
-	return c
+// Check runs the policy check and pre-flight validation of Do without
+// sending the request, for example to vet a change scheduled for later.
+func (c *SetPublishedDeployPercentageCall) Check() error {
+	if _, err := c.runPolicy(c.policyRequest()); err != nil {
+		return err
+	}
+	return c.runPreflight()
 }
 
-// Do executes the set deploy percentage request.
-func (c *SetPublishedDeployPercentageCall) Do() (*SetPublishedDeployPercentageResponse, error) {
-	path := fmt.Sprintf("/v2/%s:setPublishedDeployPercentage", c.name)
-
-	c.params.Set("deployPercentage", fmt.Sprintf("%d", c.deployPercentage))
+// validateDeployPercentageRange checks that the requested deploy
+// percentage is within 0-100.
+func (c *SetPublishedDeployPercentageCall) validateDeployPercentageRange() error {
+	requested, err := strconv.Atoi(c.params.Get("deployPercentage"))
+	if err != nil {
+		return &DeployPercentageError{Current: -1, Requested: requested, Reason: "must be an integer"}
+	}
+	return checkDeployPercentageRange(requested)
+}
 
-	urlStr := buildURL(c.client.baseURL, path, c.params)
+// validateDeployPercentage checks the requested deploy percentage against
+// the current published deploy percentage.
+func (c *SetPublishedDeployPercentageCall) validateDeployPercentage() error {
+	if err := c.validateDeployPercentageRange(); err != nil {
+		return err
+	}
+	requested, _ := strconv.Atoi(c.params.Get("deployPercentage"))
 
-	resp, err := c.client.doRequest(c.ctx, http.MethodPost, urlStr, nil)
+	fetch := newFetchStatusCall(c.client, c.name).Context(c.ctx)
+	fetch.header = c.header
+	status, err := fetch.Do()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var result SetPublishedDeployPercentageResponse
-	if err := parseResponse(resp, &result); err != nil {
-		return nil, err
+	current, ok := status.PublishedDeployPercentage()
+	if !ok {
+		return &DeployPercentageError{Current: -1, Requested: requested, Reason: "item has no published revision"}
+	}
+	if requested == current {
+		return &DeployPercentageError{Current: current, Requested: requested, Reason: "deploy percentage is unchanged"}
+	}
+	if requested < current {
+		return &DeployPercentageError{Current: current, Requested: requested, Reason: "deploy percentage can only be increased"}
 	}
 
-	return &result, nil
+	return nil
+}
+
+// checkDeployPercentageRange checks that percentage is within 0-100.
+func checkDeployPercentageRange(percentage int) error {
+	if percentage < 0 || percentage > 100 {
+		return &DeployPercentageError{Current: -1, Requested: percentage, Reason: "must be between 0 and 100"}
+	}
+	return nil
 }
@@ -3,6 +3,7 @@ package chromewebstore
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 	"testing"
@@ -232,6 +233,74 @@ func TestAPIError(t *testing.T) {
 	}
 }
 
+func TestCallOptions(t *testing.T) {
+	server := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("fields") != "itemId,publishedItemRevisionStatus(state)" {
+			t.Errorf("unexpected fields %q", query.Get("fields"))
+		}
+		if query.Get("quotaUser") != "user-1" && query.Get("quotaUser") != "fail" {
+			t.Errorf("expected quotaUser user-1, got %q", query.Get("quotaUser"))
+		}
+		if query.Get("prettyPrint") != "false" {
+			t.Errorf("expected prettyPrint false, got %q", query.Get("prettyPrint"))
+		}
+		if r.Header.Get("X-Trace") != "abc" {
+			t.Errorf("expected X-Trace header abc, got %q", r.Header.Get("X-Trace"))
+		}
+		if r.Header.Get("Accept") != "application/json" {
+			t.Errorf("expected client Accept header to take precedence, got %q", r.Header.Get("Accept"))
+		}
+
+		w.Header().Set(RequestIDHeader, "req-123")
+		if query.Get("quotaUser") == "fail" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"itemId": "test-item"}`))
+	})
+	defer server.Close()
+
+	client := NewClient(nil)
+	client.SetBaseURL(server.URL)
+
+	itemName := NewItemName("test-publisher", "test-item")
+	call := client.Publishers.Items.FetchStatus(itemName).
+		Fields("itemId", "publishedItemRevisionStatus(state)").
+		QuotaUser("user-1").
+		PrettyPrint(false)
+	call.Header().Set("X-Trace", "abc")
+	call.Header().Set("Accept", "text/plain")
+
+	status, err := call.Do()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.HTTPStatusCode != http.StatusOK {
+		t.Errorf("expected status code 200, got %d", status.HTTPStatusCode)
+	}
+	if status.RequestID() != "req-123" {
+		t.Errorf("expected request ID req-123, got %q", status.RequestID())
+	}
+
+	data, err := json.Marshal(status)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `{"itemId":"test-item"}` {
+		t.Errorf("expected server response to be omitted from JSON, got %s", data)
+	}
+
+	_, err = call.QuotaUser("fail").Do()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.RequestID() != "req-123" {
+		t.Errorf("expected error request ID req-123, got %q", apiErr.RequestID())
+	}
+}
+
 func TestItemName(t *testing.T) {
 	name := NewItemName("my-publisher", "my-item")
 	expected := "publishers/my-publisher/items/my-item"
@@ -239,4 +308,156 @@ func TestItemName(t *testing.T) {
 	if name.String() != expected {
 		t.Errorf("expected %s, got %s", expected, name.String())
 	}
+	if name.ItemID() != "my-item" {
+		t.Errorf("expected item ID my-item, got %s", name.ItemID())
+	}
+}
+
+func TestSetPublishedDeployPercentageValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested int
+		wantErr   bool
+	}{
+		{"increase", 50, false},
+		{"no-op", 20, true},
+		{"decrease", 10, true},
+		{"out of range", 101, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent := false
+			server := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Path, ":setPublishedDeployPercentage") {
+					sent = true
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(ItemStatus{
+					PublishedItemRevisionStatus: &ItemRevisionStatus{
+						State:                ItemStatePublished,
+						DistributionChannels: []DistributionChannel{{DeployPercentage: 20, CrxVersion: "1.0.0"}},
+					},
+				})
+			})
+			defer server.Close()
+
+			client := NewClient(nil)
+			client.SetBaseURL(server.URL)
+
+			itemName := NewItemName("test-publisher", "test-item")
+			_, err := client.Publishers.Items.SetPublishedDeployPercentage(itemName).
+				DeployPercentage(tt.requested).
+				Validate(true).
+				Do()
+
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !sent {
+					t.Error("expected request to be sent")
+				}
+				return
+			}
+
+			dpErr, ok := err.(*DeployPercentageError)
+			if !ok {
+				t.Fatalf("expected *DeployPercentageError, got %T", err)
+			}
+			if dpErr.Requested != tt.requested {
+				t.Errorf("expected requested %d, got %d", tt.requested, dpErr.Requested)
+			}
+			if sent {
+				t.Error("expected request not to be sent")
+			}
+		})
+	}
+}
+
+func TestSetPublishedDeployPercentageValidateDisabled(t *testing.T) {
+	var sent []string
+	server := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.URL.Query().Get("deployPercentage"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+
+	client := NewClient(nil)
+	client.SetBaseURL(server.URL)
+	itemName := NewItemName("test-publisher", "test-item")
+
+	_, err := client.Publishers.Items.SetPublishedDeployPercentage(itemName).DeployPercentage(150).Validate(false).Do()
+	if _, ok := err.(*DeployPercentageError); !ok {
+		t.Fatalf("expected *DeployPercentageError, got %v", err)
+	}
+	if _, err := client.Publishers.Items.SetPublishedDeployPercentage(itemName).DeployPercentage(10).Validate(false).Do(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(sent, ",") != "10" {
+		t.Errorf("expected only the in-range request to be sent without fetching the status, got %v", sent)
+	}
+}
+
+func TestPublishValidate(t *testing.T) {
+	client := NewClient(nil)
+
+	itemName := NewItemName("test-publisher", "test-item")
+	_, err := client.Publishers.Items.Publish(itemName).DeployPercentage(150).Validate(true).Do()
+
+	if _, ok := err.(*DeployPercentageError); !ok {
+		t.Fatalf("expected *DeployPercentageError, got %v", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	var paths []string
+	server := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"publishedItemRevisionStatus":{"state":"PUBLISHED","distributionChannels":[{"crxVersion":"1.0","deployPercentage":50}]}}`))
+	})
+	defer server.Close()
+
+	client := NewClient(nil)
+	client.SetBaseURL(server.URL)
+	itemName := NewItemName("test-publisher", "test-item")
+
+	if err := client.Publishers.Items.SetPublishedDeployPercentage(itemName).DeployPercentage(80).Validate(true).Check(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	var perr *DeployPercentageError
+	if err := client.Publishers.Items.SetPublishedDeployPercentage(itemName).DeployPercentage(20).Validate(true).Check(); !errors.As(err, &perr) || perr.Current != 50 {
+		t.Errorf("expected *DeployPercentageError against 50%%, got %v", err)
+	}
+	if err := client.Publishers.Items.Publish(itemName).DeployPercentage(150).Validate(true).Check(); !errors.As(err, &perr) {
+		t.Errorf("expected *DeployPercentageError, got %v", err)
+	}
+
+	errBlocked := errors.New("blocked")
+	client.SetPolicy(PolicyFunc(func(ctx context.Context, req *PolicyRequest) ([]string, error) {
+		return nil, errBlocked
+	}))
+	if err := client.Publishers.Items.Publish(itemName).Check(); !errors.Is(err, errBlocked) {
+		t.Errorf("expected the policy error, got %v", err)
+	}
+
+	for _, path := range paths {
+		if !strings.HasSuffix(path, ":fetchStatus") {
+			t.Errorf("expected only status fetches, got %s", path)
+		}
+	}
+}
+
+func TestValidateItemID(t *testing.T) {
+	if err := ValidateItemID("knldjmfmopnpolahpmmgbagdohdnhkik"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, id := range []string{"", "test-item", "knldjmfmopnpolahpmmgbagdohdnhki", "knldjmfmopnpolahpmmgbagdohdnhkiz"} {
+		if err := ValidateItemID(id); err == nil {
+			t.Errorf("expected %q to be invalid", id)
+		}
+	}
 }
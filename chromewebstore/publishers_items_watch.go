@@ -0,0 +1,258 @@
+package chromewebstore
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strconv"
+	"time"
+)
+
+// DefaultWatchInterval is the default polling interval of WatchCall.
+const DefaultWatchInterval = time.Minute
+
+// ChangeType identifies the kind of change reported by a ChangeEvent.
+type ChangeType string
+
+const (
+	// ChangeInitial reports the first status observed for an item.
+	ChangeInitial ChangeType = "INITIAL"
+	// ChangeState reports a change of a revision's state.
+	ChangeState ChangeType = "STATE"
+	// ChangeVersion reports a change of a revision's CRX version.
+	ChangeVersion ChangeType = "VERSION"
+	// ChangeDeployPercentage reports a change of a revision's deploy percentage.
+	ChangeDeployPercentage ChangeType = "DEPLOY_PERCENTAGE"
+	// ChangeWarned reports a change of the Warned flag.
+	ChangeWarned ChangeType = "WARNED"
+	// ChangeTakenDown reports a change of the TakenDown flag.
+	ChangeTakenDown ChangeType = "TAKEN_DOWN"
+	// ChangeUploadState reports a change of LastAsyncUploadState.
+	ChangeUploadState ChangeType = "UPLOAD_STATE"
+)
+
+const (
+	// RevisionSubmitted identifies the submitted item revision.
+	RevisionSubmitted = "submitted"
+	// RevisionPublished identifies the published item revision.
+	RevisionPublished = "published"
+)
+
+// ChangeEvent is a change between two consecutive statuses of an item.
+type ChangeEvent struct {
+	// Item is the item that changed.
+	Item ItemName `json:"item"`
+	// Type is the kind of change.
+	Type ChangeType `json:"type"`
+	// Revision is RevisionSubmitted or RevisionPublished for revision
+	// changes, and empty otherwise.
+	Revision string `json:"revision,omitempty"`
+	// Old is the previous value, empty if unset.
+	Old string `json:"old,omitempty"`
+	// New is the current value, empty if unset.
+	New string `json:"new,omitempty"`
+	// Time is when the change was observed.
+	Time time.Time `json:"time"`
+	// Status is the status in which the change was observed.
+	Status *ItemStatus `json:"status,omitempty"`
+}
+
+// String returns a human-readable description of the event.
+func (e ChangeEvent) String() string {
+	if e.Type == ChangeInitial {
+		return fmt.Sprintf("%s: watching", e.Item)
+	}
+	subject := string(e.Type)
+	if e.Revision != "" {
+		subject = e.Revision + " " + subject
+	}
+	return fmt.Sprintf("%s: %s %s -> %s", e.Item, subject, valueOrNone(e.Old), valueOrNone(e.New))
+}
+
+// valueOrNone returns s, or "(none)" if s is empty.
+func valueOrNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+// DiffStatus returns the changes from old to new. Both must be non-nil.
+func DiffStatus(name ItemName, old, new *ItemStatus, now time.Time) []ChangeEvent {
+	var events []ChangeEvent
+	add := func(t ChangeType, revision, o, n string) {
+		if o != n {
+			events = append(events, ChangeEvent{Item: name, Type: t, Revision: revision, Old: o, New: n, Time: now, Status: new})
+		}
+	}
+
+	for _, rev := range []struct {
+		name     string
+		old, new *ItemRevisionStatus
+	}{
+		{RevisionSubmitted, old.SubmittedItemRevisionStatus, new.SubmittedItemRevisionStatus},
+		{RevisionPublished, old.PublishedItemRevisionStatus, new.PublishedItemRevisionStatus},
+	} {
+		add(ChangeState, rev.name, revisionState(rev.old), revisionState(rev.new))
+		add(ChangeVersion, rev.name, revisionVersion(rev.old), revisionVersion(rev.new))
+		add(ChangeDeployPercentage, rev.name, revisionDeployPercentage(rev.old), revisionDeployPercentage(rev.new))
+	}
+
+	add(ChangeWarned, "", strconv.FormatBool(old.Warned), strconv.FormatBool(new.Warned))
+	add(ChangeTakenDown, "", strconv.FormatBool(old.TakenDown), strconv.FormatBool(new.TakenDown))
+	add(ChangeUploadState, "", string(old.LastAsyncUploadState), string(new.LastAsyncUploadState))
+
+	return events
+}
+
+func revisionState(r *ItemRevisionStatus) string {
+	if r == nil {
+		return ""
+	}
+	return string(r.State)
+}
+
+func revisionVersion(r *ItemRevisionStatus) string {
+	if r == nil || len(r.DistributionChannels) == 0 {
+		return ""
+	}
+	return r.DistributionChannels[0].CrxVersion
+}
+
+func revisionDeployPercentage(r *ItemRevisionStatus) string {
+	if r == nil || len(r.DistributionChannels) == 0 {
+		return ""
+	}
+	return strconv.Itoa(r.DistributionChannels[0].DeployPercentage)
+}
+
+// WatchCall represents a call to watch one or more items for status changes.
+type WatchCall struct {
+	client   *Client
+	names    []ItemName
+	ctx      context.Context
+	interval time.Duration
+	onError  func(item ItemName, err error)
+}
+
+// Watch returns a WatchCall for polling one or more items and reporting
+// changes between consecutive statuses.
+func (s *ItemsService) Watch(names ...ItemName) *WatchCall {
+	return newWatchCall(s.client, names)
+}
+
+// newWatchCall creates a new WatchCall.
+func newWatchCall(c *Client, names []ItemName) *WatchCall {
+	return &WatchCall{
+		client:   c,
+		names:    names,
+		ctx:      context.Background(),
+		interval: DefaultWatchInterval,
+	}
+}
+
+// Context sets the context for the watch. Watching stops when it is done.
+func (c *WatchCall) Context(ctx context.Context) *WatchCall {
+	c.ctx = ctx
+	return c
+}
+
+// Interval sets the polling interval. A non-positive interval selects
+// DefaultWatchInterval.
+func (c *WatchCall) Interval(d time.Duration) *WatchCall {
+	if d <= 0 {
+		d = DefaultWatchInterval
+	}
+	c.interval = d
+	return c
+}
+
+// OnError sets a function called by Do with the errors of failed polls,
+// such as expired credentials. Do skips failed polls without it.
+func (c *WatchCall) OnError(f func(item ItemName, err error)) *WatchCall {
+	c.onError = f
+	return c
+}
+
+// Do starts watching and returns a channel of change events. The channel
+// is closed when the context is done. Errors of failed polls are passed to
+// the OnError function, if any; use Seq to receive them with the events.
+func (c *WatchCall) Do() (<-chan ChangeEvent, error) {
+	if len(c.names) == 0 {
+		return nil, fmt.Errorf("chromewebstore: at least one item is required to watch")
+	}
+
+	ch := make(chan ChangeEvent)
+	go func() {
+		defer close(ch)
+		for event, err := range c.Seq() {
+			if err != nil {
+				if c.onError != nil {
+					c.onError(event.Item, err)
+				}
+				continue
+			}
+			select {
+			case ch <- event:
+			case <-c.ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// Seq returns an iterator over change events and polling errors. Iteration
+// ends when the context is done or the caller stops.
+func (c *WatchCall) Seq() iter.Seq2[ChangeEvent, error] {
+	return func(yield func(ChangeEvent, error) bool) {
+		if len(c.names) == 0 {
+			yield(ChangeEvent{}, fmt.Errorf("chromewebstore: at least one item is required to watch"))
+			return
+		}
+
+		last := make(map[ItemName]*ItemStatus, len(c.names))
+		interval := c.interval
+		if interval <= 0 {
+			interval = DefaultWatchInterval
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			for _, name := range c.names {
+				status, err := newFetchStatusCall(c.client, name).Context(c.ctx).Do()
+				if c.ctx.Err() != nil {
+					return
+				}
+				if err != nil {
+					if !yield(ChangeEvent{Item: name, Time: time.Now()}, err) {
+						return
+					}
+					continue
+				}
+
+				now := time.Now()
+				var events []ChangeEvent
+				if prev, ok := last[name]; ok {
+					events = DiffStatus(name, prev, status, now)
+				} else {
+					events = []ChangeEvent{{Item: name, Type: ChangeInitial, Time: now, Status: status}}
+				}
+				last[name] = status
+
+				for _, event := range events {
+					if !yield(event, nil) {
+						return
+					}
+				}
+			}
+
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+}
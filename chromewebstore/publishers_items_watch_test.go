@@ -0,0 +1,194 @@
+package chromewebstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDiffStatus(t *testing.T) {
+	name := NewItemName("test-publisher", "test-item")
+	old := &ItemStatus{
+		SubmittedItemRevisionStatus: &ItemRevisionStatus{
+			State:                ItemStatePendingReview,
+			DistributionChannels: []DistributionChannel{{DeployPercentage: 10, CrxVersion: "1.1"}},
+		},
+		PublishedItemRevisionStatus: &ItemRevisionStatus{
+			State:                ItemStatePublished,
+			DistributionChannels: []DistributionChannel{{DeployPercentage: 100, CrxVersion: "1.0"}},
+		},
+	}
+	new := &ItemStatus{
+		Warned:               true,
+		LastAsyncUploadState: UploadStateSucceeded,
+		PublishedItemRevisionStatus: &ItemRevisionStatus{
+			State:                ItemStatePublished,
+			DistributionChannels: []DistributionChannel{{DeployPercentage: 10, CrxVersion: "1.1"}},
+		},
+	}
+
+	events := DiffStatus(name, old, new, time.Now())
+
+	got := map[string]ChangeEvent{}
+	for _, e := range events {
+		got[e.Revision+"/"+string(e.Type)] = e
+	}
+
+	want := map[string][2]string{
+		"submitted/STATE":             {"PENDING_REVIEW", ""},
+		"submitted/VERSION":           {"1.1", ""},
+		"submitted/DEPLOY_PERCENTAGE": {"10", ""},
+		"published/VERSION":           {"1.0", "1.1"},
+		"published/DEPLOY_PERCENTAGE": {"100", "10"},
+		"/WARNED":                     {"false", "true"},
+		"/UPLOAD_STATE":               {"", "SUCCEEDED"},
+	}
+	if len(events) != len(want) {
+		t.Errorf("expected %d events, got %d: %+v", len(want), len(events), events)
+	}
+	for key, values := range want {
+		e, ok := got[key]
+		if !ok {
+			t.Errorf("missing event %s", key)
+			continue
+		}
+		if e.Old != values[0] || e.New != values[1] {
+			t.Errorf("%s: expected %q -> %q, got %q -> %q", key, values[0], values[1], e.Old, e.New)
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	states := []ItemState{ItemStatePendingReview, ItemStatePendingReview, ItemStatePublished}
+	var mu sync.Mutex
+	calls := 0
+
+	server := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		state := states[min(calls, len(states)-1)]
+		calls++
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ItemStatus{
+			SubmittedItemRevisionStatus: &ItemRevisionStatus{State: state},
+		})
+	})
+	defer server.Close()
+
+	client := NewClient(nil)
+	client.SetBaseURL(server.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	itemName := NewItemName("test-publisher", "test-item")
+	events, err := client.Publishers.Items.Watch(itemName).Context(ctx).Interval(10 * time.Millisecond).Do()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first := <-events
+	if first.Type != ChangeInitial {
+		t.Errorf("expected initial event, got %+v", first)
+	}
+
+	second := <-events
+	if second.Type != ChangeState || second.Old != string(ItemStatePendingReview) || second.New != string(ItemStatePublished) {
+		t.Errorf("unexpected event %+v", second)
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestWatchWithoutItems(t *testing.T) {
+	client := NewClient(nil)
+
+	if _, err := client.Publishers.Items.Watch().Do(); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestWatchErrors(t *testing.T) {
+	server := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"code":401,"message":"expired"}}`))
+	})
+	defer server.Close()
+
+	client := NewClient(nil)
+	client.SetBaseURL(server.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	itemName := NewItemName("test-publisher", "test-item")
+	errs := make(chan error, 1)
+	events, err := client.Publishers.Items.Watch(itemName).
+		Context(ctx).
+		Interval(10 * time.Millisecond).
+		OnError(func(item ItemName, err error) {
+			if item != itemName {
+				t.Errorf("expected item %s, got %s", itemName, item)
+			}
+			select {
+			case errs <- err:
+			default:
+			}
+		}).
+		Do()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case err := <-errs:
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+			t.Errorf("expected a 401 API error, got %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("expected the poll error to be reported")
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestWatchNonPositiveInterval(t *testing.T) {
+	client := NewClient(nil)
+	itemName := NewItemName("test-publisher", "test-item")
+
+	for _, d := range []time.Duration{0, -time.Second} {
+		call := client.Publishers.Items.Watch(itemName).Interval(d)
+		if call.interval != DefaultWatchInterval {
+			t.Errorf("expected interval %s for %s, got %s", DefaultWatchInterval, d, call.interval)
+		}
+	}
+
+	server := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+	client.SetBaseURL(server.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	call := client.Publishers.Items.Watch(itemName).Context(ctx)
+	call.interval = 0
+	for event, err := range call.Seq() {
+		if err != nil || event.Type != ChangeInitial {
+			t.Errorf("expected the initial event, got %+v, %v", event, err)
+		}
+		break
+	}
+}
@@ -0,0 +1,127 @@
+package chromewebstore
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// rateLimitBackoffFactor is the factor the rate is multiplied by after a
+	// 429 response.
+	rateLimitBackoffFactor = 0.5
+	// rateLimitMinFactor bounds how far the rate is lowered, relative to the
+	// configured rate.
+	rateLimitMinFactor = 1.0 / 16
+	// rateLimitRecoveryFactor is the fraction of the configured rate
+	// recovered after each successful response.
+	rateLimitRecoveryFactor = 0.1
+)
+
+// RateLimiter is a token bucket rate limiter that adapts to the server:
+// after a 429 Too Many Requests response it halves its rate, and it
+// recovers gradually with every successful response.
+//
+// A RateLimiter is safe for concurrent use and may be shared by several
+// clients, for example all clients acting for the same publisher.
+type RateLimiter struct {
+	mu sync.Mutex
+	// limit is the configured rate in requests per second.
+	limit float64
+	// rate is the current, possibly reduced, rate.
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	// now and sleep are replaced in tests.
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewRateLimiter returns a limiter allowing requestsPerSecond requests per
+// second on average and bursts of up to burst requests. Burst values below
+// 1 are treated as 1.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	l := &RateLimiter{
+		limit: requestsPerSecond,
+		rate:  requestsPerSecond,
+		burst: float64(burst),
+		now:   time.Now,
+		sleep: sleepContext,
+	}
+	l.tokens = l.burst
+	l.last = l.now()
+	return l
+}
+
+// Rate returns the current rate in requests per second, which is below the
+// configured rate while the limiter is backing off.
+func (l *RateLimiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate
+}
+
+// Wait blocks until a request may be made or ctx is done. A limiter with
+// a rate of zero or less does not limit requests.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l.limit <= 0 {
+		return ctx.Err()
+	}
+	for {
+		l.mu.Lock()
+		l.refill()
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		if err := l.sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// Observe adapts the rate to the status code of a response.
+func (l *RateLimiter) Observe(statusCode int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill()
+	if statusCode == http.StatusTooManyRequests {
+		l.rate = math.Max(l.rate*rateLimitBackoffFactor, l.limit*rateLimitMinFactor)
+		l.tokens = 0
+		return
+	}
+	if statusCode < 500 {
+		l.rate = math.Min(l.rate+l.limit*rateLimitRecoveryFactor, l.limit)
+	}
+}
+
+// refill adds the tokens accumulated since the last call. l.mu must be held.
+func (l *RateLimiter) refill() {
+	now := l.now()
+	l.tokens = math.Min(l.tokens+now.Sub(l.last).Seconds()*l.rate, l.burst)
+	l.last = now
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
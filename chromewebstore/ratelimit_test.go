@@ -0,0 +1,127 @@
+package chromewebstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// newFakeClockLimiter returns a limiter whose sleeps advance a fake clock
+// and are recorded in slept.
+func newFakeClockLimiter(rps float64, burst int, slept *[]time.Duration) *RateLimiter {
+	l := NewRateLimiter(rps, burst)
+	now := time.Unix(0, 0)
+	l.now = func() time.Time { return now }
+	l.last = now
+	l.sleep = func(ctx context.Context, d time.Duration) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		*slept = append(*slept, d)
+		now = now.Add(d)
+		return nil
+	}
+	return l
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	var slept []time.Duration
+	l := newFakeClockLimiter(2, 3, &slept)
+
+	for i := 0; i < 5; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(slept) != 2 {
+		t.Fatalf("expected 2 waits after the burst, got %v", slept)
+	}
+	for _, d := range slept {
+		if d != 500*time.Millisecond {
+			t.Errorf("expected 500ms wait, got %s", d)
+		}
+	}
+}
+
+func TestRateLimiterWaitCanceled(t *testing.T) {
+	var slept []time.Duration
+	l := newFakeClockLimiter(1, 1, &slept)
+	l.Wait(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRateLimiterObserve(t *testing.T) {
+	l := NewRateLimiter(8, 1)
+
+	l.Observe(http.StatusTooManyRequests)
+	if l.Rate() != 4 {
+		t.Errorf("expected rate 4 after 429, got %v", l.Rate())
+	}
+	for i := 0; i < 10; i++ {
+		l.Observe(http.StatusTooManyRequests)
+	}
+	if l.Rate() != 0.5 {
+		t.Errorf("expected rate to bottom out at 0.5, got %v", l.Rate())
+	}
+
+	l.Observe(http.StatusInternalServerError)
+	if l.Rate() != 0.5 {
+		t.Errorf("expected 5xx not to change the rate, got %v", l.Rate())
+	}
+
+	for i := 0; i < 20; i++ {
+		l.Observe(http.StatusOK)
+	}
+	if l.Rate() != 8 {
+		t.Errorf("expected rate to recover to 8, got %v", l.Rate())
+	}
+}
+
+func TestClientRateLimit(t *testing.T) {
+	calls := 0
+	server := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error": {"code": 429, "message": "slow down"}}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+
+	client := NewClient(nil)
+	client.SetBaseURL(server.URL)
+	limiter := NewRateLimiter(1000, 1)
+	client.SetRateLimiter(limiter)
+
+	itemName := NewItemName("pub", "item")
+	_, err := client.Publishers.Items.FetchStatus(itemName).Do()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !apiErr.IsRateLimited() {
+		t.Fatalf("expected rate limited error, got %v", err)
+	}
+	if limiter.Rate() != 500 {
+		t.Errorf("expected rate to be halved, got %v", limiter.Rate())
+	}
+
+	if _, err := client.Publishers.Items.FetchStatus(itemName).Do(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client.SetRateLimit(0.001, 1)
+	client.Publishers.Items.FetchStatus(itemName).Context(context.Background()).Do()
+	if _, err := client.Publishers.Items.FetchStatus(itemName).Context(ctx).Do(); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled while waiting, got %v", err)
+	}
+}
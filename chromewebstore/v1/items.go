@@ -0,0 +1,26 @@
+package v1
+
+// ItemsService provides access to items operations.
+type ItemsService struct {
+	client *Client
+}
+
+// Get returns a GetCall for fetching an item.
+func (s *ItemsService) Get(itemID string) *GetCall {
+	return &GetCall{call: newCall(s.client), itemID: itemID}
+}
+
+// Insert returns an InsertCall for creating a new item from a package.
+func (s *ItemsService) Insert() *InsertCall {
+	return &InsertCall{call: newCall(s.client)}
+}
+
+// Update returns an UpdateCall for uploading a new package to an item.
+func (s *ItemsService) Update(itemID string) *UpdateCall {
+	return &UpdateCall{call: newCall(s.client), itemID: itemID}
+}
+
+// Publish returns a PublishCall for publishing an item.
+func (s *ItemsService) Publish(itemID string) *PublishCall {
+	return &PublishCall{call: newCall(s.client), itemID: itemID}
+}
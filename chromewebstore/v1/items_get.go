@@ -0,0 +1,35 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// GetCall represents a call to items.get.
+type GetCall struct {
+	call
+	itemID string
+}
+
+// Context sets the context for the request.
+func (c *GetCall) Context(ctx context.Context) *GetCall {
+	c.ctx = ctx
+	return c
+}
+
+// Projection selects the revision to return. The API defaults to
+// ProjectionDraft.
+func (c *GetCall) Projection(projection Projection) *GetCall {
+	c.params.Set("projection", string(projection))
+	return c
+}
+
+// Do executes the get request.
+func (c *GetCall) Do() (*Item, error) {
+	var item Item
+	if err := c.do(http.MethodGet, c.client.baseURL, "/items/"+url.PathEscape(c.itemID), nil, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
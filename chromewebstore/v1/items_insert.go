@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// InsertCall represents a call to items.insert.
+type InsertCall struct {
+	call
+	media io.Reader
+}
+
+// Context sets the context for the request.
+func (c *InsertCall) Context(ctx context.Context) *InsertCall {
+	c.ctx = ctx
+	return c
+}
+
+// Media sets the ZIP package of the new item.
+func (c *InsertCall) Media(media io.Reader) *InsertCall {
+	c.media = media
+	return c
+}
+
+// PublisherEmail sets the email of the publisher owning the new item, for
+// publishers with group publishing enabled.
+func (c *InsertCall) PublisherEmail(email string) *InsertCall {
+	c.params.Set("publisherEmail", email)
+	return c
+}
+
+// Do executes the insert request.
+func (c *InsertCall) Do() (*Item, error) {
+	if c.media == nil {
+		return nil, fmt.Errorf("chromewebstore/v1: media is required for insert")
+	}
+	var item Item
+	if err := c.do(http.MethodPost, c.client.uploadBaseURL, "/items", c.media, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// UpdateCall represents a call to items.update.
+type UpdateCall struct {
+	call
+	itemID string
+	media  io.Reader
+}
+
+// Context sets the context for the request.
+func (c *UpdateCall) Context(ctx context.Context) *UpdateCall {
+	c.ctx = ctx
+	return c
+}
+
+// Media sets the ZIP package to upload.
+func (c *UpdateCall) Media(media io.Reader) *UpdateCall {
+	c.media = media
+	return c
+}
+
+// Do executes the update request.
+func (c *UpdateCall) Do() (*Item, error) {
+	if c.media == nil {
+		return nil, fmt.Errorf("chromewebstore/v1: media is required for update")
+	}
+	var item Item
+	if err := c.do(http.MethodPut, c.client.uploadBaseURL, "/items/"+url.PathEscape(c.itemID), c.media, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
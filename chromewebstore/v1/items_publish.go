@@ -0,0 +1,48 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// PublishCall represents a call to items.publish.
+type PublishCall struct {
+	call
+	itemID string
+}
+
+// Context sets the context for the request.
+func (c *PublishCall) Context(ctx context.Context) *PublishCall {
+	c.ctx = ctx
+	return c
+}
+
+// Target sets the audience to publish to. The API defaults to
+// PublishTargetDefault.
+func (c *PublishCall) Target(target PublishTarget) *PublishCall {
+	c.params.Set("publishTarget", string(target))
+	return c
+}
+
+// DeployPercentage sets the percentage of users receiving the update.
+func (c *PublishCall) DeployPercentage(percentage int) *PublishCall {
+	c.params.Set("deployPercentage", strconv.Itoa(percentage))
+	return c
+}
+
+// ReviewExemption sets whether to request an exemption from review.
+func (c *PublishCall) ReviewExemption(exempt bool) *PublishCall {
+	c.params.Set("reviewExemption", strconv.FormatBool(exempt))
+	return c
+}
+
+// Do executes the publish request.
+func (c *PublishCall) Do() (*PublishResponse, error) {
+	var result PublishResponse
+	if err := c.do(http.MethodPost, c.client.baseURL, "/items/"+url.PathEscape(c.itemID)+"/publish", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
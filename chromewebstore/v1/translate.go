@@ -0,0 +1,124 @@
+package v1
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+// UploadError is returned when a v1.1 upload failed. It carries the
+// item errors reported by the API.
+type UploadError struct {
+	// ItemID is the ID of the item.
+	ItemID string
+	// Errors are the reported item errors.
+	Errors []ItemError
+}
+
+// Error returns the error message.
+func (e *UploadError) Error() string {
+	details := make([]string, 0, len(e.Errors))
+	for _, ie := range e.Errors {
+		details = append(details, ie.ErrorDetail)
+	}
+	if len(details) == 0 {
+		return fmt.Sprintf("chromewebstore/v1: upload of item %s failed", e.ItemID)
+	}
+	return fmt.Sprintf("chromewebstore/v1: upload of item %s failed: %s", e.ItemID, strings.Join(details, "; "))
+}
+
+// PublishError is returned when items.publish did not submit the item.
+type PublishError struct {
+	// ItemID is the ID of the item.
+	ItemID string
+	// Status contains the status codes, such as ITEM_PENDING_REVIEW.
+	Status []string
+	// StatusDetail contains human-readable explanations of Status.
+	StatusDetail []string
+}
+
+// Error returns the error message.
+func (e *PublishError) Error() string {
+	msg := strings.Join(e.Status, ", ")
+	if len(e.StatusDetail) > 0 {
+		msg += ": " + strings.Join(e.StatusDetail, "; ")
+	}
+	return fmt.Sprintf("chromewebstore/v1: publish of item %s failed: %s", e.ItemID, msg)
+}
+
+// ConvertUploadState returns the v2 upload state equivalent to s. An empty
+// state stays empty; unknown states map to UploadStateUnspecified.
+func ConvertUploadState(s UploadState) chromewebstore.UploadState {
+	switch s {
+	case "":
+		return ""
+	case UploadStateSuccess:
+		return chromewebstore.UploadStateSucceeded
+	case UploadStateFailure:
+		return chromewebstore.UploadStateFailed
+	case UploadStateInProgress:
+		return chromewebstore.UploadStateInProgress
+	case UploadStateNotFound:
+		return chromewebstore.UploadStateNotFound
+	}
+	return chromewebstore.UploadStateUnspecified
+}
+
+// itemName returns the v2 resource name of the item, or "" if publisherID
+// is empty.
+func itemName(publisherID, itemID string) string {
+	if publisherID == "" {
+		return ""
+	}
+	return chromewebstore.NewItemName(publisherID, itemID).String()
+}
+
+// ToItemStatus converts the item to a v2 item status. v1.1 items have no
+// publisher, so the resource name is only set if publisherID is given.
+// The v1.1 API does not report revision states or deploy percentages, so
+// the revision statuses are left unset.
+func (i *Item) ToItemStatus(publisherID string) *chromewebstore.ItemStatus {
+	return &chromewebstore.ItemStatus{
+		ServerResponse:       i.ServerResponse,
+		Name:                 itemName(publisherID, i.ID),
+		ItemID:               i.ID,
+		PublicKey:            i.PublicKey,
+		LastAsyncUploadState: ConvertUploadState(i.UploadState),
+	}
+}
+
+// ToUploadResponse converts the result of items.insert or items.update to
+// a v2 upload response. It also returns an *UploadError with the item
+// errors if the upload failed.
+func (i *Item) ToUploadResponse(publisherID string) (*chromewebstore.UploadResponse, error) {
+	resp := &chromewebstore.UploadResponse{
+		ServerResponse: i.ServerResponse,
+		Name:           itemName(publisherID, i.ID),
+		ItemID:         i.ID,
+		UploadState:    ConvertUploadState(i.UploadState),
+		CrxVersion:     i.CrxVersion,
+	}
+	if i.UploadState == UploadStateFailure || len(i.ItemError) > 0 {
+		return resp, &UploadError{ItemID: i.ID, Errors: i.ItemError}
+	}
+	return resp, nil
+}
+
+// ToPublishResponse converts the result of items.publish to a v2 publish
+// response. It also returns a *PublishError if any status is not
+// PublishStatusOK. The v1.1 API does not report the resulting state, so
+// State is left unset.
+func (r *PublishResponse) ToPublishResponse(publisherID string) (*chromewebstore.PublishResponse, error) {
+	resp := &chromewebstore.PublishResponse{
+		ServerResponse: r.ServerResponse,
+		Name:           itemName(publisherID, r.ItemID),
+		ItemID:         r.ItemID,
+	}
+	for _, status := range r.Status {
+		if status != PublishStatusOK {
+			return resp, &PublishError{ItemID: r.ItemID, Status: r.Status, StatusDetail: r.StatusDetail}
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+func TestConvertUploadState(t *testing.T) {
+	tests := map[UploadState]chromewebstore.UploadState{
+		"":                    "",
+		UploadStateSuccess:    chromewebstore.UploadStateSucceeded,
+		UploadStateFailure:    chromewebstore.UploadStateFailed,
+		UploadStateInProgress: chromewebstore.UploadStateInProgress,
+		UploadStateNotFound:   chromewebstore.UploadStateNotFound,
+		"SOMETHING_NEW":       chromewebstore.UploadStateUnspecified,
+	}
+	for state, expected := range tests {
+		if got := ConvertUploadState(state); got != expected {
+			t.Errorf("ConvertUploadState(%q): expected %q, got %q", state, expected, got)
+		}
+	}
+}
+
+func TestToItemStatus(t *testing.T) {
+	item := &Item{ID: "item", PublicKey: "key", UploadState: UploadStateInProgress}
+
+	status := item.ToItemStatus("pub")
+	if status.Name != "publishers/pub/items/item" || status.ItemID != "item" || status.PublicKey != "key" {
+		t.Errorf("unexpected status %+v", status)
+	}
+	if status.LastAsyncUploadState != chromewebstore.UploadStateInProgress {
+		t.Errorf("expected IN_PROGRESS, got %s", status.LastAsyncUploadState)
+	}
+	if status.SubmittedItemRevisionStatus != nil || status.PublishedItemRevisionStatus != nil {
+		t.Error("expected revision statuses to be unset")
+	}
+
+	if item.ToItemStatus("").Name != "" {
+		t.Error("expected empty name without publisher")
+	}
+}
+
+func TestToUploadResponse(t *testing.T) {
+	resp, err := (&Item{ID: "item", UploadState: UploadStateSuccess, CrxVersion: "1.0"}).ToUploadResponse("pub")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.UploadState != chromewebstore.UploadStateSucceeded || resp.CrxVersion != "1.0" || resp.Name != "publishers/pub/items/item" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+
+	failed := &Item{ID: "item", UploadState: UploadStateFailure, ItemError: []ItemError{
+		{ErrorCode: "PKG_MANIFEST_PARSE_ERROR", ErrorDetail: "Manifest is not valid JSON."},
+		{ErrorCode: "PKG_INVALID_ICON", ErrorDetail: "Icon missing."},
+	}}
+	resp, err = failed.ToUploadResponse("pub")
+	var uploadErr *UploadError
+	if !errors.As(err, &uploadErr) || len(uploadErr.Errors) != 2 {
+		t.Fatalf("expected *UploadError, got %v", err)
+	}
+	if err.Error() != "chromewebstore/v1: upload of item item failed: Manifest is not valid JSON.; Icon missing." {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+	if resp.UploadState != chromewebstore.UploadStateFailed {
+		t.Errorf("expected FAILED, got %s", resp.UploadState)
+	}
+}
+
+func TestToPublishResponse(t *testing.T) {
+	resp, err := (&PublishResponse{ItemID: "item", Status: []string{"OK"}}).ToPublishResponse("pub")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.ItemID != "item" || resp.Name != "publishers/pub/items/item" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+
+	_, err = (&PublishResponse{
+		ItemID:       "item",
+		Status:       []string{"ITEM_PENDING_REVIEW"},
+		StatusDetail: []string{"The item is pending review."},
+	}).ToPublishResponse("pub")
+	var publishErr *PublishError
+	if !errors.As(err, &publishErr) {
+		t.Fatalf("expected *PublishError, got %v", err)
+	}
+	if err.Error() != "chromewebstore/v1: publish of item item failed: ITEM_PENDING_REVIEW: The item is pending review." {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+}
@@ -0,0 +1,96 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+// Projection selects the revision returned by items.get.
+type Projection string
+
+const (
+	// ProjectionDraft returns the draft revision.
+	ProjectionDraft Projection = "DRAFT"
+	// ProjectionPublished returns the published revision.
+	ProjectionPublished Projection = "PUBLISHED"
+)
+
+// PublishTarget selects the audience of items.publish.
+type PublishTarget string
+
+const (
+	// PublishTargetDefault publishes to everyone.
+	PublishTargetDefault PublishTarget = "default"
+	// PublishTargetTrustedTesters publishes to trusted testers only.
+	PublishTargetTrustedTesters PublishTarget = "trustedTesters"
+)
+
+// UploadState is the state of a v1.1 upload.
+type UploadState string
+
+const (
+	UploadStateSuccess    UploadState = "SUCCESS"
+	UploadStateFailure    UploadState = "FAILURE"
+	UploadStateInProgress UploadState = "IN_PROGRESS"
+	UploadStateNotFound   UploadState = "NOT_FOUND"
+)
+
+// PublishStatusOK is the publish status reported when an item was
+// submitted successfully.
+const PublishStatusOK = "OK"
+
+// Item is a v1.1 item resource, returned by items.get, items.insert and
+// items.update.
+type Item struct {
+	chromewebstore.ServerResponse `json:"-"`
+
+	// Kind is always "chromewebstore#item".
+	Kind string `json:"kind,omitempty"`
+	// ID is the ID of the item.
+	ID string `json:"id,omitempty"`
+	// PublicKey is the public key of the item.
+	PublicKey string `json:"publicKey,omitempty"`
+	// UploadState is the state of the upload, set by items.insert,
+	// items.update and items.get with the DRAFT projection.
+	UploadState UploadState `json:"uploadState,omitempty"`
+	// CrxVersion is the extension version from the manifest.
+	CrxVersion string `json:"crxVersion,omitempty"`
+	// ItemError lists the errors of a failed upload.
+	ItemError []ItemError `json:"itemError,omitempty"`
+}
+
+// ItemError is an error reported for an item.
+type ItemError struct {
+	// ErrorCode identifies the error.
+	ErrorCode string `json:"error_code,omitempty"`
+	// ErrorDetail describes the error.
+	ErrorDetail string `json:"error_detail,omitempty"`
+}
+
+// PublishResponse is the response of items.publish.
+type PublishResponse struct {
+	chromewebstore.ServerResponse `json:"-"`
+
+	// Kind is always "chromewebstore#item".
+	Kind string `json:"kind,omitempty"`
+	// ItemID is the ID of the item.
+	ItemID string `json:"item_id,omitempty"`
+	// Status contains PublishStatusOK on success, or codes such as
+	// ITEM_PENDING_REVIEW describing why the item was not published.
+	Status []string `json:"status,omitempty"`
+	// StatusDetail contains human-readable explanations of Status.
+	StatusDetail []string `json:"statusDetail,omitempty"`
+}
+
+// setServerResponse records the response an item was decoded from.
+func (i *Item) setServerResponse(resp *http.Response) {
+	i.HTTPStatusCode = resp.StatusCode
+	i.Header = resp.Header
+}
+
+// setServerResponse records the response a publish result was decoded from.
+func (r *PublishResponse) setServerResponse(resp *http.Response) {
+	r.HTTPStatusCode = resp.StatusCode
+	r.Header = resp.Header
+}
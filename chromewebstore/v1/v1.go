@@ -0,0 +1,141 @@
+// Package v1 provides a client for the legacy Chrome Web Store API v1.1
+// (items.get, items.insert, items.update and items.publish) in the same
+// fluent style as package chromewebstore, and converts its results to the
+// v2 types so that scripts can be migrated one call at a time.
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+const (
+	// DefaultBaseURL is the default base URL for the Chrome Web Store API v1.1.
+	DefaultBaseURL = "https://www.googleapis.com/chromewebstore/v1.1"
+	// DefaultUploadBaseURL is the default base URL for v1.1 upload operations.
+	DefaultUploadBaseURL = "https://www.googleapis.com/upload/chromewebstore/v1.1"
+)
+
+// Client is a Chrome Web Store API v1.1 client.
+type Client struct {
+	// httpClient is the HTTP client used for API requests.
+	httpClient *http.Client
+	// baseURL is the base URL for API requests.
+	baseURL string
+	// uploadBaseURL is the base URL for upload requests.
+	uploadBaseURL string
+
+	// Items provides access to items operations.
+	Items *ItemsService
+}
+
+// NewClient creates a new Chrome Web Store API v1.1 client.
+// The provided http.Client should be configured with OAuth 2.0 credentials.
+func NewClient(httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	c := &Client{
+		httpClient:    httpClient,
+		baseURL:       DefaultBaseURL,
+		uploadBaseURL: DefaultUploadBaseURL,
+	}
+	c.Items = &ItemsService{client: c}
+	return c
+}
+
+// NewClientFromCredentials creates a new v1.1 client with OAuth 2.0
+// authentication using the same credentials as the v2 client.
+func NewClientFromCredentials(ctx context.Context, config chromewebstore.AuthConfig) *Client {
+	return NewClient(chromewebstore.NewAuthenticatedClient(ctx, config))
+}
+
+// SetBaseURL sets the base URL for API requests.
+// This is useful for testing with a mock server.
+func (c *Client) SetBaseURL(baseURL string) {
+	c.baseURL = baseURL
+}
+
+// SetUploadBaseURL sets the base URL for upload requests.
+// This is useful for testing with a mock server.
+func (c *Client) SetUploadBaseURL(uploadBaseURL string) {
+	c.uploadBaseURL = uploadBaseURL
+}
+
+// call holds the state shared by every call type.
+type call struct {
+	client *Client
+	ctx    context.Context
+	params url.Values
+}
+
+// newCall creates a call.
+func newCall(c *Client) call {
+	return call{client: c, ctx: context.Background(), params: make(url.Values)}
+}
+
+// do sends a request with an optional body to the URL built from base,
+// path and the call's parameters, and decodes the response into target.
+func (c *call) do(method, base, path string, body io.Reader, target any) error {
+	u := base + path
+	if len(c.params) > 0 {
+		u += "?" + c.params.Encode()
+	}
+	req, err := http.NewRequestWithContext(c.ctx, method, u, body)
+	if err != nil {
+		return fmt.Errorf("chromewebstore/v1: failed to create request: %w", err)
+	}
+	// The v1.1 API requires the version header on every request.
+	req.Header.Set("x-goog-api-version", "2")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("chromewebstore/v1: failed to read response body: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return newAPIError(resp, data)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("chromewebstore/v1: failed to unmarshal response: %w", err)
+	}
+	if r, ok := target.(interface {
+		setServerResponse(*http.Response)
+	}); ok {
+		r.setServerResponse(resp)
+	}
+	return nil
+}
+
+// newAPIError returns the v2 error type for a failed response so that
+// callers handle errors from both APIs alike.
+func newAPIError(resp *http.Response, body []byte) *chromewebstore.APIError {
+	apiErr := &chromewebstore.APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       string(body),
+		Header:     resp.Header,
+	}
+	var errResp struct {
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error != nil {
+		apiErr.Message = errResp.Error.Message
+	}
+	return apiErr
+}
@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+func newTestClient(handler http.HandlerFunc) (*Client, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	client := NewClient(nil)
+	client.SetBaseURL(server.URL)
+	client.SetUploadBaseURL(server.URL + "/upload")
+	return client, server
+}
+
+func TestGet(t *testing.T) {
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET method, got %s", r.Method)
+		}
+		if r.URL.Path != "/items/test-item" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("projection") != "PUBLISHED" {
+			t.Errorf("expected projection PUBLISHED, got %q", r.URL.Query().Get("projection"))
+		}
+		if r.Header.Get("x-goog-api-version") != "2" {
+			t.Errorf("expected x-goog-api-version 2, got %q", r.Header.Get("x-goog-api-version"))
+		}
+		w.Write([]byte(`{"kind": "chromewebstore#item", "id": "test-item", "publicKey": "key", "uploadState": "SUCCESS", "crxVersion": "1.2.3"}`))
+	})
+	defer server.Close()
+
+	item, err := client.Items.Get("test-item").Projection(ProjectionPublished).Do()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item.ID != "test-item" || item.UploadState != UploadStateSuccess || item.CrxVersion != "1.2.3" {
+		t.Errorf("unexpected item %+v", item)
+	}
+	if item.HTTPStatusCode != http.StatusOK {
+		t.Errorf("expected status code 200, got %d", item.HTTPStatusCode)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("expected PUT method, got %s", r.Method)
+		}
+		if r.URL.Path != "/upload/items/test-item" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "zip" {
+			t.Errorf("expected body zip, got %q", body)
+		}
+		w.Write([]byte(`{"id": "test-item", "uploadState": "FAILURE", "itemError": [{"error_code": "PKG_INVALID_VERSION_NUMBER", "error_detail": "Invalid version number."}]}`))
+	})
+	defer server.Close()
+
+	item, err := client.Items.Update("test-item").Media(strings.NewReader("zip")).Do()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(item.ItemError) != 1 || item.ItemError[0].ErrorCode != "PKG_INVALID_VERSION_NUMBER" {
+		t.Errorf("unexpected item errors %+v", item.ItemError)
+	}
+
+	if _, err := client.Items.Update("test-item").Do(); err == nil {
+		t.Error("expected error without media")
+	}
+}
+
+func TestInsert(t *testing.T) {
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/upload/items" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("publisherEmail") != "group@example.com" {
+			t.Errorf("unexpected publisherEmail %q", r.URL.Query().Get("publisherEmail"))
+		}
+		w.Write([]byte(`{"id": "new-item", "uploadState": "SUCCESS"}`))
+	})
+	defer server.Close()
+
+	item, err := client.Items.Insert().Media(strings.NewReader("zip")).PublisherEmail("group@example.com").Do()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item.ID != "new-item" {
+		t.Errorf("expected item new-item, got %s", item.ID)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/items/test-item/publish" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("publishTarget") != "trustedTesters" {
+			t.Errorf("expected publishTarget trustedTesters, got %q", query.Get("publishTarget"))
+		}
+		if query.Get("deployPercentage") != "10" {
+			t.Errorf("expected deployPercentage 10, got %q", query.Get("deployPercentage"))
+		}
+		w.Write([]byte(`{"item_id": "test-item", "status": ["OK"], "statusDetail": ["Published."]}`))
+	})
+	defer server.Close()
+
+	resp, err := client.Items.Publish("test-item").Target(PublishTargetTrustedTesters).DeployPercentage(10).Do()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.ItemID != "test-item" || len(resp.Status) != 1 || resp.Status[0] != PublishStatusOK {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(chromewebstore.RequestIDHeader, "req-1")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error": {"code": 403, "message": "forbidden"}}`))
+	})
+	defer server.Close()
+
+	_, err := client.Items.Get("test-item").Do()
+	var apiErr *chromewebstore.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *chromewebstore.APIError, got %v", err)
+	}
+	if !apiErr.IsForbidden() || apiErr.Message != "forbidden" || apiErr.RequestID() != "req-1" {
+		t.Errorf("unexpected error %+v", apiErr)
+	}
+}
@@ -0,0 +1,308 @@
+// Package crx builds, parses and verifies CRX3 extension packages for
+// verified uploads to the Chrome Web Store.
+package crx
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	magic   = "Cr24"
+	version = 3
+
+	// signatureContext is prepended to the signed data as defined by the CRX3 format.
+	signatureContext = "CRX3 SignedData\x00"
+
+	// Field numbers of CrxFileHeader and SignedData.
+	fieldSHA256WithRSA    = 2
+	fieldSHA256WithECDSA  = 3
+	fieldSignedHeaderData = 10000
+	fieldProofPublicKey   = 1
+	fieldProofSignature   = 2
+	fieldSignedDataCrxID  = 1
+)
+
+// File is a parsed CRX3 package.
+type File struct {
+	// Proofs are the RSA key proofs contained in the header.
+	Proofs []Proof
+	// CrxID is the 16-byte CRX ID the package declares.
+	CrxID []byte
+	// Zip is the embedded ZIP archive.
+	Zip []byte
+
+	signedHeaderData []byte
+}
+
+// Proof is a public key and the signature it produced.
+type Proof struct {
+	// PublicKey is the DER-encoded SubjectPublicKeyInfo.
+	PublicKey []byte
+	// Signature is the signature over the signed data.
+	Signature []byte
+}
+
+// IsCRX reports whether data starts with the CRX magic number.
+func IsCRX(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(magic))
+}
+
+// ParsePrivateKey parses a PEM-encoded RSA private key in PKCS #8 or PKCS #1 form.
+func ParsePrivateKey(pemData []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, errors.New("crx: no PEM data found in private key")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("crx: failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("crx: private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// DecodePublicKey decodes a public key given as base64-encoded DER, as in
+// the manifest "key" field and ItemStatus.PublicKey, or as PEM.
+func DecodePublicKey(s string) ([]byte, error) {
+	if block, _ := pem.Decode([]byte(s)); block != nil {
+		return block.Bytes, nil
+	}
+
+	s = strings.Join(strings.Fields(s), "")
+	der, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		der, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(s, "="))
+		if err != nil {
+			return nil, fmt.Errorf("crx: failed to decode public key: %w", err)
+		}
+	}
+	return der, nil
+}
+
+// CrxID returns the 16-byte CRX ID of a DER-encoded public key.
+func CrxID(publicKey []byte) []byte {
+	sum := sha256.Sum256(publicKey)
+	return sum[:16]
+}
+
+// Sign builds a CRX3 package from a ZIP archive, signed with key.
+func Sign(zipData []byte, key *rsa.PrivateKey) ([]byte, error) {
+	publicKey, err := PublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	signedHeaderData := appendBytesField(nil, fieldSignedDataCrxID, CrxID(publicKey))
+
+	digest := signedDigest(signedHeaderData, zipData)
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest)
+	if err != nil {
+		return nil, fmt.Errorf("crx: failed to sign package: %w", err)
+	}
+
+	var proof []byte
+	proof = appendBytesField(proof, fieldProofPublicKey, publicKey)
+	proof = appendBytesField(proof, fieldProofSignature, signature)
+
+	var header []byte
+	header = appendBytesField(header, fieldSHA256WithRSA, proof)
+	header = appendBytesField(header, fieldSignedHeaderData, signedHeaderData)
+
+	out := make([]byte, 0, 12+len(header)+len(zipData))
+	out = append(out, magic...)
+	out = binary.LittleEndian.AppendUint32(out, version)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(header)))
+	out = append(out, header...)
+	out = append(out, zipData...)
+	return out, nil
+}
+
+// Parse parses a CRX3 package. Signatures are not checked; use Verify.
+func Parse(data []byte) (*File, error) {
+	if !IsCRX(data) {
+		return nil, errors.New("crx: not a CRX file")
+	}
+	if len(data) < 12 {
+		return nil, errors.New("crx: truncated header")
+	}
+	if v := binary.LittleEndian.Uint32(data[4:8]); v != version {
+		return nil, fmt.Errorf("crx: unsupported CRX version %d", v)
+	}
+
+	headerLen := int64(binary.LittleEndian.Uint32(data[8:12]))
+	if 12+headerLen > int64(len(data)) {
+		return nil, errors.New("crx: truncated header")
+	}
+	header := data[12 : 12+headerLen]
+
+	f := &File{Zip: data[12+headerLen:]}
+	err := readFields(header, func(field int, value []byte) error {
+		switch field {
+		case fieldSHA256WithRSA:
+			var p Proof
+			err := readFields(value, func(field int, value []byte) error {
+				switch field {
+				case fieldProofPublicKey:
+					p.PublicKey = value
+				case fieldProofSignature:
+					p.Signature = value
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			f.Proofs = append(f.Proofs, p)
+		case fieldSignedHeaderData:
+			f.signedHeaderData = value
+			return readFields(value, func(field int, value []byte) error {
+				if field == fieldSignedDataCrxID {
+					f.CrxID = value
+				}
+				return nil
+			})
+		case fieldSHA256WithECDSA:
+			// ECDSA proofs are only used by the store's own signature.
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// PublicKey returns the DER-encoded public key matching the declared CRX ID.
+func (f *File) PublicKey() ([]byte, error) {
+	for _, p := range f.Proofs {
+		if bytes.Equal(CrxID(p.PublicKey), f.CrxID) {
+			return p.PublicKey, nil
+		}
+	}
+	return nil, errors.New("crx: no public key matches the CRX ID")
+}
+
+// Verify checks that the package has a valid RSA signature from the key
+// matching its CRX ID.
+func (f *File) Verify() error {
+	for _, p := range f.Proofs {
+		if !bytes.Equal(CrxID(p.PublicKey), f.CrxID) {
+			continue
+		}
+
+		parsed, err := x509.ParsePKIXPublicKey(p.PublicKey)
+		if err != nil {
+			return fmt.Errorf("crx: failed to parse public key: %w", err)
+		}
+		key, ok := parsed.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("crx: public key is not an RSA key")
+		}
+
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, signedDigest(f.signedHeaderData, f.Zip), p.Signature); err != nil {
+			return fmt.Errorf("crx: invalid signature: %w", err)
+		}
+		return nil
+	}
+	return errors.New("crx: no public key matches the CRX ID")
+}
+
+// VerifyPublicKey checks that the package is signed by the given public key,
+// encoded as accepted by DecodePublicKey.
+func (f *File) VerifyPublicKey(expected string) error {
+	want, err := DecodePublicKey(expected)
+	if err != nil {
+		return err
+	}
+
+	got, err := f.PublicKey()
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(got, want) {
+		return errors.New("crx: package public key does not match the expected public key")
+	}
+	return nil
+}
+
+// signedDigest returns the SHA-256 digest of the data covered by a CRX3 signature.
+func signedDigest(signedHeaderData, zipData []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte(signatureContext))
+	binary.Write(h, binary.LittleEndian, uint32(len(signedHeaderData)))
+	h.Write(signedHeaderData)
+	h.Write(zipData)
+	return h.Sum(nil)
+}
+
+// appendBytesField appends a length-delimited protocol buffer field to b.
+func appendBytesField(b []byte, field int, value []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(value)))
+	return append(b, value...)
+}
+
+// readFields calls fn for every length-delimited field of a protocol buffer
+// message, skipping fields of other wire types.
+func readFields(b []byte, fn func(field int, value []byte) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errors.New("crx: malformed header")
+		}
+		b = b[n:]
+
+		field, wireType := int(tag>>3), tag&7
+		switch wireType {
+		case 0:
+			_, n := binary.Uvarint(b)
+			if n <= 0 {
+				return errors.New("crx: malformed header")
+			}
+			b = b[n:]
+		case 1:
+			if len(b) < 8 {
+				return errors.New("crx: malformed header")
+			}
+			b = b[8:]
+		case 2:
+			length, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < length {
+				return errors.New("crx: malformed header")
+			}
+			value := b[n : n+int(length)]
+			b = b[n+int(length):]
+			if err := fn(field, value); err != nil {
+				return err
+			}
+		case 5:
+			if len(b) < 4 {
+				return errors.New("crx: malformed header")
+			}
+			b = b[4:]
+		default:
+			return errors.New("crx: malformed header")
+		}
+	}
+	return nil
+}
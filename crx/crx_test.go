@@ -0,0 +1,133 @@
+package crx
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/manifest"
+)
+
+func newTestKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return key
+}
+
+func newTestZip(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("manifest.json")
+	w.Write([]byte(`{"manifest_version": 3, "name": "x", "version": "1.0"}`))
+	zw.Close()
+	return buf.Bytes()
+}
+
+func TestSignAndVerify(t *testing.T) {
+	key := newTestKey(t)
+	zipData := newTestZip(t)
+
+	data, err := Sign(zipData, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !IsCRX(data) {
+		t.Fatal("expected CRX magic")
+	}
+
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !bytes.Equal(f.Zip, zipData) {
+		t.Error("expected embedded ZIP to match")
+	}
+
+	if err := f.Verify(); err != nil {
+		t.Errorf("expected valid signature, got %v", err)
+	}
+
+	publicKey, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err := f.VerifyPublicKey(base64.StdEncoding.EncodeToString(publicKey)); err != nil {
+		t.Errorf("expected public key to match, got %v", err)
+	}
+
+	other, _ := x509.MarshalPKIXPublicKey(&newTestKey(t).PublicKey)
+	if err := f.VerifyPublicKey(base64.StdEncoding.EncodeToString(other)); err == nil {
+		t.Error("expected mismatched public key to fail")
+	}
+
+	pkg, err := manifest.OpenBytes(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pkg.Format != manifest.FormatCRX || pkg.Manifest == nil || pkg.Manifest.Version != "1.0" {
+		t.Errorf("expected manifest package to read the CRX, got %+v", pkg)
+	}
+}
+
+func TestVerifyTampered(t *testing.T) {
+	data, err := Sign(newTestZip(t), newTestKey(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data[len(data)-1] ^= 0xff
+
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := f.Verify(); err == nil {
+		t.Error("expected tampered package to fail verification")
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	key := newTestKey(t)
+
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if _, err := ParsePrivateKey(pkcs1); err != nil {
+		t.Errorf("failed to parse PKCS #1 key: %v", err)
+	}
+
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	pkcs8 := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if _, err := ParsePrivateKey(pkcs8); err != nil {
+		t.Errorf("failed to parse PKCS #8 key: %v", err)
+	}
+
+	if _, err := ParsePrivateKey([]byte("not a key")); err == nil {
+		t.Error("expected error for invalid key")
+	}
+}
+
+func TestDecodePublicKey(t *testing.T) {
+	der, _ := x509.MarshalPKIXPublicKey(&newTestKey(t).PublicKey)
+
+	inputs := []string{
+		base64.StdEncoding.EncodeToString(der),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	}
+	for _, in := range inputs {
+		got, err := DecodePublicKey(in)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		if !bytes.Equal(got, der) {
+			t.Error("decoded key does not match")
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package crx
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+)
+
+// ExtensionID returns the Chrome extension ID derived from a DER-encoded
+// public key: the first 16 bytes of its SHA-256 digest, hex-encoded with
+// the digits 0-9a-f mapped to the letters a-p.
+func ExtensionID(publicKey []byte) string {
+	id := make([]byte, 0, 32)
+	for _, b := range CrxID(publicKey) {
+		id = append(id, 'a'+b>>4, 'a'+b&0x0f)
+	}
+	return string(id)
+}
+
+// ExtensionIDFromKey returns the extension ID for a public key encoded as
+// accepted by DecodePublicKey, such as the manifest "key" field.
+func ExtensionIDFromKey(key string) (string, error) {
+	der, err := DecodePublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	return ExtensionID(der), nil
+}
+
+// PublicKey returns the DER-encoded public key of a private key.
+func PublicKey(key *rsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("crx: failed to encode public key: %w", err)
+	}
+	return der, nil
+}
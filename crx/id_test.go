@@ -0,0 +1,46 @@
+package crx
+
+import "testing"
+
+// testKey is the example manifest "key" from the Chrome documentation,
+// whose extension ID is testKeyID.
+const (
+	testKey   = "MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQDcBHwzDvyBQ6bDppkIs9MP4ksKqCMyXQ/A52JivHZKh4YO/9vJsT3oaYhSpDCE9RPocOEQvwsHsFReW2nUEc6OLLyoCFFxIb7KkLGsmfakkut/fFdNJYh0xOTbSN8YvLWcqph09XAY2Y/f0AL7vfO1cuCqtkMt8hFrBGWxDdf9CQIDAQAB"
+	testKeyID = "knldjmfmopnpolahpmmgbagdohdnhkik"
+)
+
+func TestExtensionIDFromKey(t *testing.T) {
+	id, err := ExtensionIDFromKey(testKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if id != testKeyID {
+		t.Errorf("expected %s, got %s", testKeyID, id)
+	}
+}
+
+func TestExtensionIDMatchesCRX(t *testing.T) {
+	key := newTestKey(t)
+	der, err := PublicKey(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := Sign(newTestZip(t), key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	publicKey, err := f.PublicKey()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ExtensionID(publicKey) != ExtensionID(der) {
+		t.Error("expected the CRX public key to produce the same extension ID")
+	}
+}
@@ -0,0 +1,191 @@
+package gateway
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultMaxUploadBytes is the upload size limit used when
+// Config.MaxUploadBytes is zero.
+const DefaultMaxUploadBytes = 100 << 20
+
+const (
+	// AnyItem grants a role on every item when used as a key of
+	// Principal.Items or APIKey.Items.
+	AnyItem = "*"
+	// AnySubject matches every verified ID token when used as
+	// Principal.Subject.
+	AnySubject = "*"
+)
+
+// Role is the permission level of a caller on an item.
+type Role string
+
+const (
+	// RoleReader may fetch the status of an item.
+	RoleReader Role = "reader"
+	// RolePublisher may also upload, publish, cancel submissions and set
+	// the deploy percentage.
+	RolePublisher Role = "publisher"
+)
+
+// level orders roles so that a higher role includes the lower ones.
+func (r Role) level() int {
+	switch r {
+	case RoleReader:
+		return 1
+	case RolePublisher:
+		return 2
+	}
+	return 0
+}
+
+// Config configures the gateway.
+type Config struct {
+	// PublisherID is the publisher whose items are served.
+	PublisherID string `json:"publisherId"`
+	// MaxUploadBytes limits the size of uploaded packages. If zero,
+	// DefaultMaxUploadBytes is used.
+	MaxUploadBytes int64 `json:"maxUploadBytes,omitempty"`
+	// APIKeys are the static keys accepted in the X-API-Key header.
+	APIKeys []APIKey `json:"apiKeys,omitempty"`
+	// OIDC, if set, accepts ID tokens in the Authorization header.
+	OIDC *OIDCConfig `json:"oidc,omitempty"`
+	// Principals grant roles to the subjects of ID tokens.
+	Principals []Principal `json:"principals,omitempty"`
+}
+
+// APIKey is a static key and the roles granted to its holder.
+type APIKey struct {
+	// Name identifies the key in logs.
+	Name string `json:"name"`
+	// Key is the key in plain text. Prefer SHA256 outside of environment
+	// variables.
+	Key string `json:"key,omitempty"`
+	// SHA256 is the hex-encoded SHA-256 digest of the key.
+	SHA256 string `json:"sha256,omitempty"`
+	// Items maps item IDs, or AnyItem, to roles.
+	Items map[string]Role `json:"items"`
+}
+
+// matches reports whether key is this API key, in constant time.
+func (k *APIKey) matches(key string) bool {
+	sum := sha256.Sum256([]byte(key))
+	want, err := hex.DecodeString(k.SHA256)
+	if err != nil || len(want) != len(sum) {
+		want = nil
+		if k.Key != "" {
+			s := sha256.Sum256([]byte(k.Key))
+			want = s[:]
+		}
+	}
+	return want != nil && subtle.ConstantTimeCompare(sum[:], want) == 1
+}
+
+// OIDCConfig configures the verification of OpenID Connect ID tokens.
+type OIDCConfig struct {
+	// Issuer is the expected "iss" claim. The signing keys are discovered
+	// from its /.well-known/openid-configuration document.
+	Issuer string `json:"issuer"`
+	// Audience is the expected "aud" claim.
+	Audience string `json:"audience"`
+	// SubjectClaim is the claim matched against Principal.Subject. If
+	// empty, "sub" is used.
+	SubjectClaim string `json:"subjectClaim,omitempty"`
+	// JWKSURL overrides the discovered JSON Web Key Set URL.
+	JWKSURL string `json:"jwksUrl,omitempty"`
+}
+
+// Principal grants roles to the subject of ID tokens.
+type Principal struct {
+	// Subject is the value of the subject claim, or AnySubject.
+	Subject string `json:"subject"`
+	// Items maps item IDs, or AnyItem, to roles.
+	Items map[string]Role `json:"items"`
+}
+
+// LoadConfig reads a JSON gateway configuration file. Environment variables
+// referenced as $VAR or ${VAR} in the publisher ID and keys are expanded.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("gateway: failed to read config: %w", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("gateway: failed to parse config: %w", err)
+	}
+
+	config.PublisherID = os.ExpandEnv(config.PublisherID)
+	for i := range config.APIKeys {
+		k := &config.APIKeys[i]
+		k.Key = os.ExpandEnv(k.Key)
+		k.SHA256 = strings.ToLower(os.ExpandEnv(k.SHA256))
+	}
+	return &config, config.Validate()
+}
+
+// Validate checks that the configuration is usable.
+func (c *Config) Validate() error {
+	if len(c.APIKeys) == 0 && c.OIDC == nil {
+		return fmt.Errorf("gateway: no API keys or OIDC configured")
+	}
+	for i, k := range c.APIKeys {
+		if k.Name == "" {
+			return fmt.Errorf("gateway: API key %d has no name", i)
+		}
+		if k.Key == "" && k.SHA256 == "" {
+			return fmt.Errorf("gateway: API key %s has no key or sha256", k.Name)
+		}
+		if k.SHA256 != "" {
+			if b, err := hex.DecodeString(k.SHA256); err != nil || len(b) != sha256.Size {
+				return fmt.Errorf("gateway: API key %s has an invalid sha256", k.Name)
+			}
+		}
+		if err := validateItems(k.Items); err != nil {
+			return fmt.Errorf("gateway: API key %s: %w", k.Name, err)
+		}
+	}
+	if c.OIDC != nil {
+		if c.OIDC.Issuer == "" || c.OIDC.Audience == "" {
+			return fmt.Errorf("gateway: oidc requires an issuer and an audience")
+		}
+	}
+	for i, p := range c.Principals {
+		if p.Subject == "" {
+			return fmt.Errorf("gateway: principal %d has no subject", i)
+		}
+		if err := validateItems(p.Items); err != nil {
+			return fmt.Errorf("gateway: principal %s: %w", p.Subject, err)
+		}
+	}
+	if c.MaxUploadBytes < 0 {
+		return fmt.Errorf("gateway: maxUploadBytes must not be negative")
+	}
+	return nil
+}
+
+// validateItems checks the roles of an item map.
+func validateItems(items map[string]Role) error {
+	for item, role := range items {
+		if role.level() == 0 {
+			return fmt.Errorf("unknown role %q for item %s", role, item)
+		}
+	}
+	return nil
+}
+
+// roleFor returns the role granted on itemID by items. A role for the item
+// takes precedence over a role for AnyItem.
+func roleFor(items map[string]Role, itemID string) Role {
+	if role, ok := items[itemID]; ok {
+		return role
+	}
+	return items[AnyItem]
+}
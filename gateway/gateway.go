@@ -0,0 +1,349 @@
+// Package gateway implements an authenticated HTTP/JSON API in front of the
+// Chrome Web Store API, so that callers can fetch statuses, upload and
+// publish items without holding the publisher's OAuth credentials. Callers
+// authenticate with API keys or OpenID Connect ID tokens and are granted
+// read-only or publisher roles per item.
+package gateway
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/audit"
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/H0R15H0/chrome-webstore-api-v2/policy"
+)
+
+// APIKeyHeader is the header carrying an API key.
+const APIKeyHeader = "X-API-Key"
+
+//go:embed openapi.json
+var openAPI []byte
+
+// OpenAPI returns the OpenAPI 3 description of the gateway's API.
+func OpenAPI() []byte {
+	return openAPI
+}
+
+// Server serves the gateway API.
+type Server struct {
+	// Client performs the Chrome Web Store API calls.
+	Client *chromewebstore.Client
+	// Config configures authentication, roles and limits.
+	Config *Config
+	// HTTPClient fetches OIDC signing keys. If nil, http.DefaultClient is
+	// used.
+	HTTPClient *http.Client
+	// Logger, if set, logs every API request with its caller and outcome.
+	Logger *log.Logger
+
+	once     sync.Once
+	verifier *verifier
+	mux      *http.ServeMux
+}
+
+// NewServer returns a server for config using client.
+func NewServer(client *chromewebstore.Client, config *Config) (*Server, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if config.PublisherID == "" {
+		return nil, fmt.Errorf("gateway: publisher ID is required")
+	}
+	return &Server{Client: client, Config: config}, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.once.Do(s.init)
+	s.mux.ServeHTTP(w, r)
+}
+
+// init registers the routes and creates the OIDC verifier.
+func (s *Server) init() {
+	if s.Config.OIDC != nil {
+		s.verifier = newVerifier(*s.Config.OIDC, s.HTTPClient)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openAPI)
+	})
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.Handle("GET /v1/items/{itemId}/status", s.handle(RoleReader, s.status))
+	mux.Handle("POST /v1/items/{itemId}/upload", s.handle(RolePublisher, s.upload))
+	mux.Handle("POST /v1/items/{itemId}/publish", s.handle(RolePublisher, s.publish))
+	mux.Handle("POST /v1/items/{itemId}/cancel", s.handle(RolePublisher, s.cancel))
+	mux.Handle("POST /v1/items/{itemId}/deploy-percentage", s.handle(RolePublisher, s.setDeployPercentage))
+	s.mux = mux
+}
+
+// caller is an authenticated caller.
+type caller struct {
+	// name identifies the caller in logs.
+	name  string
+	items map[string]Role
+}
+
+// errUnauthenticated is returned when a request has no valid credentials.
+var errUnauthenticated = errors.New("missing or invalid credentials")
+
+// authenticate returns the caller of r.
+func (s *Server) authenticate(r *http.Request) (*caller, error) {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		for _, k := range s.Config.APIKeys {
+			if k.matches(key) {
+				return &caller{name: "key:" + k.Name, items: k.Items}, nil
+			}
+		}
+		return nil, errUnauthenticated
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || s.verifier == nil {
+		return nil, errUnauthenticated
+	}
+	subject, err := s.verifier.verify(r.Context(), strings.TrimSpace(token))
+	if err != nil {
+		if errors.Is(err, errInvalidToken) {
+			return nil, fmt.Errorf("%w: %v", errUnauthenticated, err)
+		}
+		return nil, err
+	}
+
+	// Roles granted to AnySubject are overridden by those granted to the
+	// subject itself.
+	items := make(map[string]Role)
+	for _, p := range s.Config.Principals {
+		if p.Subject == AnySubject {
+			maps.Copy(items, p.Items)
+		}
+	}
+	for _, p := range s.Config.Principals {
+		if p.Subject == subject {
+			maps.Copy(items, p.Items)
+		}
+	}
+	return &caller{name: "oidc:" + subject, items: items}, nil
+}
+
+// handlerFunc handles an authorized API request for item and returns the
+// response body.
+type handlerFunc func(w http.ResponseWriter, r *http.Request, item chromewebstore.ItemName) (any, error)
+
+// handle authenticates the caller, checks that it has at least role on the
+// requested item and calls h.
+func (s *Server) handle(role Role, h handlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		itemID := r.PathValue("itemId")
+		c, err := s.authenticate(r)
+		if err != nil {
+			if errors.Is(err, errUnauthenticated) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="cws"`)
+				s.fail(w, r, "-", itemID, http.StatusUnauthorized, err)
+			} else {
+				s.fail(w, r, "-", itemID, http.StatusServiceUnavailable, err)
+			}
+			return
+		}
+		if err := chromewebstore.ValidateItemID(itemID); err != nil {
+			s.fail(w, r, c.name, itemID, http.StatusBadRequest, err)
+			return
+		}
+		if roleFor(c.items, itemID).level() < role.level() {
+			s.fail(w, r, c.name, itemID, http.StatusForbidden, fmt.Errorf("%s requires the %s role on item %s", r.URL.Path, role, itemID))
+			return
+		}
+
+		// Operations are attributed to the caller in the audit log when the
+		// client records them.
+		r = r.WithContext(audit.WithActor(r.Context(), audit.Actor{Name: c.name, Source: "gateway"}))
+		result, err := h(w, r, chromewebstore.NewItemName(s.Config.PublisherID, itemID))
+		if err != nil {
+			s.fail(w, r, c.name, itemID, errorStatus(err), err)
+			return
+		}
+		s.logf(r, c.name, itemID, http.StatusOK, nil)
+		writeJSON(w, http.StatusOK, result)
+	})
+}
+
+// errorStatus returns the HTTP status code reported for err.
+func errorStatus(err error) int {
+	var apiErr *chromewebstore.APIError
+	var maxErr *http.MaxBytesError
+	var reqErr *requestError
+	var deployErr *chromewebstore.DeployPercentageError
+	var policyErr *policy.ViolationError
+	switch {
+	case errors.As(err, &maxErr):
+		return http.StatusRequestEntityTooLarge
+	case errors.As(err, &reqErr):
+		return http.StatusBadRequest
+	case errors.As(err, &deployErr):
+		return http.StatusConflict
+	case errors.As(err, &policyErr):
+		return http.StatusForbidden
+	case errors.As(err, &apiErr) && apiErr.StatusCode != http.StatusUnauthorized:
+		// A 401 from the store concerns the gateway's own credentials,
+		// which is not the caller's fault.
+		return apiErr.StatusCode
+	}
+	return http.StatusBadGateway
+}
+
+// requestError is an invalid request body or parameter.
+type requestError struct {
+	err error
+}
+
+func (e *requestError) Error() string { return e.err.Error() }
+
+func (e *requestError) Unwrap() error { return e.err }
+
+// status fetches the status of an item.
+func (s *Server) status(w http.ResponseWriter, r *http.Request, item chromewebstore.ItemName) (any, error) {
+	call := s.Client.Publishers.Items.FetchStatus(item).Context(r.Context())
+	if projection := r.URL.Query().Get("projection"); projection != "" {
+		call.Projection(projection)
+	}
+	return call.Do()
+}
+
+// upload streams the request body to the store as a package.
+func (s *Server) upload(w http.ResponseWriter, r *http.Request, item chromewebstore.ItemName) (any, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (mediaType != chromewebstore.MediaTypeZIP && mediaType != chromewebstore.MediaTypeCRX) {
+		return nil, &requestError{fmt.Errorf("Content-Type must be %s or %s", chromewebstore.MediaTypeZIP, chromewebstore.MediaTypeCRX)}
+	}
+	limit := s.Config.MaxUploadBytes
+	if limit == 0 {
+		limit = DefaultMaxUploadBytes
+	}
+	if r.ContentLength > limit {
+		return nil, &http.MaxBytesError{Limit: limit}
+	}
+	body := http.MaxBytesReader(w, r.Body, limit)
+	return s.Client.Media.Upload(item).Context(r.Context()).Media(body, mediaType).Do()
+}
+
+// publishRequest is the body of a publish request.
+type publishRequest struct {
+	PublishType      chromewebstore.PublishType `json:"publishType,omitempty"`
+	SkipReview       bool                       `json:"skipReview,omitempty"`
+	DeployPercentage *int                       `json:"deployPercentage,omitempty"`
+}
+
+// publish submits an item for publishing.
+func (s *Server) publish(w http.ResponseWriter, r *http.Request, item chromewebstore.ItemName) (any, error) {
+	var req publishRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	call := s.Client.Publishers.Items.Publish(item).Context(r.Context())
+	if req.PublishType != "" {
+		call.PublishType(req.PublishType)
+	}
+	if req.SkipReview {
+		call.SkipReview(true)
+	}
+	if req.DeployPercentage != nil {
+		call.DeployPercentage(*req.DeployPercentage)
+	}
+	return call.Validate(true).Do()
+}
+
+// cancel cancels the pending submission of an item.
+func (s *Server) cancel(w http.ResponseWriter, r *http.Request, item chromewebstore.ItemName) (any, error) {
+	return s.Client.Publishers.Items.CancelSubmission(item).Context(r.Context()).Do()
+}
+
+// deployPercentageRequest is the body of a deploy percentage request.
+type deployPercentageRequest struct {
+	DeployPercentage *int `json:"deployPercentage"`
+}
+
+// setDeployPercentage sets the published deploy percentage of an item.
+func (s *Server) setDeployPercentage(w http.ResponseWriter, r *http.Request, item chromewebstore.ItemName) (any, error) {
+	var req deployPercentageRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	if req.DeployPercentage == nil {
+		return nil, &requestError{fmt.Errorf("deployPercentage is required")}
+	}
+	return s.Client.Publishers.Items.SetPublishedDeployPercentage(item).Context(r.Context()).
+		DeployPercentage(*req.DeployPercentage).Validate(true).Do()
+}
+
+// maxJSONBytes limits the size of JSON request bodies.
+const maxJSONBytes = 64 << 10
+
+// decodeBody decodes an optional JSON request body into v.
+func decodeBody(r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxJSONBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return err
+		}
+		return &requestError{fmt.Errorf("invalid request body: %w", err)}
+	}
+	return nil
+}
+
+// errorResponse is the body of error responses, in the format of Google
+// API errors.
+type errorResponse struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// fail writes an error response and logs it.
+func (s *Server) fail(w http.ResponseWriter, r *http.Request, name, itemID string, code int, err error) {
+	s.logf(r, name, itemID, code, err)
+	detail := errorDetail{Code: code, Message: err.Error()}
+	var apiErr *chromewebstore.APIError
+	if errors.As(err, &apiErr) {
+		detail.RequestID = apiErr.RequestID()
+	}
+	writeJSON(w, code, errorResponse{Error: detail})
+}
+
+// logf logs an API request.
+func (s *Server) logf(r *http.Request, name, itemID string, code int, err error) {
+	if s.Logger == nil {
+		return
+	}
+	if err != nil {
+		s.Logger.Printf("%s %s caller=%s item=%s status=%d error=%q", r.Method, r.URL.Path, name, itemID, code, err)
+		return
+	}
+	s.Logger.Printf("%s %s caller=%s item=%s status=%d", r.Method, r.URL.Path, name, itemID, code)
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
@@ -0,0 +1,266 @@
+package gateway
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/audit"
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/H0R15H0/chrome-webstore-api-v2/policy"
+)
+
+const (
+	testItem  = "abcdefghijklmnopabcdefghijklmnop"
+	otherItem = "ponmlkjihgfedcbaponmlkjihgfedcba"
+)
+
+// newTestGateway returns a gateway backed by a fake store. The fake store
+// records the paths it receives in calls.
+func newTestGateway(t *testing.T, config *Config, calls *[]string) *httptest.Server {
+	return newAuditedTestGateway(t, config, calls, nil)
+}
+
+// newAuditedTestGateway is like newTestGateway, recording the operations in
+// log if it is not nil.
+func newAuditedTestGateway(t *testing.T, config *Config, calls *[]string, log *audit.Log) *httptest.Server {
+	t.Helper()
+	store := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*calls = append(*calls, r.Method+" "+r.URL.Path+" "+string(body))
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, ":fetchStatus"):
+			json.NewEncoder(w).Encode(map[string]any{
+				"itemId": testItem,
+				"publishedItemRevisionStatus": map[string]any{
+					"state":                "PUBLISHED",
+					"distributionChannels": []map[string]any{{"deployPercentage": 10, "crxVersion": "1.0"}},
+				},
+			})
+		case strings.HasSuffix(r.URL.Path, ":upload"):
+			json.NewEncoder(w).Encode(map[string]any{"itemId": testItem, "uploadState": "SUCCEEDED"})
+		case strings.HasSuffix(r.URL.Path, ":publish"):
+			json.NewEncoder(w).Encode(map[string]any{"itemId": testItem, "state": "PENDING_REVIEW"})
+		case strings.HasSuffix(r.URL.Path, ":cancelSubmission"):
+			w.Header().Set(chromewebstore.RequestIDHeader, "req-1")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":404,"message":"no pending submission"}}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(store.Close)
+
+	httpClient := store.Client()
+	if log != nil {
+		httpClient.Transport = &audit.Transport{Base: httpClient.Transport, Log: log}
+	}
+	client := chromewebstore.NewClient(httpClient)
+	client.SetBaseURL(store.URL)
+	client.SetUploadBaseURL(store.URL)
+	server, err := NewServer(client, config)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	gw := httptest.NewServer(server)
+	t.Cleanup(gw.Close)
+	return gw
+}
+
+func testConfig() *Config {
+	sum := sha256.Sum256([]byte("publisher-key"))
+	return &Config{
+		PublisherID:    "pub",
+		MaxUploadBytes: 16,
+		APIKeys: []APIKey{
+			{Name: "reader", Key: "reader-key", Items: map[string]Role{AnyItem: RoleReader}},
+			{Name: "ci", SHA256: hex.EncodeToString(sum[:]), Items: map[string]Role{testItem: RolePublisher, AnyItem: RoleReader}},
+		},
+	}
+}
+
+func doRequest(t *testing.T, method, url, key, contentType, body string) (int, map[string]any) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "" {
+		req.Header.Set(APIKeyHeader, key)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var result map[string]any
+	json.NewDecoder(resp.Body).Decode(&result)
+	return resp.StatusCode, result
+}
+
+func TestGatewayRoles(t *testing.T) {
+	var calls []string
+	gw := newTestGateway(t, testConfig(), &calls)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		key    string
+		body   string
+		want   int
+	}{
+		{"no key", "GET", "/v1/items/" + testItem + "/status", "", "", http.StatusUnauthorized},
+		{"wrong key", "GET", "/v1/items/" + testItem + "/status", "nope", "", http.StatusUnauthorized},
+		{"reader status", "GET", "/v1/items/" + testItem + "/status", "reader-key", "", http.StatusOK},
+		{"reader publish", "POST", "/v1/items/" + testItem + "/publish", "reader-key", "", http.StatusForbidden},
+		{"publisher publish", "POST", "/v1/items/" + testItem + "/publish", "publisher-key", `{"publishType":"STAGED_PUBLISH","deployPercentage":20}`, http.StatusOK},
+		{"negative publish percentage", "POST", "/v1/items/" + testItem + "/publish", "publisher-key", `{"deployPercentage":-10}`, http.StatusConflict},
+		{"publisher on other item", "POST", "/v1/items/" + otherItem + "/publish", "publisher-key", "", http.StatusForbidden},
+		{"invalid item", "GET", "/v1/items/xyz/status", "reader-key", "", http.StatusBadRequest},
+		{"unknown field", "POST", "/v1/items/" + testItem + "/publish", "publisher-key", `{"target":"x"}`, http.StatusBadRequest},
+		{"lower percentage", "POST", "/v1/items/" + testItem + "/deploy-percentage", "publisher-key", `{"deployPercentage":5}`, http.StatusConflict},
+		{"missing percentage", "POST", "/v1/items/" + testItem + "/deploy-percentage", "publisher-key", `{}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, body := doRequest(t, tt.method, gw.URL+tt.path, tt.key, "", tt.body)
+			if code != tt.want {
+				t.Errorf("expected status %d, got %d (%v)", tt.want, code, body)
+			}
+		})
+	}
+
+	var published bool
+	for _, c := range calls {
+		if strings.Contains(c, ":publish") {
+			published = true
+			if !strings.Contains(c, `"publishType":"STAGED_PUBLISH"`) || !strings.Contains(c, `"deployPercentage":20`) {
+				t.Errorf("expected staged publish at 20%%, got %s", c)
+			}
+		}
+	}
+	if !published {
+		t.Errorf("expected a publish call, got %v", calls)
+	}
+}
+
+func TestGatewayUpload(t *testing.T) {
+	var calls []string
+	gw := newTestGateway(t, testConfig(), &calls)
+	url := gw.URL + "/v1/items/" + testItem + "/upload"
+
+	code, body := doRequest(t, "POST", url, "publisher-key", chromewebstore.MediaTypeZIP, "PK-small")
+	if code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d (%v)", code, body)
+	}
+	if body["uploadState"] != "SUCCEEDED" {
+		t.Errorf("expected upload state SUCCEEDED, got %v", body["uploadState"])
+	}
+	if len(calls) != 1 || !strings.HasSuffix(calls[0], " PK-small") {
+		t.Errorf("expected the package to be forwarded, got %v", calls)
+	}
+
+	code, _ = doRequest(t, "POST", url, "publisher-key", chromewebstore.MediaTypeZIP, strings.Repeat("x", 17))
+	if code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413, got %d", code)
+	}
+
+	code, _ = doRequest(t, "POST", url, "publisher-key", "text/plain", "PK")
+	if code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", code)
+	}
+}
+
+func TestGatewayStoreError(t *testing.T) {
+	var calls []string
+	gw := newTestGateway(t, testConfig(), &calls)
+
+	code, body := doRequest(t, "POST", gw.URL+"/v1/items/"+testItem+"/cancel", "publisher-key", "", "")
+	if code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", code)
+	}
+	detail, _ := body["error"].(map[string]any)
+	if detail["requestId"] != "req-1" {
+		t.Errorf("expected request ID req-1, got %v", detail["requestId"])
+	}
+	if !strings.Contains(detail["message"].(string), "no pending submission") {
+		t.Errorf("expected the store's message, got %v", detail["message"])
+	}
+}
+
+func TestGatewayPolicyError(t *testing.T) {
+	err := fmt.Errorf("publish: %w", &policy.ViolationError{Operation: chromewebstore.PolicyOperationPublish})
+	if code := errorStatus(err); code != http.StatusForbidden {
+		t.Errorf("expected status 403 for policy violations, got %d", code)
+	}
+}
+
+func TestGatewayAuditActor(t *testing.T) {
+	var calls []string
+	log := audit.New(filepath.Join(t.TempDir(), audit.FileName))
+	gw := newAuditedTestGateway(t, testConfig(), &calls, log)
+
+	code, _ := doRequest(t, "POST", gw.URL+"/v1/items/"+testItem+"/publish", "publisher-key", "", "")
+	if code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+	entries, err := log.Verify()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if want := (audit.Actor{Name: "key:ci", Source: "gateway"}); entries[0].Actor != want {
+		t.Errorf("expected actor %+v, got %+v", want, entries[0].Actor)
+	}
+}
+
+func TestGatewayOpenAPI(t *testing.T) {
+	var calls []string
+	gw := newTestGateway(t, testConfig(), &calls)
+
+	code, body := doRequest(t, "GET", gw.URL+"/openapi.json", "", "", "")
+	if code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+	paths, _ := body["paths"].(map[string]any)
+	for _, p := range []string{"/v1/items/{itemId}/status", "/v1/items/{itemId}/upload", "/v1/items/{itemId}/publish", "/v1/items/{itemId}/cancel", "/v1/items/{itemId}/deploy-percentage"} {
+		if _, ok := paths[p]; !ok {
+			t.Errorf("expected path %s in the OpenAPI description", p)
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{"no auth", Config{}},
+		{"unnamed key", Config{APIKeys: []APIKey{{Key: "k"}}}},
+		{"empty key", Config{APIKeys: []APIKey{{Name: "k"}}}},
+		{"bad digest", Config{APIKeys: []APIKey{{Name: "k", SHA256: "abc"}}}},
+		{"unknown role", Config{APIKeys: []APIKey{{Name: "k", Key: "k", Items: map[string]Role{AnyItem: "admin"}}}}},
+		{"oidc without audience", Config{OIDC: &OIDCConfig{Issuer: "https://issuer"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
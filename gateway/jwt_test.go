@@ -0,0 +1,137 @@
+package gateway
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testIssuer is an OpenID Connect issuer signing tokens with an RSA key.
+type testIssuer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iss := &testIssuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"jwks_uri": iss.URL + "/jwks"})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "k1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	iss.Server = httptest.NewServer(mux)
+	t.Cleanup(iss.Close)
+	return iss
+}
+
+// sign returns an RS256 token with the given key ID and claims.
+func (iss *testIssuer) sign(t *testing.T, kid string, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(input))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, iss.key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestVerifier(t *testing.T) {
+	iss := newTestIssuer(t)
+	v := newVerifier(OIDCConfig{Issuer: iss.URL, Audience: "cws", SubjectClaim: "email"}, nil)
+	exp := time.Now().Add(time.Hour).Unix()
+
+	valid := iss.sign(t, "k1", map[string]any{"iss": iss.URL, "aud": []string{"other", "cws"}, "exp": exp, "email": "dev@example.com"})
+	subject, err := v.verify(context.Background(), valid)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if subject != "dev@example.com" {
+		t.Errorf("expected subject dev@example.com, got %s", subject)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"wrong issuer", iss.sign(t, "k1", map[string]any{"iss": "https://evil", "aud": "cws", "exp": exp, "email": "a"})},
+		{"wrong audience", iss.sign(t, "k1", map[string]any{"iss": iss.URL, "aud": "other", "exp": exp, "email": "a"})},
+		{"expired", iss.sign(t, "k1", map[string]any{"iss": iss.URL, "aud": "cws", "exp": time.Now().Add(-time.Hour).Unix(), "email": "a"})},
+		{"no subject", iss.sign(t, "k1", map[string]any{"iss": iss.URL, "aud": "cws", "exp": exp})},
+		{"unknown key", iss.sign(t, "k2", map[string]any{"iss": iss.URL, "aud": "cws", "exp": exp, "email": "a"})},
+		{"tampered", strings.Replace(valid, ".", ".e30", 1)},
+		{"malformed", "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := v.verify(context.Background(), tt.token); !errors.Is(err, errInvalidToken) {
+				t.Errorf("expected an invalid token error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestGatewayOIDC(t *testing.T) {
+	iss := newTestIssuer(t)
+	config := &Config{
+		PublisherID: "pub",
+		OIDC:        &OIDCConfig{Issuer: iss.URL, Audience: "cws"},
+		Principals: []Principal{
+			{Subject: AnySubject, Items: map[string]Role{AnyItem: RoleReader}},
+			{Subject: "release-bot", Items: map[string]Role{testItem: RolePublisher}},
+		},
+	}
+	var calls []string
+	gw := newTestGateway(t, config, &calls)
+	exp := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		subject string
+		method  string
+		action  string
+		want    int
+	}{
+		{"anyone", "GET", "status", http.StatusOK},
+		{"anyone", "POST", "cancel", http.StatusForbidden},
+		{"release-bot", "POST", "publish", http.StatusOK},
+	}
+	for _, tt := range tests {
+		token := iss.sign(t, "k1", map[string]any{"iss": iss.URL, "aud": "cws", "exp": exp, "sub": tt.subject})
+		req, _ := http.NewRequest(tt.method, gw.URL+"/v1/items/"+testItem+"/"+tt.action, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.want {
+			t.Errorf("%s %s: expected status %d, got %d", tt.subject, tt.action, tt.want, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Package history keeps an append-only local record of item statuses and
+// of upload and publish results, and derives timelines and review
+// turnaround times from it.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+// Kind identifies what a Record holds.
+type Kind string
+
+const (
+	// KindStatus records a fetched item status.
+	KindStatus Kind = "status"
+	// KindUpload records an upload result.
+	KindUpload Kind = "upload"
+	// KindPublish records a publish result.
+	KindPublish Kind = "publish"
+	// KindDeployPercentage records a deploy percentage change.
+	KindDeployPercentage Kind = "deploy-percentage"
+)
+
+// Record is a single history entry. Exactly one of Status, Upload, Publish
+// and DeployPercentage is set, according to Kind.
+type Record struct {
+	// Time is when the result was received.
+	Time time.Time `json:"time"`
+	// Item is the item the record belongs to.
+	Item chromewebstore.ItemName `json:"item"`
+	// Kind is the kind of record.
+	Kind Kind `json:"kind"`
+	// Status is the fetched status for KindStatus.
+	Status *chromewebstore.ItemStatus `json:"status,omitempty"`
+	// Upload is the upload result for KindUpload.
+	Upload *chromewebstore.UploadResponse `json:"upload,omitempty"`
+	// Publish is the publish result for KindPublish.
+	Publish *chromewebstore.PublishResponse `json:"publish,omitempty"`
+	// DeployPercentage is the requested percentage for KindDeployPercentage.
+	DeployPercentage int `json:"deployPercentage,omitempty"`
+}
+
+// Store is a history file in JSON Lines format.
+type Store struct {
+	path string
+}
+
+// FileName is the name of the history file in the default location.
+const FileName = "history.jsonl"
+
+// DefaultPath returns the default history file location:
+// $CWS_HISTORY_FILE if set, otherwise cws/history.jsonl in the user
+// configuration directory.
+func DefaultPath() (string, error) {
+	if path := os.Getenv("CWS_HISTORY_FILE"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("history: %w", err)
+	}
+	return filepath.Join(dir, "cws", FileName), nil
+}
+
+// New returns a store backed by the file at path. The file is created on
+// the first Append.
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+// Path returns the path of the history file.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Append adds r to the history. If r.Time is zero, the current time is used.
+func (s *Store) Append(r Record) error {
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	line, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("history: failed to marshal record: %w", err)
+	}
+	line = append(line, '\n')
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return fmt.Errorf("history: %w", err)
+	}
+	return f.Close()
+}
+
+// Records returns the records of the given items in chronological order.
+// If no items are given, records of all items are returned. A missing
+// history file yields no records.
+func (s *Store) Records(items ...chromewebstore.ItemName) ([]Record, error) {
+	f, err := os.Open(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("history: %s:%d: %w", s.path, line, err)
+		}
+		if len(items) == 0 || slices.Contains(items, r.Item) {
+			records = append(records, r)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+
+	slices.SortStableFunc(records, func(a, b Record) int {
+		return a.Time.Compare(b.Time)
+	})
+	return records, nil
+}
+
+// Items returns the distinct items in records, in order of first appearance.
+func Items(records []Record) []chromewebstore.ItemName {
+	var items []chromewebstore.ItemName
+	for _, r := range records {
+		if !slices.Contains(items, r.Item) {
+			items = append(items, r.Item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,158 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+var (
+	itemA = chromewebstore.NewItemName("pub", "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	itemB = chromewebstore.NewItemName("pub", "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+	start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func statusRecord(item chromewebstore.ItemName, hours int, submitted, published *chromewebstore.ItemRevisionStatus) Record {
+	return Record{
+		Time: start.Add(time.Duration(hours) * time.Hour),
+		Item: item,
+		Kind: KindStatus,
+		Status: &chromewebstore.ItemStatus{
+			SubmittedItemRevisionStatus: submitted,
+			PublishedItemRevisionStatus: published,
+		},
+	}
+}
+
+func revision(state chromewebstore.ItemState, version string, percentage int) *chromewebstore.ItemRevisionStatus {
+	return &chromewebstore.ItemRevisionStatus{
+		State:                state,
+		DistributionChannels: []chromewebstore.DistributionChannel{{CrxVersion: version, DeployPercentage: percentage}},
+	}
+}
+
+func TestStore(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "cws", FileName))
+
+	records, err := s.Records()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("expected no records, got %d", len(records))
+	}
+
+	later := statusRecord(itemA, 2, nil, revision(chromewebstore.ItemStatePublished, "1.0", 100))
+	if err := s.Append(later); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Append(Record{Time: start, Item: itemA, Kind: KindUpload, Upload: &chromewebstore.UploadResponse{CrxVersion: "1.0"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Append(Record{Item: itemB, Kind: KindDeployPercentage, DeployPercentage: 50}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records, err = s.Records(itemA)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	if records[0].Kind != KindUpload || records[1].Kind != KindStatus {
+		t.Errorf("expected records in chronological order, got %s, %s", records[0].Kind, records[1].Kind)
+	}
+
+	all, err := s.Records()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("expected 3 records, got %d", len(all))
+	}
+	if all[2].Time.IsZero() {
+		t.Error("expected Append to set the time")
+	}
+	if items := Items(all); len(items) != 2 {
+		t.Errorf("expected 2 items, got %v", items)
+	}
+}
+
+func TestStoreCorruptLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte("{\"kind\":\"status\"}\nnot json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := New(path).Records()
+	if err == nil || !strings.Contains(err.Error(), ":2:") {
+		t.Errorf("expected error for line 2, got %v", err)
+	}
+}
+
+func TestReviews(t *testing.T) {
+	records := []Record{
+		statusRecord(itemA, 0, nil, revision(chromewebstore.ItemStatePublished, "1.0", 100)),
+		{Time: start.Add(time.Hour), Item: itemA, Kind: KindPublish, Publish: &chromewebstore.PublishResponse{State: chromewebstore.ItemStatePendingReview}},
+		statusRecord(itemA, 2, revision(chromewebstore.ItemStatePendingReview, "1.1", 0), revision(chromewebstore.ItemStatePublished, "1.0", 100)),
+		statusRecord(itemB, 3, revision(chromewebstore.ItemStatePendingReview, "2.0", 0), nil),
+		statusRecord(itemA, 25, nil, revision(chromewebstore.ItemStatePublished, "1.1", 100)),
+		statusRecord(itemA, 30, revision(chromewebstore.ItemStatePendingReview, "1.2", 0), revision(chromewebstore.ItemStatePublished, "1.1", 100)),
+		statusRecord(itemA, 40, revision(chromewebstore.ItemStateRejected, "1.2", 0), revision(chromewebstore.ItemStatePublished, "1.1", 100)),
+		statusRecord(itemA, 41, revision(chromewebstore.ItemStateRejected, "1.2", 0), revision(chromewebstore.ItemStatePublished, "1.1", 100)),
+	}
+
+	reviews := Reviews(records)
+	if len(reviews) != 3 {
+		t.Fatalf("expected 3 reviews, got %d: %+v", len(reviews), reviews)
+	}
+
+	if r := reviews[0]; r.Item != itemA || r.Version != "1.1" || r.Outcome != chromewebstore.ItemStatePublished || r.Turnaround() != 24*time.Hour {
+		t.Errorf("unexpected first review: %+v", r)
+	}
+	if r := reviews[1]; r.Version != "1.2" || r.Outcome != chromewebstore.ItemStateRejected || r.Turnaround() != 10*time.Hour {
+		t.Errorf("unexpected second review: %+v", r)
+	}
+	if r := reviews[2]; r.Item != itemB || !r.Pending() || r.Turnaround() != 0 {
+		t.Errorf("expected pending review of item B, got %+v", r)
+	}
+
+	avg, n := AverageTurnaround(reviews)
+	if n != 2 || avg != 17*time.Hour {
+		t.Errorf("expected average 17h over 2 reviews, got %s over %d", avg, n)
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	records := []Record{
+		statusRecord(itemA, 0, revision(chromewebstore.ItemStatePendingReview, "1.1", 0), nil),
+		statusRecord(itemA, 1, revision(chromewebstore.ItemStatePendingReview, "1.1", 0), nil),
+		statusRecord(itemA, 2, nil, revision(chromewebstore.ItemStatePublished, "1.1", 10)),
+		{Time: start.Add(3 * time.Hour), Item: itemA, Kind: KindDeployPercentage, DeployPercentage: 50},
+	}
+
+	entries := Timeline(records)
+	var messages []string
+	for _, e := range entries {
+		messages = append(messages, e.Message)
+	}
+
+	expected := []string{
+		"status: submitted PENDING_REVIEW 1.1 (0%)",
+		"submitted STATE PENDING_REVIEW -> (none)",
+		"submitted VERSION 1.1 -> (none)",
+		"submitted DEPLOY_PERCENTAGE 0 -> (none)",
+		"published STATE (none) -> PUBLISHED",
+		"published VERSION (none) -> 1.1",
+		"published DEPLOY_PERCENTAGE (none) -> 10",
+		"deploy percentage set to 50%",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected timeline:\n%s", strings.Join(messages, "\n"))
+	}
+}
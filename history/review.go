@@ -0,0 +1,131 @@
+package history
+
+import (
+	"slices"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+// Review is a single pass of an item revision through review.
+//
+// Times are when the transitions were observed, so their accuracy is
+// bounded by how often the status was recorded.
+type Review struct {
+	// Item is the reviewed item.
+	Item chromewebstore.ItemName `json:"item"`
+	// Version is the reviewed CRX version, empty if never observed.
+	Version string `json:"version,omitempty"`
+	// Submitted is when the revision was first seen in PENDING_REVIEW.
+	Submitted time.Time `json:"submitted"`
+	// Decided is when the review outcome was first seen. It is zero while
+	// the review is still pending.
+	Decided time.Time `json:"decided,omitzero"`
+	// Outcome is the state the revision left review in: PUBLISHED, STAGED,
+	// PUBLISHED_TO_TESTERS or REJECTED. It is empty while pending.
+	Outcome chromewebstore.ItemState `json:"outcome,omitempty"`
+}
+
+// Pending reports whether the review has no outcome yet.
+func (r Review) Pending() bool {
+	return r.Outcome == ""
+}
+
+// Turnaround returns the time from submission to decision, or zero if the
+// review is pending.
+func (r Review) Turnaround() time.Duration {
+	if r.Pending() {
+		return 0
+	}
+	return r.Decided.Sub(r.Submitted)
+}
+
+// Reviews derives review passes from status and publish records, which
+// must be in chronological order.
+func Reviews(records []Record) []Review {
+	var reviews []Review
+	pending := make(map[chromewebstore.ItemName]*Review)
+	var order []chromewebstore.ItemName
+
+	for _, r := range records {
+		state, version, ok := submissionState(r)
+		if !ok {
+			continue
+		}
+
+		p := pending[r.Item]
+		switch state {
+		case chromewebstore.ItemStatePendingReview:
+			if p == nil {
+				pending[r.Item] = &Review{Item: r.Item, Version: version, Submitted: r.Time}
+				if !slices.Contains(order, r.Item) {
+					order = append(order, r.Item)
+				}
+			} else if p.Version == "" {
+				p.Version = version
+			}
+		case chromewebstore.ItemStatePublished, chromewebstore.ItemStateStaged,
+			chromewebstore.ItemStatePublishedToTesters, chromewebstore.ItemStateRejected:
+			if p == nil || (p.Version != "" && version != "" && p.Version != version) {
+				continue
+			}
+			if p.Version == "" {
+				p.Version = version
+			}
+			p.Decided = r.Time
+			p.Outcome = state
+			reviews = append(reviews, *p)
+			delete(pending, r.Item)
+		case chromewebstore.ItemStateCancelled:
+			delete(pending, r.Item)
+		}
+	}
+
+	for _, item := range order {
+		if p := pending[item]; p != nil {
+			reviews = append(reviews, *p)
+		}
+	}
+	return reviews
+}
+
+// submissionState returns the state and version of the latest submission
+// described by r.
+func submissionState(r Record) (chromewebstore.ItemState, string, bool) {
+	switch {
+	case r.Kind == KindPublish && r.Publish != nil:
+		return r.Publish.State, "", r.Publish.State != ""
+	case r.Kind == KindStatus && r.Status != nil:
+		rev := r.Status.SubmittedItemRevisionStatus
+		if rev == nil {
+			rev = r.Status.PublishedItemRevisionStatus
+		}
+		if rev == nil || rev.State == "" {
+			return "", "", false
+		}
+		var version string
+		if len(rev.DistributionChannels) > 0 {
+			version = rev.DistributionChannels[0].CrxVersion
+		}
+		return rev.State, version, true
+	}
+	return "", "", false
+}
+
+// AverageTurnaround returns the mean turnaround of the decided reviews and
+// their number.
+func AverageTurnaround(reviews []Review) (time.Duration, int) {
+	var total time.Duration
+	var n int
+	for _, r := range reviews {
+		if r.Pending() {
+			continue
+		}
+		total += r.Turnaround()
+		n++
+	}
+	if n == 0 {
+		return 0, 0
+	}
+	return total / time.Duration(n), n
+}
@@ -0,0 +1,109 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+// Entry is a human-readable timeline entry.
+type Entry struct {
+	// Time is when the entry was recorded.
+	Time time.Time `json:"time"`
+	// Item is the item the entry belongs to.
+	Item chromewebstore.ItemName `json:"item"`
+	// Message describes what happened.
+	Message string `json:"message"`
+}
+
+// Timeline turns records into timeline entries. Status records are reported
+// as the changes from the previous status of the same item; statuses
+// without changes produce no entries.
+func Timeline(records []Record) []Entry {
+	var entries []Entry
+	last := make(map[chromewebstore.ItemName]*chromewebstore.ItemStatus)
+	add := func(r Record, format string, args ...any) {
+		entries = append(entries, Entry{Time: r.Time, Item: r.Item, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, r := range records {
+		switch r.Kind {
+		case KindStatus:
+			if r.Status == nil {
+				continue
+			}
+			prev, ok := last[r.Item]
+			last[r.Item] = r.Status
+			if !ok {
+				add(r, "status: %s", describeStatus(r.Status))
+				continue
+			}
+			for _, e := range chromewebstore.DiffStatus(r.Item, prev, r.Status, r.Time) {
+				subject := string(e.Type)
+				if e.Revision != "" {
+					subject = e.Revision + " " + subject
+				}
+				add(r, "%s %s -> %s", subject, orNone(e.Old), orNone(e.New))
+			}
+		case KindUpload:
+			if r.Upload == nil {
+				continue
+			}
+			if r.Upload.CrxVersion != "" {
+				add(r, "uploaded %s (%s)", r.Upload.CrxVersion, r.Upload.UploadState)
+			} else {
+				add(r, "uploaded (%s)", r.Upload.UploadState)
+			}
+		case KindPublish:
+			if r.Publish == nil {
+				continue
+			}
+			add(r, "publish requested: %s", r.Publish.State)
+		case KindDeployPercentage:
+			add(r, "deploy percentage set to %d%%", r.DeployPercentage)
+		}
+	}
+	return entries
+}
+
+// describeStatus summarizes the revisions of status.
+func describeStatus(status *chromewebstore.ItemStatus) string {
+	var parts []string
+	for _, rev := range []struct {
+		name string
+		rev  *chromewebstore.ItemRevisionStatus
+	}{
+		{chromewebstore.RevisionSubmitted, status.SubmittedItemRevisionStatus},
+		{chromewebstore.RevisionPublished, status.PublishedItemRevisionStatus},
+	} {
+		if rev.rev == nil {
+			continue
+		}
+		part := fmt.Sprintf("%s %s", rev.name, rev.rev.State)
+		if len(rev.rev.DistributionChannels) > 0 {
+			ch := rev.rev.DistributionChannels[0]
+			part += fmt.Sprintf(" %s (%d%%)", ch.CrxVersion, ch.DeployPercentage)
+		}
+		parts = append(parts, part)
+	}
+	if status.Warned {
+		parts = append(parts, "warned")
+	}
+	if status.TakenDown {
+		parts = append(parts, "taken down")
+	}
+	if len(parts) == 0 {
+		return "(no revisions)"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// orNone returns s, or "(none)" if s is empty.
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
@@ -0,0 +1,111 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+// kinds maps the custom method suffixes of request paths to the kinds of
+// records made from their responses.
+var kinds = map[string]Kind{
+	":fetchStatus":                  KindStatus,
+	":upload":                       KindUpload,
+	":publish":                      KindPublish,
+	":setPublishedDeployPercentage": KindDeployPercentage,
+}
+
+// maxResponseBytes limits the response bodies read for the history.
+const maxResponseBytes = 1 << 20
+
+// Transport is an http.RoundTripper recording the successful responses of
+// status fetches, uploads, publishes and deploy percentage changes sent
+// through it in a Store, whichever code made them: commands, pre-flight
+// validation, watches or rollouts. Use it as the transport of the HTTP
+// client given to chromewebstore.NewClient.
+type Transport struct {
+	// Base performs the requests. If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+	// Store receives the records.
+	Store *Store
+	// OnError, if set, is called when a record cannot be appended. The
+	// response is returned regardless.
+	OnError func(error)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	kind, item, ok := recorded(req)
+	resp, err := base.RoundTrip(req)
+	if !ok || err != nil || resp.StatusCode >= 300 {
+		return resp, err
+	}
+
+	data, readErr := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+	if readErr != nil {
+		return resp, nil
+	}
+
+	r := Record{Time: time.Now(), Item: item, Kind: kind}
+	switch kind {
+	case KindStatus:
+		r.Status = new(chromewebstore.ItemStatus)
+		err = json.Unmarshal(data, r.Status)
+	case KindUpload:
+		r.Upload = new(chromewebstore.UploadResponse)
+		err = json.Unmarshal(data, r.Upload)
+	case KindPublish:
+		r.Publish = new(chromewebstore.PublishResponse)
+		err = json.Unmarshal(data, r.Publish)
+	case KindDeployPercentage:
+		r.DeployPercentage, err = strconv.Atoi(req.URL.Query().Get("deployPercentage"))
+	}
+	if err == nil {
+		err = t.Store.Append(r)
+	}
+	if err != nil && t.OnError != nil {
+		t.OnError(err)
+	}
+	return resp, nil
+}
+
+// recorded returns the kind of record and the item of req, and whether
+// its response is recorded.
+func recorded(req *http.Request) (Kind, chromewebstore.ItemName, bool) {
+	path := req.URL.Path
+	i := strings.LastIndexByte(path, ':')
+	if i < 0 {
+		return "", "", false
+	}
+	kind, ok := kinds[path[i:]]
+	if !ok {
+		return "", "", false
+	}
+	if (kind == KindStatus) != (req.Method == http.MethodGet) {
+		return "", "", false
+	}
+	// Projected and partial statuses omit revisions and would read as
+	// changes.
+	if q := req.URL.Query(); kind == KindStatus && (q.Get("projection") != "" || q.Get("fields") != "") {
+		return "", "", false
+	}
+	j := strings.Index(path, "publishers/")
+	if j < 0 || j > i {
+		return "", "", false
+	}
+	return kind, chromewebstore.ItemName(path[j:i]), true
+}
@@ -0,0 +1,92 @@
+package history
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, ":upload"):
+			w.Write([]byte(`{"crxVersion":"1.1","uploadState":"SUCCEEDED"}`))
+		case strings.HasSuffix(r.URL.Path, ":publish"):
+			w.Write([]byte(`{"state":"PENDING_REVIEW"}`))
+		case strings.HasSuffix(r.URL.Path, ":cancelSubmission"):
+			w.Write([]byte(`{}`))
+		case strings.HasSuffix(r.URL.Path, ":setPublishedDeployPercentage"):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":400,"message":"invalid"}}`))
+		default:
+			w.Write([]byte(`{"publishedItemRevisionStatus":{"state":"PUBLISHED","distributionChannels":[{"crxVersion":"1.0","deployPercentage":10}]}}`))
+		}
+	}))
+	defer server.Close()
+
+	store := New(filepath.Join(t.TempDir(), FileName))
+	var appendErrs []error
+	transport := &Transport{Store: store, OnError: func(err error) { appendErrs = append(appendErrs, err) }}
+	client := chromewebstore.NewClient(&http.Client{Transport: transport})
+	client.SetBaseURL(server.URL)
+	client.SetUploadBaseURL(server.URL)
+	ctx := context.Background()
+	items := client.Publishers.Items
+
+	if _, err := client.Media.Upload(itemA).Context(ctx).Media(strings.NewReader("PK\x03\x04"), chromewebstore.MediaTypeZIP).Do(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := items.Publish(itemA).Context(ctx).Do(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// The pre-flight validation fetches the status, which is recorded even
+	// though the change itself is rejected by the store.
+	if _, err := items.SetPublishedDeployPercentage(itemA).Context(ctx).DeployPercentage(50).Validate(true).Do(); err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, err := items.CancelSubmission(itemA).Context(ctx).Do(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := items.FetchStatus(itemB).Context(ctx).Projection("PUBLISHED").Do(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := items.FetchStatus(itemB).Context(ctx).Fields("itemId").Do(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	status, err := items.FetchStatus(itemB).Context(ctx).Do()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if v, _ := status.PublishedDeployPercentage(); v != 10 {
+		t.Errorf("expected the response to be passed through, got %+v", status)
+	}
+
+	records, err := store.Records()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var kinds []string
+	for _, r := range records {
+		kinds = append(kinds, string(r.Item.ItemID()[:1])+":"+string(r.Kind))
+	}
+	if got := strings.Join(kinds, ","); got != "a:upload,a:publish,a:status,b:status" {
+		t.Fatalf("unexpected records %s", got)
+	}
+	if records[0].Upload.CrxVersion != "1.1" || records[1].Publish.State != chromewebstore.ItemStatePendingReview {
+		t.Errorf("expected the results to be recorded, got %+v and %+v", records[0].Upload, records[1].Publish)
+	}
+	if v, _ := records[3].Status.PublishedDeployPercentage(); v != 10 {
+		t.Errorf("expected the status to be recorded, got %+v", records[3].Status)
+	}
+	if len(appendErrs) > 0 {
+		t.Errorf("unexpected append errors %v", appendErrs)
+	}
+}
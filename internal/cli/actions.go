@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/H0R15H0/chrome-webstore-api-v2/internal/ghactions"
+	"github.com/H0R15H0/chrome-webstore-api-v2/manifest"
+)
+
+// actionsResult is the structured result of a command reported to
+// GitHub Actions as step outputs and a job summary.
+type actionsResult struct {
+	title            string
+	item             chromewebstore.ItemName
+	crxVersion       string
+	uploadState      chromewebstore.UploadState
+	itemState        chromewebstore.ItemState
+	deployPercentage int
+	hasPercentage    bool
+	// notes are appended to the summary as a list.
+	notes []string
+}
+
+// statusResult returns the result describing an item status: the state and
+// version of the submitted revision if there is one, otherwise of the
+// published revision, and the published deploy percentage.
+func statusResult(title string, item chromewebstore.ItemName, status *chromewebstore.ItemStatus) actionsResult {
+	r := actionsResult{title: title, item: item, uploadState: status.LastAsyncUploadState}
+	rev := status.SubmittedItemRevisionStatus
+	if rev == nil {
+		rev = status.PublishedItemRevisionStatus
+	}
+	if rev != nil {
+		r.itemState = rev.State
+		if len(rev.DistributionChannels) > 0 {
+			r.crxVersion = rev.DistributionChannels[0].CrxVersion
+		}
+	}
+	r.deployPercentage, r.hasPercentage = status.PublishedDeployPercentage()
+	return r
+}
+
+// reportActions writes r to $GITHUB_OUTPUT and $GITHUB_STEP_SUMMARY when
+// running inside GitHub Actions. Failures are reported on stderr.
+func reportActions(r actionsResult) {
+	if !ghactions.Enabled() {
+		return
+	}
+
+	var percentage string
+	if r.hasPercentage {
+		percentage = strconv.Itoa(r.deployPercentage)
+	}
+
+	err := ghactions.SetOutputs([]ghactions.Output{
+		{Name: "item-id", Value: r.item.ItemID()},
+		{Name: "crx-version", Value: r.crxVersion},
+		{Name: "upload-state", Value: string(r.uploadState)},
+		{Name: "item-state", Value: string(r.itemState)},
+		{Name: "deploy-percentage", Value: percentage},
+	})
+	if err == nil {
+		if percentage != "" {
+			percentage += "%"
+		}
+		summary := fmt.Sprintf("### %s\n\n", r.title) + ghactions.Table([2]string{"Field", "Value"}, [][2]string{
+			{"Item", "`" + r.item.ItemID() + "`"},
+			{"Version", r.crxVersion},
+			{"Upload state", string(r.uploadState)},
+			{"Item state", string(r.itemState)},
+			{"Deploy percentage", percentage},
+		})
+		if len(r.notes) > 0 {
+			summary += "\n"
+			for _, note := range r.notes {
+				summary += "- " + note + "\n"
+			}
+		}
+		err = ghactions.AppendSummary(summary + "\n")
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to write GitHub Actions output: %v\n", err)
+	}
+}
+
+// annotate emits a GitHub Actions annotation when running inside GitHub
+// Actions. Annotations go to stderr when JSON is written to stdout.
+func annotate(a ghactions.Annotation) {
+	if !ghactions.Enabled() {
+		return
+	}
+	var w io.Writer = os.Stdout
+	if jsonOutput {
+		w = os.Stderr
+	}
+	a.Write(w)
+}
+
+// annotateProblems emits an annotation for every validation problem of the
+// package at path.
+func annotateProblems(path string, problems manifest.Problems) {
+	for _, p := range problems {
+		level := ghactions.LevelWarning
+		if p.Severity == manifest.SeverityError {
+			level = ghactions.LevelError
+		}
+		annotate(ghactions.Annotation{Level: level, Title: p.Check, File: path, Message: p.Message})
+	}
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/H0R15H0/chrome-webstore-api-v2/release"
+	"github.com/spf13/cobra"
+)
+
+var applyDryRun bool
+
+func init() {
+	applyCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the plan in JSON format")
+	applyCmd.Flags().BoolVar(&applyDryRun, "dry-run", false, "Only print the plan")
+	addNotifyFlags(applyCmd)
+	rootCmd.AddCommand(applyCmd)
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply <release.yaml|release.json>",
+	Short: "Bring an item to the state described by a release spec",
+	Long: `Compare a declarative release spec with the item's live status, print the
+upload, publish and deploy percentage actions required to reach it, and
+execute them. Re-running apply after a successful release is a no-op.
+
+Specs are JSON or a YAML subset (block mappings of scalars):
+
+  item: aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa
+  package: dist/extension.zip    # ZIP, CRX or directory
+  version: 1.2.0                 # optional; defaults to the package version
+  publishType: default           # default, staged or none (no package)
+  skipReview: false
+  deployPercentage: 100
+  wait:
+    for: published               # none, review or published
+    timeout: 72h
+    interval: 5m
+
+The publisher defaults to --publisher-id or CHROME_WEBSTORE_PUBLISHER_ID.
+Use --webhook or --notify-config to post the status changes of the release.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		spec, err := release.Load(args[0])
+		if err != nil {
+			return err
+		}
+
+		itemName, err := spec.ItemName(getPublisherID())
+		if err != nil {
+			return err
+		}
+
+		var packageVersion string
+		if spec.Package != "" {
+			packageVersion, err = release.PackageVersion(spec.Package)
+			if err != nil {
+				return err
+			}
+		}
+
+		client, err := createClient(ctx)
+		if err != nil {
+			return err
+		}
+
+		notifier, err := loadNotifier()
+		if err != nil {
+			return err
+		}
+
+		status, err := client.Publishers.Items.FetchStatus(itemName).Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("failed to fetch status: %w", err)
+		}
+		plan, err := release.NewPlan(spec, itemName, status, packageVersion)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			output, err := json.MarshalIndent(plan, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(output))
+		} else {
+			printPlan(plan)
+		}
+
+		summary := statusResult("Release "+plan.Version, itemName, status)
+		summary.crxVersion = plan.Version
+		if applyDryRun || (plan.Empty() && (spec.Wait.For == "" || spec.Wait.For == release.WaitNone)) {
+			summary.notes = append([]string{"No changes applied"}, plan.Notes...)
+			if applyDryRun {
+				summary.notes = planNotes(plan)
+			}
+			reportActions(summary)
+			return nil
+		}
+
+		done := 0
+		last := status
+		applier := &release.Applier{
+			Client: client,
+			OnAction: func(action release.Action, result any) {
+				switch r := result.(type) {
+				case *chromewebstore.UploadResponse:
+					summary.uploadState = r.UploadState
+				case *chromewebstore.PublishResponse:
+					summary.itemState = r.State
+				case *chromewebstore.SetPublishedDeployPercentageResponse:
+					summary.deployPercentage, summary.hasPercentage = spec.DeployPercentage, true
+				}
+				summary.notes = append(summary.notes, "Done: "+action.Description)
+				done++
+				if !jsonOutput {
+					fmt.Printf("Done: %s\n", action.Description)
+				}
+			},
+			OnPoll: func(status *chromewebstore.ItemStatus) {
+				for _, event := range chromewebstore.DiffStatus(itemName, last, status, time.Now()) {
+					sendNotification(ctx, notifier, event)
+				}
+				last = status
+				polled := statusResult(summary.title, itemName, status)
+				summary.itemState, summary.uploadState = polled.itemState, polled.uploadState
+				summary.deployPercentage, summary.hasPercentage = polled.deployPercentage, polled.hasPercentage
+			},
+		}
+		err = applier.Apply(ctx, plan)
+		notifyStatusChanges(ctx, client, notifier, itemName, last)
+		summary.notes = append(summary.notes, plan.Notes...)
+		reportActions(summary)
+		if err != nil {
+			state := "No actions were completed."
+			if done > 0 {
+				state = fmt.Sprintf("%d of %d actions were completed; the last one may have reached the store.", done, len(plan.Actions))
+			}
+			return interrupted(fmt.Errorf("failed to apply: %w", err), state+" Re-run \"cws apply\" to continue from the current state.")
+		}
+
+		if !jsonOutput {
+			fmt.Printf("Applied release %s of %s\n", plan.Version, itemName)
+		}
+		return nil
+	},
+}
+
+// planNotes returns the planned actions and notes of plan as summary lines.
+func planNotes(plan *release.Plan) []string {
+	var notes []string
+	for _, a := range plan.Actions {
+		notes = append(notes, "Planned: "+a.Description)
+	}
+	return append(notes, plan.Notes...)
+}
+
+// printPlan prints a release plan in a human-readable form.
+func printPlan(plan *release.Plan) {
+	fmt.Printf("Item:    %s\n", plan.Item)
+	fmt.Printf("Version: %s\n", plan.Version)
+	if plan.Empty() {
+		fmt.Println("No changes required.")
+	} else {
+		fmt.Println("Plan:")
+		for i, a := range plan.Actions {
+			fmt.Printf("  %d. %s\n", i+1, a.Description)
+		}
+	}
+	for _, note := range plan.Notes {
+		fmt.Printf("Note: %s\n", note)
+	}
+}
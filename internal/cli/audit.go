@@ -0,0 +1,217 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/audit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	auditFile       string
+	noAudit         bool
+	auditHead       string
+	auditActor      string
+	auditOperations []string
+	auditSince      string
+	auditUntil      string
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&auditFile, "audit-file", "", "Audit log file (default: $CWS_AUDIT_FILE or cws/audit.jsonl in the user config directory)")
+	rootCmd.PersistentFlags().BoolVar(&noAudit, "no-audit", false, "Do not record uploads, publishes and other changes in the audit log")
+	auditVerifyCmd.Flags().StringVar(&auditHead, "head", "", "Also check that the log still contains the entry with this hash, as printed by an earlier verify")
+	auditShowCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON Lines format")
+	auditShowCmd.Flags().StringVar(&auditActor, "actor", "", "Only show entries of this actor name or email")
+	auditShowCmd.Flags().StringSliceVar(&auditOperations, "operation", nil, "Only show these operations: upload, publish, cancel-submission, set-deploy-percentage")
+	auditShowCmd.Flags().StringVar(&auditSince, "since", "", "Only show entries at or after this time (RFC 3339 or YYYY-MM-DD) or this long ago (e.g. 72h)")
+	auditShowCmd.Flags().StringVar(&auditUntil, "until", "", "Only show entries before this time (RFC 3339 or YYYY-MM-DD)")
+	auditCmd.AddCommand(auditVerifyCmd)
+	auditCmd.AddCommand(auditShowCmd)
+	rootCmd.AddCommand(auditCmd)
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Verify and query the tamper-evident audit log",
+	Long: `Every upload, publish, cancelled submission and deploy percentage change is
+recorded in a hash-chained audit log with the actor, item, request
+parameters, package SHA-256, response state and time, unless --no-audit is
+given. The actor is taken from $CWS_AUDIT_ACTOR, the CI environment (GitHub
+Actions, GitLab CI) or git config, in that order.`,
+}
+
+var auditVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check the audit log for tampering",
+	Long: `Check the hash chain of the audit log. Modified, reordered or removed entries
+are reported with their line number.
+
+Entries removed from the end of the log cannot be detected from the log
+alone. Keep the head hash printed by verify elsewhere and pass it with
+--head later to detect them.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log, err := auditLog()
+		if err != nil {
+			return err
+		}
+
+		var entries []audit.Entry
+		if auditHead != "" {
+			entries, err = log.VerifyHead(auditHead)
+		} else {
+			entries, err = log.Verify()
+		}
+		if err != nil {
+			return fmt.Errorf("audit log %s failed verification: %w", log.Path(), err)
+		}
+
+		if len(entries) == 0 {
+			fmt.Printf("No entries in %s\n", log.Path())
+			return nil
+		}
+		fmt.Printf("Verified %d entries in %s\n", len(entries), log.Path())
+		fmt.Printf("Head: %s\n", audit.Head(entries))
+		return nil
+	},
+}
+
+var auditShowCmd = &cobra.Command{
+	Use:   "show [item-id...]",
+	Short: "Show audit log entries",
+	Long: `Show the entries of the audit log, optionally filtered by item, actor,
+operation and time. Entries of all items are shown if no item IDs are given.
+The log is not verified; use "cws audit verify" for that.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log, err := auditLog()
+		if err != nil {
+			return err
+		}
+
+		filter := audit.Filter{Actor: auditActor}
+		if len(args) > 0 {
+			filter.Items, err = getItemNames(args)
+			if err != nil {
+				return err
+			}
+		}
+		for _, op := range auditOperations {
+			switch o := audit.Operation(op); o {
+			case audit.OperationUpload, audit.OperationPublish, audit.OperationCancelSubmission, audit.OperationSetDeployPercentage:
+				filter.Operations = append(filter.Operations, o)
+			default:
+				return fmt.Errorf("unknown operation %q", op)
+			}
+		}
+		if filter.Since, err = parseTimeFlag("since", auditSince); err != nil {
+			return err
+		}
+		if filter.Until, err = parseTimeFlag("until", auditUntil); err != nil {
+			return err
+		}
+
+		entries, err := log.Entries(filter)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			enc := json.NewEncoder(os.Stdout)
+			for _, e := range entries {
+				if err := enc.Encode(e); err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
+			}
+			return nil
+		}
+
+		if len(entries) == 0 {
+			fmt.Printf("No matching entries in %s\n", log.Path())
+			return nil
+		}
+		for _, e := range entries {
+			result := orNone(e.State)
+			if e.Error != "" {
+				result = "error: " + e.Error
+			}
+			fmt.Printf("#%d  %s  %s  %-21s  %s\n", e.Seq, e.Time.Local().Format(time.DateTime), e.Item.ItemID(), e.Operation, e.Actor)
+			if o := e.PolicyOverride; o != nil {
+				result += fmt.Sprintf(" (policy override of %s: %s)", strings.Join(o.Rules, ", "), o.Reason)
+			}
+			fmt.Printf("    %s -> %s\n", auditDetails(e), result)
+		}
+		return nil
+	},
+}
+
+// auditDetails summarizes the request parameters of an entry.
+func auditDetails(e audit.Entry) string {
+	switch {
+	case e.PackageSHA256 != "":
+		return fmt.Sprintf("sha256:%s (%d bytes)", e.PackageSHA256[:12], e.PackageSize)
+	case len(e.Request) > 0:
+		return string(e.Request)
+	case e.Query != "":
+		return e.Query
+	}
+	return "-"
+}
+
+// parseTimeFlag parses an RFC 3339 time, a date or a duration before now.
+// An empty value yields the zero time.
+func parseTimeFlag(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --%s %q: use RFC 3339, YYYY-MM-DD or a duration", name, value)
+}
+
+// auditLog returns the configured audit log.
+func auditLog() (*audit.Log, error) {
+	path := auditFile
+	if path == "" {
+		var err error
+		path, err = audit.DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return audit.New(path), nil
+}
+
+// auditTransport wraps the transport of httpClient so that audited
+// operations are recorded, unless disabled. Failures to record are
+// reported on stderr and do not fail the command.
+func auditTransport(httpClient *http.Client) error {
+	if noAudit {
+		return nil
+	}
+	log, err := auditLog()
+	if err != nil {
+		return err
+	}
+	httpClient.Transport = &audit.Transport{
+		Base:  httpClient.Transport,
+		Log:   log,
+		Actor: audit.DetectActor(),
+		OnError: func(err error) {
+			fmt.Fprintf(os.Stderr, "warning: failed to record audit entry: %v\n", err)
+		},
+	}
+	return nil
+}
@@ -16,7 +16,9 @@ var cancelSubmissionCmd = &cobra.Command{
 	Short: "Cancel a pending submission",
 	Long:  `Cancel a pending submission for a Chrome Web Store item.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		client, err := createClient()
+		ctx := cmd.Context()
+
+		client, err := createClient(ctx)
 		if err != nil {
 			return err
 		}
@@ -26,9 +28,10 @@ var cancelSubmissionCmd = &cobra.Command{
 			return err
 		}
 
-		result, err := client.Publishers.Items.CancelSubmission(itemName).Do()
+		result, err := client.Publishers.Items.CancelSubmission(itemName).Context(ctx).Do()
 		if err != nil {
-			return fmt.Errorf("failed to cancel submission: %w", err)
+			return interrupted(fmt.Errorf("failed to cancel submission: %w", err),
+				"The submission may have been cancelled. Run \"cws fetch-status\" to check before retrying.")
 		}
 
 		if jsonOutput {
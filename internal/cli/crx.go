@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/H0R15H0/chrome-webstore-api-v2/crx"
+	"github.com/H0R15H0/chrome-webstore-api-v2/pack"
+	"github.com/spf13/cobra"
+)
+
+var (
+	crxKeyPath  string
+	crxOutput   string
+	crxStripKey bool
+	crxCheckKey bool
+)
+
+func init() {
+	crxPackCmd.Flags().StringVar(&crxKeyPath, "key", "", "PEM-encoded RSA private key used to sign the package")
+	crxPackCmd.Flags().StringVarP(&crxOutput, "output", "o", "", "Output CRX file (default: <input>.crx)")
+	crxPackCmd.Flags().BoolVar(&crxStripKey, "strip-key", false, "Remove the key field from manifest.json when packing a directory")
+	crxPackCmd.MarkFlagRequired("key")
+	crxVerifyCmd.Flags().BoolVar(&crxCheckKey, "check-store", false, "Also check the public key against the item's public key in the store")
+	crxCmd.AddCommand(crxPackCmd)
+	crxCmd.AddCommand(crxVerifyCmd)
+	rootCmd.AddCommand(crxCmd)
+}
+
+var crxCmd = &cobra.Command{
+	Use:   "crx",
+	Short: "Build and verify signed CRX packages for verified uploads",
+}
+
+var crxPackCmd = &cobra.Command{
+	Use:   "pack <file.zip|dir>",
+	Short: "Sign a ZIP file or directory as a CRX3 package",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		input := args[0]
+
+		data, err := signPackage(input, crxKeyPath, crxStripKey)
+		if err != nil {
+			return err
+		}
+
+		output := crxOutput
+		if output == "" {
+			abs, err := filepath.Abs(input)
+			if err != nil {
+				return err
+			}
+			output = strings.TrimSuffix(filepath.Base(abs), ".zip") + ".crx"
+		}
+
+		if err := os.WriteFile(output, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+
+		fmt.Printf("Wrote %s (%d bytes)\n", output, len(data))
+		return nil
+	},
+}
+
+var crxVerifyCmd = &cobra.Command{
+	Use:   "verify <file.crx>",
+	Short: "Verify the signature of a CRX3 package",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open file: %w", err)
+		}
+
+		f, err := crx.Parse(data)
+		if err != nil {
+			return err
+		}
+		if err := f.Verify(); err != nil {
+			return err
+		}
+		fmt.Printf("Signature: valid (CRX ID %s)\n", hex.EncodeToString(f.CrxID))
+
+		if crxCheckKey {
+			client, err := createClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			itemName, err := getItemName()
+			if err != nil {
+				return err
+			}
+
+			if err := verifyCRXAgainstStore(ctx, client, itemName, data); err != nil {
+				return err
+			}
+			fmt.Println("Public key: matches the store")
+		}
+
+		return nil
+	},
+}
+
+// signPackage reads a ZIP file or packs a directory and signs it with the
+// private key at keyPath.
+func signPackage(input, keyPath string, stripKey bool) ([]byte, error) {
+	pemData, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+	key, err := crx.ParsePrivateKey(pemData)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	var zipData []byte
+	if info.IsDir() {
+		zipData, err = pack.Bytes(input, pack.Options{StripKey: stripKey, OnSkip: warnSkipped})
+	} else {
+		zipData, err = os.ReadFile(input)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if crx.IsCRX(zipData) {
+		return nil, fmt.Errorf("%s is already a CRX package", input)
+	}
+
+	return crx.Sign(zipData, key)
+}
+
+// verifyCRXAgainstStore checks the CRX signature and that its public key
+// matches the item's public key in the store.
+func verifyCRXAgainstStore(ctx context.Context, client *chromewebstore.Client, itemName chromewebstore.ItemName, data []byte) error {
+	f, err := crx.Parse(data)
+	if err != nil {
+		return err
+	}
+	if err := f.Verify(); err != nil {
+		return err
+	}
+
+	status, err := client.Publishers.Items.FetchStatus(itemName).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to fetch status: %w", err)
+	}
+	if status.PublicKey == "" {
+		return fmt.Errorf("the store returned no public key for %s", itemName)
+	}
+
+	if err := f.VerifyPublicKey(status.PublicKey); err != nil {
+		return fmt.Errorf("refusing to upload: %w", err)
+	}
+	return nil
+}
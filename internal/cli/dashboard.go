@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/H0R15H0/chrome-webstore-api-v2/internal/dashboard"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dashboardInterval    time.Duration
+	dashboardMaxInterval time.Duration
+)
+
+func init() {
+	dashboardCmd.Flags().DurationVar(&dashboardInterval, "interval", dashboard.DefaultInterval, "Refresh interval")
+	dashboardCmd.Flags().DurationVar(&dashboardMaxInterval, "max-interval", dashboard.DefaultMaxInterval, "Maximum refresh interval while fetches fail")
+	rootCmd.AddCommand(dashboardCmd)
+}
+
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard [item-id...]",
+	Short: "Show an interactive status dashboard",
+	Long: `Show the published and submitted version, state, deploy percentage, policy
+flags and last upload state of one or more items in the terminal. The
+statuses are refreshed periodically; the interval doubles up to
+--max-interval while fetches fail.
+
+Keys: ↑/↓ or j/k select an item, p publishes it, c cancels its pending
+submission, d raises its deploy percentage, r refreshes and q quits.
+Every action asks for confirmation first.
+
+If no item IDs are given, the configured item is shown.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		if !dashboard.IsTerminal(os.Stdin) || !dashboard.IsTerminal(os.Stdout) {
+			return fmt.Errorf("dashboard requires an interactive terminal; use \"cws watch\" instead")
+		}
+
+		client, err := createClient(ctx)
+		if err != nil {
+			return err
+		}
+
+		names, err := getItemNames(args)
+		if err != nil {
+			return err
+		}
+
+		restore, err := dashboard.OpenTerminal(os.Stdin, os.Stdout)
+		if err != nil {
+			return err
+		}
+		defer restore()
+
+		d := &dashboard.Dashboard{
+			Items:       names,
+			Client:      &dashboardClient{client: client},
+			Interval:    dashboardInterval,
+			MaxInterval: dashboardMaxInterval,
+			In:          os.Stdin,
+			Out:         os.Stdout,
+		}
+		return d.Run(ctx)
+	},
+}
+
+// dashboardClient performs the dashboard's API calls.
+type dashboardClient struct {
+	client *chromewebstore.Client
+}
+
+func (c *dashboardClient) FetchStatus(ctx context.Context, item chromewebstore.ItemName) (*chromewebstore.ItemStatus, error) {
+	status, err := c.client.Publishers.Items.FetchStatus(item).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func (c *dashboardClient) Publish(ctx context.Context, item chromewebstore.ItemName) error {
+	_, err := c.client.Publishers.Items.Publish(item).Context(ctx).Do()
+	return err
+}
+
+func (c *dashboardClient) CancelSubmission(ctx context.Context, item chromewebstore.ItemName) error {
+	_, err := c.client.Publishers.Items.CancelSubmission(item).Context(ctx).Do()
+	return err
+}
+
+func (c *dashboardClient) SetDeployPercentage(ctx context.Context, item chromewebstore.ItemName, percentage int) error {
+	_, err := c.client.Publishers.Items.SetPublishedDeployPercentage(item).
+		Context(ctx).
+		DeployPercentage(percentage).
+		Validate(true).
+		Do()
+	return err
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/manifest"
+	"github.com/spf13/cobra"
+)
+
+var diffFailOnNewPermissions bool
+
+func init() {
+	diffCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	diffCmd.Flags().BoolVar(&diffFailOnNewPermissions, "fail-on-new-permissions", false, "Exit with an error if the new package requests new permissions, host permissions or content script matches")
+	rootCmd.AddCommand(diffCmd)
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old> <new>",
+	Short: "Compare two extension packages",
+	Long: `Compare the manifests and file lists of two ZIP files, CRX files or
+extension directories, highlighting newly requested permissions.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oldPkg, err := manifest.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open package: %w", err)
+		}
+		newPkg, err := manifest.Open(args[1])
+		if err != nil {
+			return fmt.Errorf("failed to open package: %w", err)
+		}
+
+		d, err := manifest.Compare(oldPkg, newPkg)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			output, err := json.MarshalIndent(d, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(output))
+		} else {
+			printDiff(d)
+		}
+
+		if diffFailOnNewPermissions && d.HasNewPermissions() {
+			return fmt.Errorf("new package requests new permissions")
+		}
+		return nil
+	},
+}
+
+// printDiff prints a package diff in a human-readable form.
+func printDiff(d *manifest.Diff) {
+	if len(d.AddedPermissions)+len(d.RemovedPermissions)+len(d.AddedHostPermissions)+len(d.RemovedHostPermissions) > 0 {
+		fmt.Println("Permissions:")
+		for _, p := range d.AddedPermissions {
+			fmt.Printf("  + %s  (NEW)\n", p)
+		}
+		for _, p := range d.RemovedPermissions {
+			fmt.Printf("  - %s\n", p)
+		}
+		for _, p := range d.AddedHostPermissions {
+			fmt.Printf("  + host %s  (NEW)\n", p)
+		}
+		for _, p := range d.RemovedHostPermissions {
+			fmt.Printf("  - host %s\n", p)
+		}
+	}
+
+	if len(d.Fields) > 0 {
+		fmt.Println("Manifest:")
+		for _, f := range d.Fields {
+			fmt.Printf("  %s: %s -> %s\n", f.Field, orNone(f.Old), orNone(f.New))
+		}
+	}
+
+	if len(d.AddedFiles)+len(d.RemovedFiles)+len(d.ChangedFiles) > 0 {
+		fmt.Println("Files:")
+		for _, f := range d.AddedFiles {
+			fmt.Printf("  + %s (%d bytes)\n", f.Name, f.Size)
+		}
+		for _, f := range d.RemovedFiles {
+			fmt.Printf("  - %s (%d bytes)\n", f.Name, f.Size)
+		}
+		for _, f := range d.ChangedFiles {
+			fmt.Printf("  ~ %s (%d -> %d bytes)\n", f.Name, f.OldSize, f.NewSize)
+		}
+	}
+
+	fmt.Printf("Size: %d -> %d bytes (%+d)\n", d.OldSize, d.NewSize, d.SizeDelta())
+}
+
+// orNone returns s, or "(none)" if s is empty.
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
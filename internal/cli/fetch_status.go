@@ -20,7 +20,9 @@ var fetchStatusCmd = &cobra.Command{
 	Short: "Fetch the status of an item",
 	Long:  `Fetch the current status of a Chrome Web Store item.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		client, err := createClient()
+		ctx := cmd.Context()
+
+		client, err := createClient(ctx)
 		if err != nil {
 			return err
 		}
@@ -30,7 +32,7 @@ var fetchStatusCmd = &cobra.Command{
 			return err
 		}
 
-		call := client.Publishers.Items.FetchStatus(itemName)
+		call := client.Publishers.Items.FetchStatus(itemName).Context(ctx)
 		if projection != "" {
 			call = call.Projection(projection)
 		}
@@ -38,6 +40,7 @@ var fetchStatusCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to fetch status: %w", err)
 		}
+		reportActions(statusResult("Item status", itemName, status))
 
 		if jsonOutput {
 			output, err := json.MarshalIndent(status, "", "  ")
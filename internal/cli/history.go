@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/H0R15H0/chrome-webstore-api-v2/history"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyFile     string
+	noHistory       bool
+	historyReviews  bool
+	historyAllItems bool
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&historyFile, "history-file", "", "History file (default: $CWS_HISTORY_FILE or cws/history.jsonl in the user config directory)")
+	rootCmd.PersistentFlags().BoolVar(&noHistory, "no-history", false, "Do not record results in the local history")
+	historyCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	historyCmd.Flags().BoolVar(&historyReviews, "reviews", false, "Only show review turnaround times")
+	historyCmd.Flags().BoolVar(&historyAllItems, "all", false, "Show all items in the history")
+	rootCmd.AddCommand(historyCmd)
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history [item-id...]",
+	Short: "Show recorded status timelines and review turnaround times",
+	Long: `Show the timeline of statuses, uploads and publishes recorded in the local
+history, and the review turnaround time of each submission: the time from
+PENDING_REVIEW to PUBLISHED, STAGED or REJECTED as observed by cws.
+
+Every status fetch, upload, publish and deploy percentage change made by cws
+is recorded unless --no-history is given, including the status fetches of
+watch, rollout gates and pre-flight validation.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := historyStore()
+		if err != nil {
+			return err
+		}
+
+		var names []chromewebstore.ItemName
+		if !historyAllItems {
+			names, err = getItemNames(args)
+			if err != nil {
+				return err
+			}
+		}
+
+		records, err := store.Records(names...)
+		if err != nil {
+			return err
+		}
+		reviews := history.Reviews(records)
+
+		if jsonOutput {
+			result := struct {
+				Timeline []history.Entry  `json:"timeline,omitempty"`
+				Reviews  []history.Review `json:"reviews"`
+			}{Reviews: reviews}
+			if !historyReviews {
+				result.Timeline = history.Timeline(records)
+			}
+			output, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(output))
+			return nil
+		}
+
+		if len(records) == 0 {
+			fmt.Printf("No history recorded in %s\n", store.Path())
+			return nil
+		}
+
+		entries := history.Timeline(records)
+		for _, item := range history.Items(records) {
+			fmt.Println(item)
+			if !historyReviews {
+				for _, e := range entries {
+					if e.Item == item {
+						fmt.Printf("  %s  %s\n", e.Time.Local().Format(time.DateTime), e.Message)
+					}
+				}
+			}
+
+			var itemReviews []history.Review
+			for _, r := range reviews {
+				if r.Item == item {
+					itemReviews = append(itemReviews, r)
+				}
+			}
+			if len(itemReviews) == 0 {
+				continue
+			}
+			fmt.Println("  Reviews:")
+			for _, r := range itemReviews {
+				version := orNone(r.Version)
+				submitted := r.Submitted.Local().Format(time.DateTime)
+				if r.Pending() {
+					fmt.Printf("    %-12s submitted %s, pending for %s\n", version, submitted, formatDuration(time.Since(r.Submitted)))
+				} else {
+					fmt.Printf("    %-12s submitted %s, %s after %s\n", version, submitted, r.Outcome, formatDuration(r.Turnaround()))
+				}
+			}
+			if avg, n := history.AverageTurnaround(itemReviews); n > 0 {
+				fmt.Printf("  Average turnaround: %s (%d reviews)\n", formatDuration(avg), n)
+			}
+		}
+
+		return nil
+	},
+}
+
+// historyStore returns the configured history store.
+func historyStore() (*history.Store, error) {
+	path := historyFile
+	if path == "" {
+		var err error
+		path, err = history.DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return history.New(path), nil
+}
+
+// historyTransport wraps the transport of httpClient so that every status
+// fetch, upload, publish and deploy percentage change is recorded in the
+// local history, unless disabled. Failures to record are reported on
+// stderr and do not fail the command.
+func historyTransport(httpClient *http.Client) error {
+	if noHistory {
+		return nil
+	}
+	store, err := historyStore()
+	if err != nil {
+		return err
+	}
+	httpClient.Transport = &history.Transport{
+		Base:  httpClient.Transport,
+		Store: store,
+		OnError: func(err error) {
+			fmt.Fprintf(os.Stderr, "warning: failed to record history: %v\n", err)
+		},
+	}
+	return nil
+}
+
+// formatDuration formats d rounded to minutes.
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Minute).String()
+}
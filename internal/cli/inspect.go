@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/manifest"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	inspectCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	rootCmd.AddCommand(inspectCmd)
+}
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <package>",
+	Short: "Inspect an extension package",
+	Long:  `Print a summary of the manifest of a ZIP file, CRX file or extension directory.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pkg, err := manifest.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open package: %w", err)
+		}
+		if pkg.ManifestErr != nil {
+			return fmt.Errorf("failed to load manifest: %w", pkg.ManifestErr)
+		}
+		m := pkg.Manifest
+
+		if jsonOutput {
+			output, err := json.MarshalIndent(struct {
+				Path     string             `json:"path"`
+				Format   manifest.Format    `json:"format"`
+				Size     int64              `json:"size"`
+				Manifest *manifest.Manifest `json:"manifest"`
+			}{pkg.Path, pkg.Format, pkg.Size, m}, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(output))
+			return nil
+		}
+
+		fmt.Printf("Package:          %s (%s, %d bytes)\n", pkg.Path, pkg.Format, pkg.Size)
+		fmt.Printf("Name:             %s\n", m.Name)
+		fmt.Printf("Version:          %s\n", m.Version)
+		if m.VersionName != "" {
+			fmt.Printf("Version name:     %s\n", m.VersionName)
+		}
+		fmt.Printf("Manifest version: %d\n", m.ManifestVersion)
+		if m.MinimumChromeVersion != "" {
+			fmt.Printf("Minimum Chrome:   %s\n", m.MinimumChromeVersion)
+		}
+		if len(m.Permissions) > 0 {
+			fmt.Printf("Permissions:      %s\n", strings.Join(m.Permissions, ", "))
+		}
+		if len(m.HostPermissions) > 0 {
+			fmt.Printf("Host permissions: %s\n", strings.Join(m.HostPermissions, ", "))
+		}
+		if len(m.ContentScripts) > 0 {
+			fmt.Println("Content scripts:")
+			for _, cs := range m.ContentScripts {
+				fmt.Printf("  %s -> %s\n", strings.Join(cs.Matches, ", "), strings.Join(slices.Concat(cs.JS, cs.CSS), ", "))
+			}
+		}
+		if m.Background != nil {
+			switch {
+			case m.Background.ServiceWorker != "":
+				fmt.Printf("Background:       service worker %s\n", m.Background.ServiceWorker)
+			case m.Background.Page != "":
+				fmt.Printf("Background:       page %s\n", m.Background.Page)
+			case len(m.Background.Scripts) > 0:
+				fmt.Printf("Background:       scripts %s\n", strings.Join(m.Background.Scripts, ", "))
+			}
+		}
+		if len(m.Icons) > 0 {
+			fmt.Println("Icons:")
+			sizes := make([]string, 0, len(m.Icons))
+			for size := range m.Icons {
+				sizes = append(sizes, size)
+			}
+			sort.Slice(sizes, func(i, j int) bool {
+				a, _ := strconv.Atoi(sizes[i])
+				b, _ := strconv.Atoi(sizes[j])
+				return a < b
+			})
+			for _, size := range sizes {
+				fmt.Printf("  %s: %s\n", size, m.Icons[size])
+			}
+		}
+		if m.Key != "" {
+			fmt.Println("Key:              present")
+		}
+
+		return nil
+	},
+}
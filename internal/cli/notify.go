@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/H0R15H0/chrome-webstore-api-v2/notify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	notifyConfig        string
+	notifyWebhooks      []string
+	notifyWebhookFormat string
+	notifyWebhookSecret string
+	notifyEvents        []string
+	notifyStates        []string
+)
+
+// addNotifyFlags registers the webhook notification flags on cmd.
+func addNotifyFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&notifyConfig, "notify-config", "", "JSON file with webhook definitions")
+	cmd.Flags().StringArrayVar(&notifyWebhooks, "webhook", nil, "Webhook URL to notify of status changes (repeatable)")
+	cmd.Flags().StringVar(&notifyWebhookFormat, "webhook-format", string(notify.FormatJSON), "Payload format for --webhook: json, slack or teams")
+	cmd.Flags().StringVar(&notifyWebhookSecret, "webhook-secret", "", "HMAC secret for --webhook (or set CWS_WEBHOOK_SECRET)")
+	cmd.Flags().StringSliceVar(&notifyEvents, "notify-events", nil, "Change types sent to --webhook, e.g. STATE,TAKEN_DOWN (default: all)")
+	cmd.Flags().StringSliceVar(&notifyStates, "notify-states", nil, "Only send state changes into these states, e.g. PUBLISHED,REJECTED")
+}
+
+// loadNotifier returns the notifier configured by the notification flags,
+// or nil if no webhooks are configured.
+func loadNotifier() (*notify.Notifier, error) {
+	n := &notify.Notifier{}
+	if notifyConfig != "" {
+		loaded, err := notify.LoadConfig(notifyConfig)
+		if err != nil {
+			return nil, err
+		}
+		n = loaded
+	}
+
+	secret := notifyWebhookSecret
+	if secret == "" {
+		secret = os.Getenv("CWS_WEBHOOK_SECRET")
+	}
+	for _, u := range notifyWebhooks {
+		w := notify.Webhook{
+			URL:    u,
+			Format: notify.Format(notifyWebhookFormat),
+			Secret: secret,
+		}
+		for _, e := range notifyEvents {
+			w.Events = append(w.Events, chromewebstore.ChangeType(e))
+		}
+		for _, s := range notifyStates {
+			w.States = append(w.States, chromewebstore.ItemState(s))
+		}
+		if err := w.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid --webhook %s: %w", u, err)
+		}
+		n.Webhooks = append(n.Webhooks, w)
+	}
+
+	if len(n.Webhooks) == 0 {
+		return nil, nil
+	}
+	return n, nil
+}
+
+// sendNotification delivers event, reporting failures on stderr.
+func sendNotification(ctx context.Context, n *notify.Notifier, event chromewebstore.ChangeEvent) {
+	if n == nil {
+		return
+	}
+	if err := n.Notify(ctx, event); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to send notification: %v\n", err)
+	}
+}
+
+// notifyStatusChanges sends notifications for the changes from before to
+// the current status of item. Failures are reported on stderr.
+func notifyStatusChanges(ctx context.Context, client *chromewebstore.Client, n *notify.Notifier, item chromewebstore.ItemName, before *chromewebstore.ItemStatus) {
+	if n == nil || before == nil || ctx.Err() != nil {
+		return
+	}
+	status, err := client.Publishers.Items.FetchStatus(item).Context(ctx).Do()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to fetch status for notifications: %v\n", err)
+		return
+	}
+	for _, event := range chromewebstore.DiffStatus(item, before, status, time.Now()) {
+		sendNotification(ctx, n, event)
+	}
+}
+
+// watchAndNotify polls the items in the background and sends notifications
+// for their status changes until ctx is done.
+func watchAndNotify(ctx context.Context, client *chromewebstore.Client, n *notify.Notifier, names ...chromewebstore.ItemName) {
+	if n == nil {
+		return
+	}
+	go func() {
+		for event, err := range client.Publishers.Items.Watch(names...).Context(ctx).Seq() {
+			if err == nil {
+				sendNotification(ctx, n, event)
+			}
+		}
+	}()
+}
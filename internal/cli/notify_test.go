@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadNotifierInvalidFilters(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []string
+		states []string
+		errMsg string
+	}{
+		{name: "unknown event", events: []string{"STATE", "PUBLISHED"}, errMsg: `unknown event "PUBLISHED"`},
+		{name: "unknown state", states: []string{"published"}, errMsg: `unknown state "published"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifyWebhooks, notifyEvents, notifyStates = []string{"https://example.com/hook"}, tt.events, tt.states
+			t.Cleanup(func() { notifyWebhooks, notifyEvents, notifyStates = nil, nil, nil })
+
+			if _, err := loadNotifier(); err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
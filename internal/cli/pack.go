@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/pack"
+	"github.com/spf13/cobra"
+)
+
+var (
+	packOutput   string
+	packStripKey bool
+	packIgnore   []string
+	packList     bool
+)
+
+func init() {
+	packCmd.Flags().StringVarP(&packOutput, "output", "o", "", "Output ZIP file (default: <dir>.zip next to the directory)")
+	packCmd.Flags().BoolVar(&packStripKey, "strip-key", false, "Remove the key field from manifest.json")
+	packCmd.Flags().StringArrayVar(&packIgnore, "ignore", nil, "Additional ignore pattern (repeatable)")
+	packCmd.Flags().BoolVar(&packList, "list", false, "List the files that would be packed without writing the ZIP")
+	rootCmd.AddCommand(packCmd)
+}
+
+var packCmd = &cobra.Command{
+	Use:   "pack <dir>",
+	Short: "Build a store ZIP from a source directory",
+	Long: `Build a deterministic ZIP package from an extension source directory.
+
+Entries are sorted with fixed timestamps and permissions. VCS directories,
+.DS_Store, Thumbs.db and source maps are excluded by default; additional
+patterns are read from a .cwsignore file in the directory. Symbolic links and
+other files that are not regular files are left out with a warning.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := args[0]
+		opts := pack.Options{StripKey: packStripKey, Ignore: packIgnore, OnSkip: warnSkipped}
+
+		output, opts, err := packOutputPath(dir, packOutput, opts)
+		if err != nil {
+			return err
+		}
+
+		if packList {
+			files, err := pack.Files(dir, opts)
+			if err != nil {
+				return err
+			}
+			for _, f := range files {
+				fmt.Println(f)
+			}
+			return nil
+		}
+
+		data, err := pack.Bytes(dir, opts)
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(output, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+
+		fmt.Printf("Wrote %s (%d bytes)\n", output, len(data))
+		return nil
+	},
+}
+
+// packOutputPath returns the ZIP file to write for dir, defaulting to
+// <dir>.zip next to dir, and opts ignoring it if it lies inside dir so that
+// packing again does not include the previous package.
+func packOutputPath(dir, output string, opts pack.Options) (string, pack.Options, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", opts, err
+	}
+	if output == "" {
+		output = filepath.Join(filepath.Dir(abs), filepath.Base(abs)+".zip")
+	}
+
+	absOutput, err := filepath.Abs(output)
+	if err != nil {
+		return "", opts, err
+	}
+	if rel, err := filepath.Rel(abs, absOutput); err == nil && filepath.IsLocal(rel) {
+		opts.Ignore = append(append([]string{}, opts.Ignore...), "/"+escapePattern(filepath.ToSlash(rel)))
+	}
+	return output, opts, nil
+}
+
+// escapePattern quotes the special characters of an ignore pattern.
+func escapePattern(name string) string {
+	var b strings.Builder
+	for _, c := range name {
+		if strings.ContainsRune(`*?[\`, c) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// warnSkipped reports a file left out of a package on stderr.
+func warnSkipped(name string) {
+	fmt.Fprintf(os.Stderr, "warning: skipping %s: not a regular file (symbolic links are not followed)\n", name)
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/pack"
+)
+
+func TestPackOutputPath(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "ext")
+	if err := os.Mkdir(src, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "manifest.json"), []byte(`{"manifest_version":3,"name":"x","version":"1.0"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{name: "default", want: filepath.Join(filepath.Dir(src), "ext.zip")},
+		{name: "inside the directory", output: filepath.Join(src, "build", "ext[1].zip"), want: filepath.Join(src, "build", "ext[1].zip")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var packages [][]byte
+			for range 2 {
+				output, opts, err := packOutputPath(src, tt.output, pack.Options{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if output != tt.want {
+					t.Fatalf("expected output %s, got %s", tt.want, output)
+				}
+				data, err := pack.Bytes(src, opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(output, data, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				packages = append(packages, data)
+			}
+			if !bytes.Equal(packages[0], packages[1]) {
+				t.Errorf("expected packing twice to produce the same package")
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/H0R15H0/chrome-webstore-api-v2/policy"
+	"github.com/spf13/cobra"
+)
+
+var (
+	policyFile          string
+	policyOverride      string
+	policyOverrideRules []string
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&policyFile, "policy", "", "Policy file checked before publishes and deploy percentage changes (or set CWS_POLICY_FILE)")
+	rootCmd.PersistentFlags().StringVar(&policyOverride, "override-policy", "", "Override policy violations with this reason, which is recorded in the audit log")
+	rootCmd.PersistentFlags().StringSliceVar(&policyOverrideRules, "override-rules", nil, "Only override these policy rules (default: all rules that allow overrides)")
+}
+
+// getPolicyFile returns the configured policy file, or "" if there is none.
+func getPolicyFile() string {
+	if policyFile != "" {
+		return policyFile
+	}
+	return os.Getenv("CWS_POLICY_FILE")
+}
+
+// applyPolicy sets the configured policy on client, if any.
+func applyPolicy(client *chromewebstore.Client) error {
+	return applyPolicyAt(client, nil)
+}
+
+// applyPolicyAt sets the configured policy on client, if any, evaluating
+// its time-based rules at the time returned by now. A nil now uses the
+// current time.
+func applyPolicyAt(client *chromewebstore.Client, now func() time.Time) error {
+	path := getPolicyFile()
+	if path == "" {
+		return nil
+	}
+	engine, err := policy.LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	engine.Now = now
+	client.SetPolicy(engine)
+	return nil
+}
+
+// withPolicyOverride attaches the --override-policy override to the
+// command's context. Overrides must be audited, so they cannot be combined
+// with --no-audit.
+func withPolicyOverride(cmd *cobra.Command) error {
+	if policyOverride == "" {
+		if len(policyOverrideRules) > 0 {
+			return fmt.Errorf("--override-rules requires --override-policy with a reason")
+		}
+		return nil
+	}
+	if noAudit {
+		return fmt.Errorf("--override-policy cannot be used with --no-audit: overrides are recorded in the audit log")
+	}
+	override := chromewebstore.PolicyOverride{Reason: policyOverride, Rules: policyOverrideRules}
+	cmd.SetContext(chromewebstore.WithPolicyOverride(cmd.Context(), override))
+	return nil
+}
+
+// policyHint returns how to proceed past a policy violation in err, or ""
+// if err is not one.
+func policyHint(err error) string {
+	var verr *policy.ViolationError
+	if !errors.As(err, &verr) {
+		return ""
+	}
+	if !verr.Overridable() {
+		return "Some rules cannot be overridden."
+	}
+	return "Use --override-policy <reason> to override the rules (recorded in the audit log)."
+}
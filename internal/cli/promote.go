@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/spf13/cobra"
+)
+
+var (
+	promoteDeployPercentage int
+	promoteDryRun           bool
+)
+
+func init() {
+	promoteCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	promoteCmd.Flags().IntVar(&promoteDeployPercentage, "deploy-percentage", 0, "Initial deploy percentage of the promoted version (0-100, 0 keeps the current setting)")
+	promoteCmd.Flags().BoolVar(&promoteDryRun, "dry-run", false, "Only show the version that would go live")
+	promoteCmd.Flags().BoolVar(&force, "force", false, "Skip deploy percentage validation")
+	addNotifyFlags(promoteCmd)
+	rootCmd.AddCommand(promoteCmd)
+}
+
+var promoteCmd = &cobra.Command{
+	Use:   "promote",
+	Short: "Publish the staged revision of an item",
+	Long: `Publish the revision that was submitted with --type staged and approved.
+
+The submitted revision must be STAGED. The staged version is shown next to
+the currently published one before it is published, optionally with an
+initial deploy percentage. Use --dry-run to only show them. Use --webhook or
+--notify-config to post the resulting status changes.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		if promoteDeployPercentage < 0 || promoteDeployPercentage > 100 {
+			return fmt.Errorf("invalid deploy percentage %d: must be between 0 and 100", promoteDeployPercentage)
+		}
+
+		client, err := createClient(ctx)
+		if err != nil {
+			return err
+		}
+
+		itemName, err := getItemName()
+		if err != nil {
+			return err
+		}
+
+		notifier, err := loadNotifier()
+		if err != nil {
+			return err
+		}
+
+		status, err := client.Publishers.Items.FetchStatus(itemName).Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("failed to fetch status: %w", err)
+		}
+		staged, err := stagedVersion(status)
+		if err != nil {
+			return err
+		}
+		published, percentage := "", 0
+		if rev := status.PublishedItemRevisionStatus; rev != nil && len(rev.DistributionChannels) > 0 {
+			published, percentage = rev.DistributionChannels[0].CrxVersion, rev.DistributionChannels[0].DeployPercentage
+		}
+
+		if !jsonOutput {
+			fmt.Printf("Staged:    %s\n", orNone(staged))
+			if published != "" {
+				fmt.Printf("Published: %s (Deploy: %d%%)\n", published, percentage)
+			} else {
+				fmt.Println("Published: (none)")
+			}
+		}
+
+		var result *chromewebstore.PublishResponse
+		if !promoteDryRun {
+			call := client.Publishers.Items.Publish(itemName).Context(ctx).PublishType(chromewebstore.PublishTypeDefault)
+			hasPercentage := cmd.Flags().Changed("deploy-percentage")
+			if hasPercentage {
+				call.DeployPercentage(promoteDeployPercentage)
+			}
+			result, err = call.Validate(!force).Do()
+			if err != nil {
+				return interrupted(fmt.Errorf("failed to promote version %s: %w", staged, err),
+					"The store may have received the request. Run \"cws fetch-status\" to check whether the staged version was published before retrying.")
+			}
+			notifyStatusChanges(ctx, client, notifier, itemName, status)
+			reportActions(actionsResult{
+				title:            "Promoted",
+				item:             itemName,
+				crxVersion:       staged,
+				itemState:        result.State,
+				deployPercentage: promoteDeployPercentage,
+				hasPercentage:    hasPercentage,
+			})
+		}
+
+		if jsonOutput {
+			output, err := json.MarshalIndent(struct {
+				Item                      chromewebstore.ItemName         `json:"item"`
+				StagedVersion             string                          `json:"stagedVersion"`
+				PublishedVersion          string                          `json:"publishedVersion,omitempty"`
+				PublishedDeployPercentage int                             `json:"publishedDeployPercentage,omitempty"`
+				Result                    *chromewebstore.PublishResponse `json:"result,omitempty"`
+			}{itemName, staged, published, percentage, result}, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(output))
+		} else if result != nil {
+			fmt.Printf("Promoted version %s (State: %s)\n", orNone(staged), result.State)
+		}
+		return nil
+	},
+}
+
+// stagedVersion returns the version of the submitted revision of status,
+// or an error explaining why nothing is staged.
+func stagedVersion(status *chromewebstore.ItemStatus) (string, error) {
+	sub := status.SubmittedItemRevisionStatus
+	if sub == nil {
+		return "", fmt.Errorf("nothing is staged: the item has no submitted revision; publish with --type staged first")
+	}
+	version := ""
+	if len(sub.DistributionChannels) > 0 {
+		version = sub.DistributionChannels[0].CrxVersion
+	}
+	switch sub.State {
+	case chromewebstore.ItemStateStaged:
+		return version, nil
+	case chromewebstore.ItemStatePendingReview:
+		return "", fmt.Errorf("nothing is staged: version %s is still %s; run \"cws watch\" to wait for the review", orNone(version), sub.State)
+	case chromewebstore.ItemStateRejected:
+		return "", fmt.Errorf("nothing is staged: version %s was %s; upload a new version", orNone(version), sub.State)
+	}
+	return "", fmt.Errorf("nothing is staged: the submitted revision (version %s) is %s", orNone(version), sub.State)
+}
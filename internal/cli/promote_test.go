@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+func TestStagedVersion(t *testing.T) {
+	submitted := func(state chromewebstore.ItemState, version string) *chromewebstore.ItemStatus {
+		rev := &chromewebstore.ItemRevisionStatus{State: state}
+		if version != "" {
+			rev.DistributionChannels = []chromewebstore.DistributionChannel{{CrxVersion: version}}
+		}
+		return &chromewebstore.ItemStatus{SubmittedItemRevisionStatus: rev}
+	}
+
+	tests := []struct {
+		name    string
+		status  *chromewebstore.ItemStatus
+		version string
+		errMsg  string
+	}{
+		{
+			name:    "staged",
+			status:  submitted(chromewebstore.ItemStateStaged, "1.2"),
+			version: "1.2",
+		},
+		{
+			name:   "no submitted revision",
+			status: &chromewebstore.ItemStatus{},
+			errMsg: "the item has no submitted revision; publish with --type staged first",
+		},
+		{
+			name:   "pending review",
+			status: submitted(chromewebstore.ItemStatePendingReview, "1.2"),
+			errMsg: `version 1.2 is still PENDING_REVIEW; run "cws watch" to wait for the review`,
+		},
+		{
+			name:   "rejected",
+			status: submitted(chromewebstore.ItemStateRejected, "1.2"),
+			errMsg: "version 1.2 was REJECTED; upload a new version",
+		},
+		{
+			name:   "other state without version",
+			status: submitted(chromewebstore.ItemStateCancelled, ""),
+			errMsg: "the submitted revision (version (none)) is CANCELLED",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := stagedVersion(tt.status)
+			if tt.errMsg == "" {
+				if err != nil || version != tt.version {
+					t.Errorf("expected version %s, got %q and %v", tt.version, version, err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), "nothing is staged: ") || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+			if version != "" {
+				t.Errorf("expected no version, got %q", version)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/H0R15H0/chrome-webstore-api-v2/schedule"
 	"github.com/spf13/cobra"
 )
 
@@ -16,15 +17,23 @@ var (
 func init() {
 	publishCmd.Flags().StringVar(&publishType, "type", "default", "Publish type: 'default' or 'staged'")
 	publishCmd.Flags().IntVar(&deployPercentage, "deploy-percentage", 0, "Deploy percentage for staged rollout (0-100)")
+	publishCmd.Flags().BoolVar(&force, "force", false, "Skip deploy percentage validation")
+	publishCmd.Flags().StringVar(&scheduleAt, "at", "", "Schedule the publish for this time (RFC 3339, \"YYYY-MM-DD HH:MM\" or a duration from now) instead of publishing now")
 	rootCmd.AddCommand(publishCmd)
 }
 
 var publishCmd = &cobra.Command{
 	Use:   "publish",
 	Short: "Publish an item",
-	Long:  `Publish a Chrome Web Store item.`,
+	Long: `Publish a Chrome Web Store item.
+
+With --at, the publish is scheduled instead and executed by
+"cws scheduler run". Submit with --type staged first to go live at a set
+time after review.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		client, err := createClient()
+		ctx := cmd.Context()
+
+		client, err := createClient(ctx)
 		if err != nil {
 			return err
 		}
@@ -34,22 +43,47 @@ var publishCmd = &cobra.Command{
 			return err
 		}
 
-		call := client.Publishers.Items.Publish(itemName)
+		call := client.Publishers.Items.Publish(itemName).Context(ctx)
 
+		var pt chromewebstore.PublishType
 		if publishType == "default" {
-			call.PublishType(chromewebstore.PublishTypeDefault)
+			pt = chromewebstore.PublishTypeDefault
 		} else if publishType == "staged" {
-			call.PublishType(chromewebstore.PublishTypeStaged)
+			pt = chromewebstore.PublishTypeStaged
+		}
+		if pt != "" {
+			call.PublishType(pt)
+		}
+
+		if scheduleAt != "" {
+			return scheduleJob(ctx, client, schedule.Job{
+				Item:             itemName,
+				Operation:        schedule.OperationPublish,
+				PublishType:      pt,
+				DeployPercentage: deployPercentage,
+				Force:            force,
+			})
 		}
 
-		if deployPercentage > 0 {
+		// An explicit percentage is always sent so that Validate rejects
+		// values out of range instead of dropping them.
+		hasPercentage := cmd.Flags().Changed("deploy-percentage")
+		if hasPercentage {
 			call.DeployPercentage(deployPercentage)
 		}
 
-		result, err := call.Do()
+		result, err := call.Validate(!force).Do()
 		if err != nil {
-			return fmt.Errorf("failed to publish: %w", err)
+			return interrupted(fmt.Errorf("failed to publish: %w", err),
+				"The store may have received the request. Run \"cws fetch-status\" to check for a pending submission before retrying.")
 		}
+		reportActions(actionsResult{
+			title:            "Published",
+			item:             itemName,
+			itemState:        result.State,
+			deployPercentage: deployPercentage,
+			hasPercentage:    hasPercentage,
+		})
 
 		if jsonOutput {
 			output, err := json.MarshalIndent(result, "", "  ")
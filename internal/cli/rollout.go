@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/rollout"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rolloutGateURLs          []string
+	rolloutGateCommands      []string
+	rolloutGateItemStatus    bool
+	rolloutConsecutivePasses int
+	rolloutCheckInterval     time.Duration
+)
+
+func init() {
+	rolloutCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	rolloutCmd.Flags().StringArrayVar(&rolloutGateURLs, "gate-url", nil, "Health endpoint that must return 2xx (repeatable)")
+	rolloutCmd.Flags().StringArrayVar(&rolloutGateCommands, "gate-command", nil, "Shell command that must exit 0 (repeatable)")
+	rolloutCmd.Flags().BoolVar(&rolloutGateItemStatus, "gate-item-status", false, "Require the item not to be warned or taken down")
+	rolloutCmd.Flags().IntVar(&rolloutConsecutivePasses, "consecutive-passes", 1, "Number of consecutive passing checks required before each step")
+	rolloutCmd.Flags().DurationVar(&rolloutCheckInterval, "check-interval", time.Minute, "Delay between checks")
+	rolloutCmd.Flags().BoolVar(&force, "force", false, "Skip validation against the current deploy percentage")
+	addNotifyFlags(rolloutCmd)
+	rootCmd.AddCommand(rolloutCmd)
+}
+
+var rolloutCmd = &cobra.Command{
+	Use:   "rollout <percentage>...",
+	Short: "Raise the deploy percentage step by step behind health gates",
+	Long: `Raise the deploy percentage of a published item through each given
+percentage in order. Before every step all configured gates are evaluated,
+and the rollout halts with a report as soon as any gate fails.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		steps := make([]int, 0, len(args))
+		for _, arg := range args {
+			percentage, err := strconv.Atoi(arg)
+			if err != nil {
+				return fmt.Errorf("invalid percentage: %w", err)
+			}
+			steps = append(steps, percentage)
+		}
+		if err := rollout.ValidateSteps(steps); err != nil {
+			return err
+		}
+
+		client, err := createClient(ctx)
+		if err != nil {
+			return err
+		}
+
+		itemName, err := getItemName()
+		if err != nil {
+			return err
+		}
+
+		notifier, err := loadNotifier()
+		if err != nil {
+			return err
+		}
+
+		var gates []rollout.Gate
+		for _, u := range rolloutGateURLs {
+			gates = append(gates, &rollout.HTTPGate{URL: u})
+		}
+		for _, c := range rolloutGateCommands {
+			gates = append(gates, &rollout.CommandGate{Command: c})
+		}
+		if rolloutGateItemStatus {
+			gates = append(gates, &rollout.ItemStatusGate{Items: client.Publishers.Items, Item: itemName})
+		}
+
+		r := &rollout.Rollout{
+			Items:             client.Publishers.Items,
+			Item:              itemName,
+			Gates:             gates,
+			ConsecutivePasses: rolloutConsecutivePasses,
+			CheckInterval:     rolloutCheckInterval,
+			Validate:          !force,
+		}
+		if !jsonOutput {
+			r.OnCheck = func(percentage int, results []rollout.GateResult) {
+				for _, res := range results {
+					if res.Passed {
+						fmt.Printf("[%d%%] PASS %s\n", percentage, res.Gate)
+					} else {
+						fmt.Printf("[%d%%] FAIL %s: %s\n", percentage, res.Gate, res.Message)
+					}
+				}
+			}
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		watchAndNotify(ctx, client, notifier, itemName)
+
+		report, runErr := r.Run(ctx, steps)
+
+		if jsonOutput {
+			output, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(output))
+		} else {
+			for _, step := range report.Steps {
+				if step.Applied {
+					fmt.Printf("Deploy percentage set to %d%%\n", step.Percentage)
+				}
+			}
+		}
+
+		var gateErr *rollout.GateFailedError
+		if errors.As(runErr, &gateErr) {
+			return fmt.Errorf("rollout halted before %d%%: gate check failed", gateErr.Percentage)
+		}
+		if runErr != nil {
+			state := "No deploy percentage change was applied."
+			for _, step := range report.Steps {
+				if step.Applied {
+					state = fmt.Sprintf("The deploy percentage was last set to %d%%.", step.Percentage)
+				}
+			}
+			return interrupted(fmt.Errorf("failed to roll out: %w", runErr), state)
+		}
+
+		return nil
+	},
+}
@@ -2,17 +2,32 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/H0R15H0/chrome-webstore-api-v2/internal/ghactions"
 	"github.com/spf13/cobra"
 )
 
 var (
-	publisherID string
-	itemID      string
-	jsonOutput  bool
+	publisherID     string
+	itemID          string
+	jsonOutput      bool
+	force           bool
+	rateLimit       float64
+	rateBurst       int
+	uploadRateLimit float64
+	timeout         time.Duration
+	requestTimeout  time.Duration
+	uploadTimeout   time.Duration
+
+	// cancelTimeout releases the --timeout context.
+	cancelTimeout context.CancelFunc = func() {}
 )
 
 var rootCmd = &cobra.Command{
@@ -20,15 +35,47 @@ var rootCmd = &cobra.Command{
 	Short:        "Chrome Web Store API CLI",
 	Long:         `A command-line interface for the Chrome Web Store API v2.`,
 	SilenceUsage: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if timeout > 0 {
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			cmd.SetContext(ctx)
+			cancelTimeout = cancel
+		}
+		return withPolicyOverride(cmd)
+	},
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&publisherID, "publisher-id", "", "Publisher ID (or set CHROME_WEBSTORE_PUBLISHER_ID)")
 	rootCmd.PersistentFlags().StringVar(&itemID, "item-id", "", "Item ID (or set CHROME_WEBSTORE_ITEM_ID)")
+	rootCmd.PersistentFlags().Float64Var(&rateLimit, "rate-limit", 0, "Maximum API requests per second (0 for no limit)")
+	rootCmd.PersistentFlags().IntVar(&rateBurst, "rate-burst", 5, "Maximum burst of requests allowed by --rate-limit and --upload-rate-limit")
+	rootCmd.PersistentFlags().Float64Var(&uploadRateLimit, "upload-rate-limit", 0, "Maximum upload requests per second (0 for no limit)")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Abort the command after this duration (0 for no limit)")
+	rootCmd.PersistentFlags().DurationVar(&requestTimeout, "request-timeout", 2*time.Minute, "Abort a single API request after this duration (0 for no limit)")
+	rootCmd.PersistentFlags().DurationVar(&uploadTimeout, "upload-timeout", 0, "Abort a single upload request after this duration (0 for no limit)")
 }
 
+// Execute runs the CLI. The command's context is cancelled on SIGINT or
+// SIGTERM; a second signal terminates the process immediately.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	cancelTimeout()
+	if err != nil {
+		var apiErr *chromewebstore.APIError
+		if errors.As(err, &apiErr) && apiErr.RequestID() != "" {
+			fmt.Fprintf(os.Stderr, "Request ID: %s (include it when contacting support)\n", apiErr.RequestID())
+		}
+		if hint := policyHint(err); hint != "" {
+			fmt.Fprintln(os.Stderr, hint)
+		}
+		annotate(ghactions.Annotation{Level: ghactions.LevelError, Title: cmd.CommandPath(), Message: err.Error()})
 		os.Exit(1)
 	}
 }
@@ -47,7 +94,10 @@ func getItemID() string {
 	return os.Getenv("CHROME_WEBSTORE_ITEM_ID")
 }
 
-func createClient() (*chromewebstore.Client, error) {
+// createClient creates an API client from the environment. ctx is used to
+// refresh access tokens. Changes made through the client are checked
+// against the configured policy and recorded in the audit log.
+func createClient(ctx context.Context) (*chromewebstore.Client, error) {
 	clientID := os.Getenv("CHROME_WEBSTORE_CLIENT_ID")
 	clientSecret := os.Getenv("CHROME_WEBSTORE_CLIENT_SECRET")
 	refreshToken := os.Getenv("CHROME_WEBSTORE_REFRESH_TOKEN")
@@ -62,7 +112,22 @@ func createClient() (*chromewebstore.Client, error) {
 		RefreshToken: refreshToken,
 	}
 
-	return chromewebstore.NewClientFromCredentials(context.Background(), config), nil
+	httpClient := chromewebstore.NewAuthenticatedClient(ctx, config)
+	if err := auditTransport(httpClient); err != nil {
+		return nil, err
+	}
+	if err := historyTransport(httpClient); err != nil {
+		return nil, err
+	}
+	client := chromewebstore.NewClient(httpClient)
+	client.SetRateLimit(rateLimit, rateBurst)
+	client.SetUploadRateLimit(uploadRateLimit, rateBurst)
+	client.SetRequestTimeout(requestTimeout)
+	client.SetUploadTimeout(uploadTimeout)
+	if err := applyPolicy(client); err != nil {
+		return nil, err
+	}
+	return client, nil
 }
 
 func getItemName() (chromewebstore.ItemName, error) {
@@ -75,6 +140,22 @@ func getItemName() (chromewebstore.ItemName, error) {
 	if itmID == "" {
 		return "", fmt.Errorf("item-id is required (use --item-id flag or CHROME_WEBSTORE_ITEM_ID environment variable)")
 	}
+	if err := chromewebstore.ValidateItemID(itmID); err != nil {
+		return "", err
+	}
 
 	return chromewebstore.NewItemName(pubID, itmID), nil
 }
+
+// interrupted annotates err with what state the item may have been left in
+// when it was caused by cancellation or a timeout. Other errors are
+// returned unchanged.
+func interrupted(err error, state string) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return fmt.Errorf("interrupted: %w\n%s", err, state)
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("timed out: %w\n%s", err, state)
+	}
+	return err
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestInterrupted(t *testing.T) {
+	const hint = "Run \"cws fetch-status\" to check before retrying."
+	errOther := errors.New("boom")
+
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   string
+	}{
+		{
+			name:   "cancelled",
+			err:    fmt.Errorf("failed to publish: %w", context.Canceled),
+			target: context.Canceled,
+			want:   "interrupted: failed to publish: context canceled\n" + hint,
+		},
+		{
+			name:   "timed out",
+			err:    fmt.Errorf("failed to publish: %w", context.DeadlineExceeded),
+			target: context.DeadlineExceeded,
+			want:   "timed out: failed to publish: context deadline exceeded\n" + hint,
+		},
+		{
+			name:   "other error",
+			err:    errOther,
+			target: errOther,
+			want:   "boom",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := interrupted(tt.err, hint)
+			if err.Error() != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, err.Error())
+			}
+			if !errors.Is(err, tt.target) {
+				t.Errorf("expected the error to wrap %v", tt.target)
+			}
+		})
+	}
+
+	if err := interrupted(errOther, hint); err != errOther {
+		t.Errorf("expected other errors to be returned unchanged, got %v", err)
+	}
+}
@@ -0,0 +1,355 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/audit"
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/H0R15H0/chrome-webstore-api-v2/schedule"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scheduleFile      string
+	scheduleAt        string
+	schedulerOnce     bool
+	schedulerInterval time.Duration
+	schedulerMaxDelay time.Duration
+	schedulerStale    time.Duration
+	schedulerAll      bool
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&scheduleFile, "schedule-file", "", "Schedule file (default: $CWS_SCHEDULE_FILE or cws/schedule.json in the user config directory)")
+	schedulerRunCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output finished jobs as JSON lines")
+	schedulerRunCmd.Flags().BoolVar(&schedulerOnce, "once", false, "Run the jobs that are due and exit, e.g. from cron")
+	schedulerRunCmd.Flags().DurationVar(&schedulerInterval, "interval", 30*time.Second, "How often to check for new jobs")
+	schedulerRunCmd.Flags().DurationVar(&schedulerMaxDelay, "max-delay", time.Hour, "Skip jobs overdue by more than this duration (0 for no limit)")
+	schedulerRunCmd.Flags().DurationVar(&schedulerStale, "stale-after", schedule.DefaultStaleAfter, "Fail jobs left running by a stopped scheduler for longer than this duration")
+	addNotifyFlags(schedulerRunCmd)
+	schedulerListCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	schedulerListCmd.Flags().DurationVar(&schedulerStale, "stale-after", schedule.DefaultStaleAfter, "Mark jobs running for longer than this duration as stale")
+	schedulerListCmd.Flags().BoolVar(&schedulerAll, "all", false, "Also show finished and cancelled jobs")
+	schedulerCmd.AddCommand(schedulerRunCmd)
+	schedulerCmd.AddCommand(schedulerListCmd)
+	schedulerCmd.AddCommand(schedulerCancelCmd)
+	rootCmd.AddCommand(schedulerCmd)
+}
+
+var schedulerCmd = &cobra.Command{
+	Use:   "scheduler",
+	Short: "Run, list and cancel scheduled publishes and deploy percentage changes",
+	Long: `Jobs are scheduled with "cws publish --at" and
+"cws set-published-deploy-percentage --at" and executed by "cws scheduler run".
+
+Each job records the submitted and published versions of the item when it is
+scheduled. The job is skipped if they changed by the time it is due, if the
+item was taken down, or if a scheduled publish finds the submission not
+STAGED.`,
+}
+
+var schedulerRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Execute scheduled jobs when they are due",
+	Long: `Execute scheduled jobs when they are due, until interrupted. With --once, the
+jobs that are due are executed and the command exits, so that it can be run
+from cron.
+
+Jobs overdue by more than --max-delay, for example because no scheduler was
+running at their time, are skipped. Jobs whose item status cannot be fetched
+are retried on the next run. Jobs left running for longer than --stale-after,
+because the scheduler running them stopped, are marked failed rather than run
+again, as their request may already have been sent.
+
+Use --webhook or --notify-config to post the status changes made by the jobs
+to Slack, Teams or any JSON endpoint.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		client, err := createClient(ctx)
+		if err != nil {
+			return err
+		}
+		store, err := scheduleStore()
+		if err != nil {
+			return err
+		}
+		notifier, err := loadNotifier()
+		if err != nil {
+			return err
+		}
+
+		failed := 0
+		before := make(map[int]*chromewebstore.ItemStatus)
+		runner := &schedule.Runner{
+			Items:      client.Publishers.Items,
+			Store:      store,
+			MaxDelay:   schedulerMaxDelay,
+			StaleAfter: schedulerStale,
+			JobContext: func(ctx context.Context, job schedule.Job) context.Context {
+				if job.CreatedBy == "" {
+					return ctx
+				}
+				return audit.WithActor(ctx, audit.Actor{Name: job.CreatedBy, Source: "scheduler"})
+			},
+			OnStatus: func(job schedule.Job, status *chromewebstore.ItemStatus) {
+				before[job.ID] = status
+			},
+			OnJob: func(job schedule.Job) {
+				if job.State == schedule.StateFailed {
+					failed++
+				}
+				reportJob(job)
+				if job.State == schedule.StateDone {
+					notifyStatusChanges(ctx, client, notifier, job.Item, before[job.ID])
+				}
+				delete(before, job.ID)
+			},
+		}
+
+		if !schedulerOnce {
+			fmt.Fprintf(os.Stderr, "Running jobs in %s (press Ctrl+C to stop)\n", store.Path())
+			return runner.Run(ctx, schedulerInterval)
+		}
+		if _, err := runner.RunDue(ctx); err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d scheduled jobs failed", failed)
+		}
+		return nil
+	},
+}
+
+var schedulerListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List scheduled jobs",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := scheduleStore()
+		if err != nil {
+			return err
+		}
+		jobs, err := store.Jobs()
+		if err != nil {
+			return err
+		}
+		if !schedulerAll {
+			var active []schedule.Job
+			for _, j := range jobs {
+				if !j.Finished() {
+					active = append(active, j)
+				}
+			}
+			jobs = active
+		}
+
+		if jsonOutput {
+			output, err := json.MarshalIndent(jobs, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(output))
+			return nil
+		}
+
+		if len(jobs) == 0 {
+			fmt.Printf("No scheduled jobs in %s\n", store.Path())
+			return nil
+		}
+		now := time.Now()
+		for _, j := range jobs {
+			fmt.Printf("#%d  %s  %s  %-21s  %-9s  %s\n", j.ID, j.At.Local().Format(time.DateTime), j.Item.ItemID(), j.Operation, j.State, jobDetails(j))
+			switch {
+			case j.Stale(now, schedulerStale):
+				fmt.Printf("    stale: claimed by %s at %s; the scheduler seems to have stopped, the next run marks the job failed\n", j.Runner, j.StartedAt.Local().Format(time.DateTime))
+			case j.State == schedule.StateRunning:
+				fmt.Printf("    running on %s since %s\n", j.Runner, j.StartedAt.Local().Format(time.DateTime))
+			}
+			if j.PolicyOverride != nil {
+				fmt.Printf("    policy override: %s\n", j.PolicyOverride.Reason)
+			}
+			if j.Error != "" {
+				fmt.Printf("    %s\n", j.Error)
+			}
+		}
+		return nil
+	},
+}
+
+var schedulerCancelCmd = &cobra.Command{
+	Use:   "cancel <job-id>...",
+	Short: "Cancel pending scheduled jobs",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := scheduleStore()
+		if err != nil {
+			return err
+		}
+		for _, arg := range args {
+			id, err := strconv.Atoi(arg)
+			if err != nil {
+				return fmt.Errorf("invalid job ID %q", arg)
+			}
+			job, err := store.Cancel(id)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Cancelled job #%d (%s of %s at %s)\n", job.ID, job.Operation, job.Item.ItemID(), job.At.Local().Format(time.DateTime))
+		}
+		return nil
+	},
+}
+
+// scheduleStore returns the configured schedule store.
+func scheduleStore() (*schedule.Store, error) {
+	path := scheduleFile
+	if path == "" {
+		var err error
+		path, err = schedule.DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return schedule.New(path), nil
+}
+
+// parseAtFlag parses the --at flag: an RFC 3339 time, a local
+// "YYYY-MM-DD HH:MM" time or a duration from now. The time must be in the
+// future.
+func parseAtFlag(value string) (time.Time, error) {
+	var at time.Time
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		at = t
+	} else if t, err := time.ParseInLocation("2006-01-02 15:04", value, time.Local); err == nil {
+		at = t
+	} else if d, err := time.ParseDuration(value); err == nil {
+		at = time.Now().Add(d)
+	} else {
+		return time.Time{}, fmt.Errorf("invalid --at %q: use RFC 3339, \"YYYY-MM-DD HH:MM\" or a duration", value)
+	}
+	if !at.After(time.Now()) {
+		return time.Time{}, fmt.Errorf("--at %s is in the past", at.Local().Format(time.DateTime))
+	}
+	return at, nil
+}
+
+// scheduleJob records the current status of the job's item in job and adds
+// it to the schedule instead of executing it. The job is vetted first with
+// the same deploy percentage validation as an immediate change and the
+// policy as of its time; a --override-policy override is saved with it.
+func scheduleJob(ctx context.Context, client *chromewebstore.Client, job schedule.Job) error {
+	var err error
+	if job.At, err = parseAtFlag(scheduleAt); err != nil {
+		return err
+	}
+	if err := applyPolicyAt(client, func() time.Time { return job.At }); err != nil {
+		return err
+	}
+	if err := checkJob(ctx, client, job); err != nil {
+		return fmt.Errorf("cannot schedule %s: %w", job.Operation, err)
+	}
+	if override, ok := chromewebstore.PolicyOverrideFromContext(ctx); ok {
+		job.PolicyOverride = &override
+	}
+
+	status, err := client.Publishers.Items.FetchStatus(job.Item).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to fetch status: %w", err)
+	}
+	job.Expect = schedule.SnapshotOf(status)
+	job.CreatedBy = audit.DetectActor().String()
+
+	store, err := scheduleStore()
+	if err != nil {
+		return err
+	}
+	job, err = store.Add(job)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		output, err := json.MarshalIndent(job, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+	fmt.Printf("Scheduled job #%d: %s of %s at %s\n", job.ID, job.Operation, job.Item.ItemID(), job.At.Local().Format(time.DateTime))
+	if sub := status.SubmittedItemRevisionStatus; job.Operation == schedule.OperationPublish && sub != nil && sub.State != chromewebstore.ItemStateStaged {
+		fmt.Printf("Note: the submission is %s; the job is skipped unless it is %s by then.\n", sub.State, chromewebstore.ItemStateStaged)
+	}
+	fmt.Println(`Run "cws scheduler run" to execute it when due.`)
+	return nil
+}
+
+// checkJob runs the policy check and pre-flight validation of job without
+// executing it. The deploy percentage of a publish is always range-checked.
+func checkJob(ctx context.Context, client *chromewebstore.Client, job schedule.Job) error {
+	items := client.Publishers.Items
+	switch job.Operation {
+	case schedule.OperationPublish:
+		call := items.Publish(job.Item).Context(ctx)
+		if job.PublishType != "" {
+			call.PublishType(job.PublishType)
+		}
+		if job.DeployPercentage != 0 {
+			call.DeployPercentage(job.DeployPercentage)
+		}
+		return call.Validate(true).Check()
+	case schedule.OperationSetDeployPercentage:
+		return items.SetPublishedDeployPercentage(job.Item).
+			Context(ctx).
+			DeployPercentage(job.DeployPercentage).
+			Validate(!job.Force).
+			Check()
+	}
+	return fmt.Errorf("unknown operation %q", job.Operation)
+}
+
+// jobDetails summarizes the parameters and outcome of a job.
+func jobDetails(j schedule.Job) string {
+	var s string
+	switch j.Operation {
+	case schedule.OperationPublish:
+		s = string(j.PublishType)
+		if s == "" {
+			s = string(chromewebstore.PublishTypeDefault)
+		}
+		if j.DeployPercentage != 0 {
+			s += fmt.Sprintf(", %d%%", j.DeployPercentage)
+		}
+	case schedule.OperationSetDeployPercentage:
+		s = fmt.Sprintf("%d%%", j.DeployPercentage)
+	}
+	if j.ItemState != "" {
+		s += " -> " + string(j.ItemState)
+	}
+	return s
+}
+
+// reportJob prints a job run by the scheduler.
+func reportJob(job schedule.Job) {
+	if jsonOutput {
+		output, err := json.Marshal(job)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to marshal JSON: %v\n", err)
+			return
+		}
+		fmt.Println(string(output))
+		return
+	}
+	line := fmt.Sprintf("#%d  %s  %s  %s  %s", job.ID, job.Item.ItemID(), job.Operation, jobDetails(job), job.State)
+	if job.Error != "" {
+		line += ": " + job.Error
+	}
+	fmt.Println(line)
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/gateway"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveConfig string
+	serveAddr   string
+)
+
+func init() {
+	serveCmd.Flags().StringVar(&serveConfig, "config", "", "Gateway configuration file (JSON)")
+	serveCmd.Flags().StringVar(&serveAddr, "addr", ":8080", "Address to listen on")
+	serveCmd.MarkFlagRequired("config")
+	rootCmd.AddCommand(serveCmd)
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve an authenticated HTTP API gateway",
+	Long: `Serve a small HTTP/JSON API for fetching statuses, uploading, publishing,
+cancelling submissions and setting deploy percentages, so that other services
+and developers do not need the publisher's OAuth credentials.
+
+Callers authenticate with an API key in the X-API-Key header or an OpenID
+Connect ID token in the Authorization header, and are granted the reader or
+publisher role per item in the configuration file. The OpenAPI description is
+served at /openapi.json.
+
+The server shuts down gracefully on SIGINT or SIGTERM.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		config, err := gateway.LoadConfig(serveConfig)
+		if err != nil {
+			return err
+		}
+		if config.PublisherID == "" {
+			config.PublisherID = getPublisherID()
+		}
+
+		client, err := createClient(ctx)
+		if err != nil {
+			return err
+		}
+
+		server, err := gateway.NewServer(client, config)
+		if err != nil {
+			return err
+		}
+		server.Logger = log.New(os.Stderr, "", log.LstdFlags)
+
+		srv := &http.Server{
+			Addr:              serveAddr,
+			Handler:           server,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		errc := make(chan error, 1)
+		go func() {
+			errc <- srv.ListenAndServe()
+		}()
+		fmt.Fprintf(os.Stderr, "Serving the gateway for publisher %s on %s\n", config.PublisherID, serveAddr)
+
+		select {
+		case err := <-errc:
+			return fmt.Errorf("failed to serve: %w", err)
+		case <-ctx.Done():
+		}
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to shut down: %w", err)
+		}
+		return nil
+	},
+}
@@ -5,29 +5,36 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/H0R15H0/chrome-webstore-api-v2/schedule"
 	"github.com/spf13/cobra"
 )
 
 func init() {
+	setPublishedDeployPercentageCmd.Flags().BoolVar(&force, "force", false, "Skip validation against the current deploy percentage")
+	setPublishedDeployPercentageCmd.Flags().StringVar(&scheduleAt, "at", "", "Schedule the change for this time (RFC 3339, \"YYYY-MM-DD HH:MM\" or a duration from now) instead of applying it now")
 	rootCmd.AddCommand(setPublishedDeployPercentageCmd)
 }
 
 var setPublishedDeployPercentageCmd = &cobra.Command{
 	Use:   "set-published-deploy-percentage <percentage>",
 	Short: "Set the deploy percentage for a published item",
-	Long:  `Set the deploy percentage (0-100) for a published Chrome Web Store item.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Set the deploy percentage (0-100) for a published Chrome Web Store item.
+
+The requested percentage is validated against the current published deploy
+percentage before the request is sent. Use --force to skip the validation.
+
+With --at, the change is scheduled instead and executed by
+"cws scheduler run".`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
 		percentage, err := strconv.Atoi(args[0])
 		if err != nil {
 			return fmt.Errorf("invalid percentage: %w", err)
 		}
 
-		if percentage < 0 || percentage > 100 {
-			return fmt.Errorf("percentage must be between 0 and 100")
-		}
-
-		client, err := createClient()
+		client, err := createClient(ctx)
 		if err != nil {
 			return err
 		}
@@ -37,12 +44,30 @@ var setPublishedDeployPercentageCmd = &cobra.Command{
 			return err
 		}
 
+		if scheduleAt != "" {
+			return scheduleJob(ctx, client, schedule.Job{
+				Item:             itemName,
+				Operation:        schedule.OperationSetDeployPercentage,
+				DeployPercentage: percentage,
+				Force:            force,
+			})
+		}
+
 		result, err := client.Publishers.Items.SetPublishedDeployPercentage(itemName).
+			Context(ctx).
 			DeployPercentage(percentage).
+			Validate(!force).
 			Do()
 		if err != nil {
-			return fmt.Errorf("failed to set deploy percentage: %w", err)
+			return interrupted(fmt.Errorf("failed to set deploy percentage: %w", err),
+				"The store may have applied the new percentage. Run \"cws fetch-status\" to check before retrying.")
 		}
+		reportActions(actionsResult{
+			title:            "Deploy percentage set",
+			item:             itemName,
+			deployPercentage: percentage,
+			hasPercentage:    true,
+		})
 
 		if jsonOutput {
 			output, err := json.MarshalIndent(result, "", "  ")
@@ -1,32 +1,58 @@
 package cli
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/H0R15H0/chrome-webstore-api-v2/crx"
+	"github.com/H0R15H0/chrome-webstore-api-v2/manifest"
+	"github.com/H0R15H0/chrome-webstore-api-v2/pack"
 	"github.com/spf13/cobra"
 )
 
+var (
+	uploadValidate     bool
+	uploadStripKey     bool
+	uploadSignKey      string
+	uploadSkipKeyCheck bool
+)
+
 func init() {
+	uploadCmd.Flags().BoolVar(&uploadValidate, "validate", false, "Validate the package before uploading")
+	uploadCmd.Flags().BoolVar(&uploadStripKey, "strip-key", false, "Remove the key field from manifest.json when uploading a directory")
+	uploadCmd.Flags().StringVar(&uploadSignKey, "sign-key", "", "Sign the package as a CRX3 with this PEM-encoded private key before uploading")
+	uploadCmd.Flags().BoolVar(&uploadSkipKeyCheck, "skip-key-check", false, "Do not check a CRX's public key against the item's public key")
 	rootCmd.AddCommand(uploadCmd)
 }
 
 var uploadCmd = &cobra.Command{
-	Use:   "upload <file.zip>",
+	Use:   "upload <file.zip|file.crx|dir>",
 	Short: "Upload an extension package",
-	Long:  `Upload a ZIP file containing the extension package to Chrome Web Store.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Upload a ZIP file containing the extension package to Chrome Web Store.
+
+If a directory is given, it is packed as with "cws pack" and streamed to the
+store. With --validate, the packed files are validated rather than the whole
+directory.
+
+CRX files are detected automatically and uploaded as verified uploads. Before
+sending, the CRX signature is checked and its public key is compared with the
+item's public key in the store.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
 		filePath := args[0]
 
-		file, err := os.Open(filePath)
+		info, err := os.Stat(filePath)
 		if err != nil {
 			return fmt.Errorf("failed to open file: %w", err)
 		}
-		defer file.Close()
 
-		client, err := createClient()
+		client, err := createClient(ctx)
 		if err != nil {
 			return err
 		}
@@ -36,10 +62,93 @@ var uploadCmd = &cobra.Command{
 			return err
 		}
 
-		result, err := client.Media.Upload(itemName).Media(file, "application/zip").Do()
+		// A directory is validated as packed, so that files left out of
+		// the package are not checked, and the validated bytes uploaded.
+		packOpts := pack.Options{StripKey: uploadStripKey, OnSkip: warnSkipped}
+		var packed []byte
+		if uploadValidate {
+			var pkg *manifest.Package
+			if info.IsDir() {
+				if packed, err = pack.Bytes(filePath, packOpts); err != nil {
+					return err
+				}
+				pkg, err = manifest.OpenBytes(packed)
+			} else {
+				pkg, err = manifest.Open(filePath)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to open package: %w", err)
+			}
+
+			storeVersion, err := fetchStoreVersion(ctx, client, itemName)
+			if err != nil {
+				return err
+			}
+
+			problems := manifest.Validate(pkg, manifest.ValidateOptions{StoreVersion: storeVersion})
+			if len(problems) > 0 {
+				printProblems(problems)
+			}
+			annotateProblems(filePath, problems)
+			if problems.HasErrors() {
+				return fmt.Errorf("package validation failed")
+			}
+		}
+
+		call := client.Media.Upload(itemName).Context(ctx)
+		var crxData []byte
+		switch {
+		case uploadSignKey != "":
+			crxData, err = signPackage(filePath, uploadSignKey, uploadStripKey)
+			if err != nil {
+				return err
+			}
+		case packed != nil:
+			call.Media(bytes.NewReader(packed), chromewebstore.MediaTypeZIP)
+		case info.IsDir():
+			call.Directory(filePath, packOpts)
+		default:
+			file, err := os.Open(filePath)
+			if err != nil {
+				return fmt.Errorf("failed to open file: %w", err)
+			}
+			defer file.Close()
+
+			header := make([]byte, 4)
+			n, _ := io.ReadFull(file, header)
+			if crx.IsCRX(header[:n]) {
+				crxData, err = os.ReadFile(filePath)
+				if err != nil {
+					return fmt.Errorf("failed to open file: %w", err)
+				}
+			} else {
+				if _, err := file.Seek(0, io.SeekStart); err != nil {
+					return fmt.Errorf("failed to read file: %w", err)
+				}
+				call.Media(file, chromewebstore.MediaTypeZIP)
+			}
+		}
+
+		if crxData != nil {
+			if !uploadSkipKeyCheck {
+				if err := verifyCRXAgainstStore(ctx, client, itemName, crxData); err != nil {
+					return err
+				}
+			}
+			call.Media(bytes.NewReader(crxData), chromewebstore.MediaTypeCRX)
+		}
+
+		result, err := call.Do()
 		if err != nil {
-			return fmt.Errorf("failed to upload: %w", err)
+			return interrupted(fmt.Errorf("failed to upload: %w", err),
+				"The package may still have reached the store. Run \"cws fetch-status --json\" and check lastAsyncUploadState before retrying.")
 		}
+		reportActions(actionsResult{
+			title:       "Uploaded " + filePath,
+			item:        itemName,
+			crxVersion:  result.CrxVersion,
+			uploadState: result.UploadState,
+		})
 
 		if jsonOutput {
 			output, err := json.MarshalIndent(result, "", "  ")
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/H0R15H0/chrome-webstore-api-v2/manifest"
+	"github.com/spf13/cobra"
+)
+
+var (
+	validateOffline bool
+	validateMaxSize int64
+)
+
+func init() {
+	validateCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	validateCmd.Flags().BoolVar(&validateOffline, "offline", false, "Skip the version check against the store")
+	validateCmd.Flags().Int64Var(&validateMaxSize, "max-size", manifest.DefaultMaxPackageSize, "Maximum package size in bytes")
+	rootCmd.AddCommand(validateCmd)
+}
+
+var validateCmd = &cobra.Command{
+	Use:   "validate <package>",
+	Short: "Validate an extension package before uploading",
+	Long: `Check a ZIP file, CRX file or extension directory for common causes of
+rejected uploads and report every problem found.
+
+Unless --offline is given, the manifest version is compared with the
+versions returned by fetch-status.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		pkg, err := manifest.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open package: %w", err)
+		}
+
+		opts := manifest.ValidateOptions{MaxSize: validateMaxSize}
+		if !validateOffline {
+			client, err := createClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			itemName, err := getItemName()
+			if err != nil {
+				return err
+			}
+
+			opts.StoreVersion, err = fetchStoreVersion(ctx, client, itemName)
+			if err != nil {
+				return err
+			}
+		}
+
+		problems := manifest.Validate(pkg, opts)
+		annotateProblems(args[0], problems)
+
+		if jsonOutput {
+			if problems == nil {
+				problems = manifest.Problems{}
+			}
+			output, err := json.MarshalIndent(problems, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(output))
+		} else {
+			printProblems(problems)
+		}
+
+		if problems.HasErrors() {
+			return fmt.Errorf("package validation failed")
+		}
+		return nil
+	},
+}
+
+// fetchStoreVersion returns the highest of the published and submitted
+// CRX versions of the item, or an empty string if there are none.
+func fetchStoreVersion(ctx context.Context, client *chromewebstore.Client, itemName chromewebstore.ItemName) (string, error) {
+	status, err := client.Publishers.Items.FetchStatus(itemName).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch status: %w", err)
+	}
+	return latestStoreVersion(status), nil
+}
+
+// latestStoreVersion returns the highest of the published and submitted
+// CRX versions in status, or an empty string if there are none.
+func latestStoreVersion(status *chromewebstore.ItemStatus) string {
+	var versions []string
+	for _, rev := range []*chromewebstore.ItemRevisionStatus{status.PublishedItemRevisionStatus, status.SubmittedItemRevisionStatus} {
+		if rev == nil {
+			continue
+		}
+		for _, ch := range rev.DistributionChannels {
+			versions = append(versions, ch.CrxVersion)
+		}
+	}
+
+	if v := manifest.HighestVersion(versions...); v != nil {
+		return v.String()
+	}
+	return ""
+}
+
+// printProblems prints validation problems in a human-readable form.
+func printProblems(problems manifest.Problems) {
+	if len(problems) == 0 {
+		fmt.Println("No problems found")
+		return
+	}
+	for _, p := range problems {
+		fmt.Printf("%-7s %-16s %s\n", p.Severity, p.Check, p.Message)
+	}
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/crx"
+	"github.com/H0R15H0/chrome-webstore-api-v2/manifest"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifyKeyPrivateKey string
+	verifyKeyManifest   string
+)
+
+func init() {
+	verifyKeyCmd.Flags().StringVar(&verifyKeyPrivateKey, "key", "", "PEM-encoded RSA private key")
+	verifyKeyCmd.Flags().StringVar(&verifyKeyManifest, "manifest", "", "Package, directory or manifest.json whose key field to check")
+	rootCmd.AddCommand(verifyKeyCmd)
+}
+
+var verifyKeyCmd = &cobra.Command{
+	Use:   "verify-key",
+	Short: "Check that local keys, the item ID and the store's public key agree",
+	Long: `Derive the extension ID from a local private key and/or a manifest key
+field and check that it matches the configured item ID and the public key
+returned by fetch-status.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		client, err := createClient(ctx)
+		if err != nil {
+			return err
+		}
+
+		itemName, err := getItemName()
+		if err != nil {
+			return err
+		}
+
+		type source struct {
+			name string
+			id   string
+		}
+		sources := []source{{"--item-id", getItemID()}}
+
+		if verifyKeyPrivateKey != "" {
+			pemData, err := os.ReadFile(verifyKeyPrivateKey)
+			if err != nil {
+				return fmt.Errorf("failed to read private key: %w", err)
+			}
+			key, err := crx.ParsePrivateKey(pemData)
+			if err != nil {
+				return err
+			}
+			der, err := crx.PublicKey(key)
+			if err != nil {
+				return err
+			}
+			sources = append(sources, source{"private key", crx.ExtensionID(der)})
+		}
+
+		if verifyKeyManifest != "" {
+			key, err := readManifestKey(verifyKeyManifest)
+			if err != nil {
+				return err
+			}
+			id, err := crx.ExtensionIDFromKey(key)
+			if err != nil {
+				return err
+			}
+			sources = append(sources, source{"manifest key", id})
+		}
+
+		status, err := client.Publishers.Items.FetchStatus(itemName).Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("failed to fetch status: %w", err)
+		}
+		if status.PublicKey == "" {
+			return fmt.Errorf("the store returned no public key for %s", itemName)
+		}
+		storeID, err := crx.ExtensionIDFromKey(status.PublicKey)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, source{"store public key", storeID})
+
+		mismatch := false
+		for _, s := range sources {
+			mark := "ok"
+			if s.id != sources[0].id {
+				mark = "MISMATCH"
+				mismatch = true
+			}
+			fmt.Printf("%-17s %s  %s\n", s.name+":", s.id, mark)
+		}
+
+		if mismatch {
+			return fmt.Errorf("extension IDs do not agree")
+		}
+		return nil
+	},
+}
+
+// readManifestKey returns the key field of the manifest at path, which may be
+// a manifest.json file or anything accepted by manifest.Open.
+func readManifestKey(path string) (string, error) {
+	var m *manifest.Manifest
+	if info, err := os.Stat(path); err == nil && !info.IsDir() && !isArchive(path) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read manifest: %w", err)
+		}
+		m, err = manifest.Parse(data)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		pkg, err := manifest.Open(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to open package: %w", err)
+		}
+		if pkg.ManifestErr != nil {
+			return "", pkg.ManifestErr
+		}
+		m = pkg.Manifest
+	}
+
+	if m.Key == "" {
+		return "", fmt.Errorf("manifest has no key field")
+	}
+	return m.Key, nil
+}
+
+// isArchive reports whether the file at path is a ZIP or CRX archive.
+func isArchive(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	header := make([]byte, 4)
+	if _, err := f.Read(header); err != nil {
+		return false
+	}
+	return crx.IsCRX(header) || string(header) == "PK\x03\x04"
+}
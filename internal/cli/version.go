@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/manifest"
+	"github.com/spf13/cobra"
+)
+
+var (
+	versionManifestPath string
+	versionFromStore    bool
+)
+
+func init() {
+	versionCmd.PersistentFlags().StringVar(&versionManifestPath, "manifest", manifest.FileName, "Path to manifest.json or the directory containing it")
+	versionBumpCmd.Flags().BoolVar(&versionFromStore, "from-store", false, "Bump relative to the highest of the store's published and submitted versions instead of the manifest version")
+	versionCmd.AddCommand(versionBumpCmd)
+	versionCmd.AddCommand(versionSetCmd)
+	rootCmd.AddCommand(versionCmd)
+}
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show or change the manifest version",
+	Long:  `Show or change the version field of manifest.json, preserving its formatting.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, data, err := readManifestFile()
+		if err != nil {
+			return err
+		}
+
+		m, err := manifest.Parse(data)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(m.Version)
+		return nil
+	},
+}
+
+var versionBumpCmd = &cobra.Command{
+	Use:       "bump <major|minor|patch|build>",
+	Short:     "Increment the manifest version",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"major", "minor", "patch", "build"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		part, err := manifest.ParsePart(args[0])
+		if err != nil {
+			return err
+		}
+
+		path, data, err := readManifestFile()
+		if err != nil {
+			return err
+		}
+
+		m, err := manifest.Parse(data)
+		if err != nil {
+			return err
+		}
+
+		base := m.Version
+		if versionFromStore {
+			client, err := createClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			itemName, err := getItemName()
+			if err != nil {
+				return err
+			}
+
+			storeVersion, err := fetchStoreVersion(ctx, client, itemName)
+			if err != nil {
+				return err
+			}
+			if storeVersion == "" {
+				return fmt.Errorf("the store has no published or submitted version")
+			}
+			base = storeVersion
+		}
+
+		current, err := manifest.ParseVersion(base)
+		if err != nil {
+			return err
+		}
+
+		next, err := current.Bump(part)
+		if err != nil {
+			return err
+		}
+		if local, err := manifest.ParseVersion(m.Version); err == nil && next.Compare(local) <= 0 {
+			fmt.Fprintf(os.Stderr, "warning: %s is not greater than the manifest version %s\n", next, local)
+		}
+
+		return writeManifestVersion(path, data, m.Version, next)
+	},
+}
+
+var versionSetCmd = &cobra.Command{
+	Use:   "set <version>",
+	Short: "Set the manifest version",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		next, err := manifest.ParseVersion(args[0])
+		if err != nil {
+			return err
+		}
+
+		path, data, err := readManifestFile()
+		if err != nil {
+			return err
+		}
+
+		m, err := manifest.Parse(data)
+		if err != nil {
+			return err
+		}
+
+		return writeManifestVersion(path, data, m.Version, next)
+	},
+}
+
+// readManifestFile resolves --manifest and reads the manifest file.
+func readManifestFile() (string, []byte, error) {
+	path := versionManifestPath
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, manifest.FileName)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	return path, data, nil
+}
+
+// writeManifestVersion rewrites the version field of the manifest at path.
+func writeManifestVersion(path string, data []byte, old string, next manifest.Version) error {
+	updated, err := manifest.SetString(data, "version", next.String())
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, updated, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	fmt.Printf("%s -> %s\n", old, next)
+	return nil
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/spf13/cobra"
+)
+
+var watchInterval time.Duration
+
+func init() {
+	watchCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output events as JSON lines")
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", chromewebstore.DefaultWatchInterval, "Polling interval")
+	addNotifyFlags(watchCmd)
+	rootCmd.AddCommand(watchCmd)
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [item-id...]",
+	Short: "Stream status changes of one or more items",
+	Long: `Poll the status of one or more items and print an event for every change
+of state, version, deploy percentage, policy flags or upload state.
+
+If no item IDs are given, the configured item is watched. Use --webhook or
+--notify-config to post the events to Slack, Teams or any JSON endpoint.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		if watchInterval <= 0 {
+			return fmt.Errorf("invalid --interval %s: must be positive", watchInterval)
+		}
+
+		client, err := createClient(ctx)
+		if err != nil {
+			return err
+		}
+
+		names, err := getItemNames(args)
+		if err != nil {
+			return err
+		}
+
+		notifier, err := loadNotifier()
+		if err != nil {
+			return err
+		}
+
+		call := client.Publishers.Items.Watch(names...).
+			Context(ctx).
+			Interval(watchInterval)
+
+		for event, err := range call.Seq() {
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: failed to fetch status: %v\n", event.Item, err)
+				continue
+			}
+
+			if jsonOutput {
+				output, err := json.Marshal(event)
+				if err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
+				fmt.Println(string(output))
+			} else {
+				fmt.Printf("%s %s\n", event.Time.Format(time.RFC3339), event)
+			}
+
+			sendNotification(ctx, notifier, event)
+		}
+
+		return nil
+	},
+}
+
+// getItemNames returns item names for the given item IDs under the configured
+// publisher, or the configured item if no IDs are given.
+func getItemNames(itemIDs []string) ([]chromewebstore.ItemName, error) {
+	if len(itemIDs) == 0 {
+		itemName, err := getItemName()
+		if err != nil {
+			return nil, err
+		}
+		return []chromewebstore.ItemName{itemName}, nil
+	}
+
+	pubID := getPublisherID()
+	if pubID == "" {
+		return nil, fmt.Errorf("publisher-id is required (use --publisher-id flag or CHROME_WEBSTORE_PUBLISHER_ID environment variable)")
+	}
+
+	names := make([]chromewebstore.ItemName, 0, len(itemIDs))
+	for _, id := range itemIDs {
+		if err := chromewebstore.ValidateItemID(id); err != nil {
+			return nil, err
+		}
+		names = append(names, chromewebstore.NewItemName(pubID, id))
+	}
+	return names, nil
+}
@@ -0,0 +1,29 @@
+// Command cwsgen generates the chromewebstore package's API types, services
+// and Call builders from the checked-in discovery document. It is run by
+// go generate in the chromewebstore directory.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/internal/codegen"
+)
+
+func main() {
+	discovery := flag.String("discovery", "discovery.json", "path to the discovery document")
+	config := flag.String("config", "codegen.json", "path to the generator configuration")
+	out := flag.String("o", "api_gen.go", "path of the generated file")
+	flag.Parse()
+
+	src, err := codegen.GenerateFiles(*discovery, *config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cwsgen:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "cwsgen:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,164 @@
+// Package codegen generates the Chrome Web Store client from a Google API
+// discovery document: enum types, request and response structs, services
+// and fluent Call builders.
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Document is the subset of a discovery document used by the generator.
+type Document struct {
+	ID        string               `json:"id"`
+	Name      string               `json:"name"`
+	Version   string               `json:"version"`
+	Schemas   map[string]*Schema   `json:"schemas"`
+	Resources map[string]*Resource `json:"resources"`
+}
+
+// Schema describes a JSON value.
+type Schema struct {
+	ID               string     `json:"id"`
+	Type             string     `json:"type"`
+	Format           string     `json:"format"`
+	Description      string     `json:"description"`
+	Ref              string     `json:"$ref"`
+	Items            *Schema    `json:"items"`
+	Properties       Properties `json:"properties"`
+	Enum             []string   `json:"enum"`
+	EnumDescriptions []string   `json:"enumDescriptions"`
+	Default          string     `json:"default"`
+}
+
+// Property is a named property of an object schema.
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties are the properties of an object schema in document order,
+// which is kept so that generated structs list fields in the same order.
+type Properties []Property
+
+// UnmarshalJSON decodes a JSON object into properties, preserving the
+// order of its keys.
+func (p *Properties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("codegen: properties must be an object")
+	}
+	*p = nil
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("codegen: %w", err)
+		}
+		var s Schema
+		if err := dec.Decode(&s); err != nil {
+			return fmt.Errorf("codegen: property %s: %w", tok, err)
+		}
+		*p = append(*p, Property{Name: tok.(string), Schema: &s})
+	}
+	return nil
+}
+
+// Resource is a collection of methods and nested resources.
+type Resource struct {
+	Methods   map[string]*Method   `json:"methods"`
+	Resources map[string]*Resource `json:"resources"`
+}
+
+// Method is an API method.
+type Method struct {
+	ID                  string                `json:"id"`
+	Path                string                `json:"path"`
+	HTTPMethod          string                `json:"httpMethod"`
+	Description         string                `json:"description"`
+	Parameters          map[string]*Parameter `json:"parameters"`
+	ParameterOrder      []string              `json:"parameterOrder"`
+	Request             *Schema               `json:"request"`
+	Response            *Schema               `json:"response"`
+	SupportsMediaUpload bool                  `json:"supportsMediaUpload"`
+}
+
+// Parameter is a path or query parameter of a method.
+type Parameter struct {
+	Schema
+	Location string `json:"location"`
+	Required bool   `json:"required"`
+}
+
+// ParseDocument parses a discovery document.
+func ParseDocument(data []byte) (*Document, error) {
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("codegen: failed to parse discovery document: %w", err)
+	}
+	return &doc, nil
+}
+
+// Config adapts the generated code to the hand-written parts of the
+// package.
+type Config struct {
+	// Package is the name of the generated package.
+	Package string `json:"package"`
+	// Schemas renames schemas, mapping discovery names to Go type names.
+	// Other schemas keep their discovery names.
+	Schemas map[string]string `json:"schemas"`
+	// Enums maps Go type names to the enum properties, written as
+	// "Schema.property", that share the type.
+	Enums map[string]Enum `json:"enums"`
+	// Handwritten lists the IDs of methods whose Call types are written by
+	// hand. Their service accessors are still generated and call the
+	// hand-written new<Method>Call constructors.
+	Handwritten []string `json:"handwritten"`
+	// Policy lists the IDs of methods checked by the client's policy before
+	// their requests are sent. Their Call types must have a hand-written
+	// policyRequest method describing the operation.
+	Policy []string `json:"policy"`
+}
+
+// Enum configures a generated enum type.
+type Enum struct {
+	// Description completes the type's doc comment after its name.
+	Description string `json:"description"`
+	// Properties are the properties of this type.
+	Properties []string `json:"properties"`
+}
+
+// ParseConfig parses a generator configuration.
+func ParseConfig(data []byte) (*Config, error) {
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("codegen: failed to parse config: %w", err)
+	}
+	if cfg.Package == "" {
+		return nil, fmt.Errorf("codegen: config has no package")
+	}
+	return &cfg, nil
+}
+
+// GenerateFiles reads the discovery document and config at the given paths
+// and returns the generated source.
+func GenerateFiles(discoveryPath, configPath string) ([]byte, error) {
+	data, err := os.ReadFile(discoveryPath)
+	if err != nil {
+		return nil, fmt.Errorf("codegen: %w", err)
+	}
+	doc, err := ParseDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	data, err = os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("codegen: %w", err)
+	}
+	cfg, err := ParseConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	return Generate(doc, cfg)
+}
@@ -0,0 +1,533 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Header is the first line of every generated file.
+const Header = "// Code generated by cwsgen from the Chrome Web Store discovery document. DO NOT EDIT."
+
+// initialisms are words written in upper case in Go names.
+var initialisms = map[string]bool{"API": true, "HTTP": true, "ID": true, "URL": true}
+
+// Generate returns the gofmt-formatted Go source generated from doc.
+//
+// Every method must take the item resource name as its only path
+// parameter, {+name}, which becomes an ItemName argument. Methods
+// supporting media upload must be listed as hand-written.
+func Generate(doc *Document, cfg *Config) ([]byte, error) {
+	g := &generator{
+		doc:         doc,
+		cfg:         cfg,
+		enumOf:      make(map[string]string),
+		responses:   make(map[string]bool),
+		handwritten: make(map[string]bool),
+		callNames:   make(map[string]string),
+		imports:     make(map[string]bool),
+	}
+	for _, id := range cfg.Handwritten {
+		g.handwritten[id] = true
+	}
+	if err := g.collect(); err != nil {
+		return nil, err
+	}
+	if err := g.genEnums(); err != nil {
+		return nil, err
+	}
+	if err := g.genSchemas(); err != nil {
+		return nil, err
+	}
+	if err := g.genResources("", doc.Resources); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "%s\n\npackage %s\n\n", Header, cfg.Package)
+	if len(g.imports) > 0 {
+		out.WriteString("import (\n")
+		for _, path := range slices.Sorted(maps.Keys(g.imports)) {
+			fmt.Fprintf(&out, "\t%q\n", path)
+		}
+		out.WriteString(")\n\n")
+	}
+	out.Write(g.buf.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("codegen: failed to format generated code: %w", err)
+	}
+	return src, nil
+}
+
+type generator struct {
+	doc *Document
+	cfg *Config
+	buf bytes.Buffer
+
+	// enumOf maps "Schema.property" to the enum type of the property.
+	enumOf map[string]string
+	// responses are the schemas returned by methods.
+	responses map[string]bool
+	// handwritten are the IDs of methods without generated Call types.
+	handwritten map[string]bool
+	// callNames maps generated Call type names to their method IDs.
+	callNames map[string]string
+	imports   map[string]bool
+}
+
+// collect indexes the enum properties and the response schemas.
+func (g *generator) collect() error {
+	for typeName, enum := range g.cfg.Enums {
+		for _, ref := range enum.Properties {
+			prop := g.propertyRef(ref)
+			if prop == nil {
+				return fmt.Errorf("codegen: enum %s: unknown property %q", typeName, ref)
+			}
+			if prop.Type != "string" || len(prop.Enum) == 0 {
+				return fmt.Errorf("codegen: enum %s: property %q is not a string enum", typeName, ref)
+			}
+			g.enumOf[ref] = typeName
+		}
+	}
+
+	var walk func(resources map[string]*Resource)
+	walk = func(resources map[string]*Resource) {
+		for _, r := range resources {
+			for _, m := range r.Methods {
+				if m.Response != nil && m.Response.Ref != "" {
+					g.responses[m.Response.Ref] = true
+				}
+			}
+			walk(r.Resources)
+		}
+	}
+	walk(g.doc.Resources)
+	return nil
+}
+
+// propertyRef returns the property referred to as "Schema.property", or nil.
+func (g *generator) propertyRef(ref string) *Schema {
+	schemaName, propName, _ := strings.Cut(ref, ".")
+	s := g.doc.Schemas[schemaName]
+	if s == nil {
+		return nil
+	}
+	for _, p := range s.Properties {
+		if p.Name == propName {
+			return p.Schema
+		}
+	}
+	return nil
+}
+
+// typeName returns the Go name of a schema.
+func (g *generator) typeName(schemaName string) string {
+	if name, ok := g.cfg.Schemas[schemaName]; ok {
+		return name
+	}
+	return exportedName(schemaName)
+}
+
+func (g *generator) genEnums() error {
+	for _, typeName := range slices.Sorted(maps.Keys(g.cfg.Enums)) {
+		enum := g.cfg.Enums[typeName]
+		first := g.propertyRef(enum.Properties[0])
+		for _, ref := range enum.Properties[1:] {
+			if !slices.Equal(g.propertyRef(ref).Enum, first.Enum) {
+				return fmt.Errorf("codegen: enum %s: %s has different values than %s", typeName, ref, enum.Properties[0])
+			}
+		}
+
+		writeComment(&g.buf, "", typeName+" "+enum.Description)
+		fmt.Fprintf(&g.buf, "type %s string\n\nconst (\n", typeName)
+		for i, value := range first.Enum {
+			name := typeName + enumValueName(typeName, value)
+			if i < len(first.EnumDescriptions) && first.EnumDescriptions[i] != "" {
+				writeComment(&g.buf, "\t", name+": "+first.EnumDescriptions[i])
+			}
+			fmt.Fprintf(&g.buf, "\t%s %s = %q\n", name, typeName, value)
+		}
+		g.buf.WriteString(")\n\n")
+	}
+	return nil
+}
+
+func (g *generator) genSchemas() error {
+	names := slices.SortedFunc(maps.Keys(g.doc.Schemas), func(a, b string) int {
+		return strings.Compare(g.typeName(a), g.typeName(b))
+	})
+	for _, schemaName := range names {
+		s := g.doc.Schemas[schemaName]
+		if s.Type != "object" {
+			return fmt.Errorf("codegen: schema %s: unsupported type %q", schemaName, s.Type)
+		}
+		typeName := g.typeName(schemaName)
+		writeComment(&g.buf, "", typeName+": "+s.Description)
+		fmt.Fprintf(&g.buf, "type %s struct {\n", typeName)
+		if g.responses[schemaName] {
+			g.buf.WriteString("\tServerResponse `json:\"-\"`\n")
+			if len(s.Properties) > 0 {
+				g.buf.WriteString("\n")
+			}
+		}
+		for _, p := range s.Properties {
+			goType, err := g.goType(p.Schema, schemaName+"."+p.Name)
+			if err != nil {
+				return err
+			}
+			writeComment(&g.buf, "\t", exportedName(p.Name)+": "+p.Schema.Description)
+			fmt.Fprintf(&g.buf, "\t%s %s `json:\"%s,omitempty\"`\n", exportedName(p.Name), goType, p.Name)
+		}
+		g.buf.WriteString("}\n\n")
+	}
+	return nil
+}
+
+// goType returns the Go type of a property. ref is "Schema.property".
+func (g *generator) goType(s *Schema, ref string) (string, error) {
+	if s.Ref != "" {
+		return "*" + g.typeName(s.Ref), nil
+	}
+	switch s.Type {
+	case "string":
+		if typeName, ok := g.enumOf[ref]; ok {
+			return typeName, nil
+		}
+		return "string", nil
+	case "boolean":
+		return "bool", nil
+	case "integer":
+		return "int", nil
+	case "number":
+		return "float64", nil
+	case "array":
+		if s.Items == nil {
+			return "", fmt.Errorf("codegen: %s: array without items", ref)
+		}
+		if s.Items.Ref != "" {
+			return "[]" + g.typeName(s.Items.Ref), nil
+		}
+		elem, err := g.goType(s.Items, ref)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	}
+	return "", fmt.Errorf("codegen: %s: unsupported type %q", ref, s.Type)
+}
+
+// genResources generates a service for every resource and the Call types
+// of their methods. parent is the dotted path of the enclosing resource.
+func (g *generator) genResources(parent string, resources map[string]*Resource) error {
+	for _, name := range slices.Sorted(maps.Keys(resources)) {
+		r := resources[name]
+		path := name
+		if parent != "" {
+			path = parent + "." + name
+		}
+		serviceName := exportedName(name) + "Service"
+
+		writeComment(&g.buf, "", fmt.Sprintf("%s provides access to the %s resource.", serviceName, path))
+		fmt.Fprintf(&g.buf, "type %s struct {\n\tclient *Client\n", serviceName)
+		children := slices.Sorted(maps.Keys(r.Resources))
+		for _, child := range children {
+			g.buf.WriteString("\n")
+			writeComment(&g.buf, "\t", fmt.Sprintf("%s provides access to the %s.%s resource.", exportedName(child), path, child))
+			fmt.Fprintf(&g.buf, "\t%s *%sService\n", exportedName(child), exportedName(child))
+		}
+		g.buf.WriteString("}\n\n")
+
+		writeComment(&g.buf, "", fmt.Sprintf("new%s creates a new %s.", serviceName, serviceName))
+		fmt.Fprintf(&g.buf, "func new%s(c *Client) *%s {\n\ts := &%s{client: c}\n", serviceName, serviceName, serviceName)
+		for _, child := range children {
+			fmt.Fprintf(&g.buf, "\ts.%s = new%sService(c)\n", exportedName(child), exportedName(child))
+		}
+		g.buf.WriteString("\treturn s\n}\n\n")
+
+		for _, methodName := range slices.Sorted(maps.Keys(r.Methods)) {
+			m := r.Methods[methodName]
+			goName := exportedName(methodName)
+			callName := goName + "Call"
+			if id, ok := g.callNames[callName]; ok {
+				return fmt.Errorf("codegen: %s and %s both generate %s", id, m.ID, callName)
+			}
+			g.callNames[callName] = m.ID
+
+			writeComment(&g.buf, "", fmt.Sprintf("%s returns %s %s. %s", goName, article(callName), callName, m.Description))
+			fmt.Fprintf(&g.buf, "func (s *%s) %s(name ItemName) *%s {\n\treturn new%s(s.client, name)\n}\n\n", serviceName, goName, callName, callName)
+		}
+
+		for _, methodName := range slices.Sorted(maps.Keys(r.Methods)) {
+			m := r.Methods[methodName]
+			if g.handwritten[m.ID] {
+				continue
+			}
+			if err := g.genCall(exportedName(methodName)+"Call", m); err != nil {
+				return err
+			}
+		}
+
+		if err := g.genResources(path, r.Resources); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *generator) genCall(callName string, m *Method) error {
+	if m.SupportsMediaUpload {
+		return fmt.Errorf("codegen: %s: media upload methods must be hand-written", m.ID)
+	}
+	if !strings.Contains(m.Path, "{+name}") {
+		return fmt.Errorf("codegen: %s: path %q does not contain {+name}", m.ID, m.Path)
+	}
+	var query []string
+	for _, name := range slices.Sorted(maps.Keys(m.Parameters)) {
+		p := m.Parameters[name]
+		switch {
+		case p.Location == "path" && name == "name":
+		case p.Location == "path":
+			return fmt.Errorf("codegen: %s: unsupported path parameter %q", m.ID, name)
+		case p.Location == "query":
+			query = append(query, name)
+		default:
+			return fmt.Errorf("codegen: %s: unsupported parameter location %q", m.ID, p.Location)
+		}
+	}
+	var request string
+	if m.Request != nil {
+		if g.doc.Schemas[m.Request.Ref] == nil {
+			return fmt.Errorf("codegen: %s: unknown request schema %q", m.ID, m.Request.Ref)
+		}
+		request = g.typeName(m.Request.Ref)
+	}
+	g.imports["context"] = true
+	g.imports["fmt"] = true
+	g.imports["net/http"] = true
+
+	writeComment(&g.buf, "", fmt.Sprintf("%s represents a call to the %s method.", callName, m.ID))
+	fmt.Fprintf(&g.buf, "type %s struct {\n\tcall\n", callName)
+	if request != "" {
+		fmt.Fprintf(&g.buf, "\trequest *%s\n", request)
+	}
+	g.buf.WriteString("}\n\n")
+
+	writeComment(&g.buf, "", fmt.Sprintf("new%s creates a new %s.", callName, callName))
+	fmt.Fprintf(&g.buf, "func new%s(client *Client, name ItemName) *%s {\n", callName, callName)
+	fmt.Fprintf(&g.buf, "\tc := &%s{\n\t\tcall: newCall(client, name),\n", callName)
+	if request != "" {
+		fmt.Fprintf(&g.buf, "\t\trequest: &%s{},\n", request)
+	}
+	g.buf.WriteString("\t}\n")
+	for _, name := range query {
+		if def := m.Parameters[name].Default; def != "" {
+			fmt.Fprintf(&g.buf, "\tc.params.Set(%q, %q)\n", name, def)
+		}
+	}
+	g.buf.WriteString("\treturn c\n}\n\n")
+
+	fmt.Fprintf(&g.buf, `// Context sets the context for the request.
+func (c *%[1]s) Context(ctx context.Context) *%[1]s {
+	c.ctx = ctx
+	return c
+}
+
+// Fields selects the fields included in the response using the partial
+// response syntax. Fields not selected are left at their zero values.
+func (c *%[1]s) Fields(fields ...string) *%[1]s {
+	c.setFields(fields)
+	return c
+}
+
+// QuotaUser sets an arbitrary string identifying the user the request is
+// made for, so that quota is enforced per user.
+func (c *%[1]s) QuotaUser(quotaUser string) *%[1]s {
+	c.setQuotaUser(quotaUser)
+	return c
+}
+
+// PrettyPrint sets whether the server indents the response.
+func (c *%[1]s) PrettyPrint(prettyPrint bool) *%[1]s {
+	c.setPrettyPrint(prettyPrint)
+	return c
+}
+
+`, callName)
+
+	for _, name := range query {
+		p := m.Parameters[name]
+		arg := argName(name)
+		var goType, value string
+		switch p.Type {
+		case "string":
+			goType, value = "string", arg
+		case "integer":
+			goType, value = "int", "strconv.Itoa("+arg+")"
+			g.imports["strconv"] = true
+		case "boolean":
+			goType, value = "bool", "strconv.FormatBool("+arg+")"
+			g.imports["strconv"] = true
+		default:
+			return fmt.Errorf("codegen: %s: unsupported type %q of parameter %q", m.ID, p.Type, name)
+		}
+		writeComment(&g.buf, "", exportedName(name)+" sets the "+name+" parameter. "+p.Description)
+		fmt.Fprintf(&g.buf, "func (c *%s) %s(%s %s) *%s {\n\tc.params.Set(%q, %s)\n\treturn c\n}\n\n",
+			callName, exportedName(name), arg, goType, callName, name, value)
+	}
+
+	if request != "" {
+		for _, p := range g.doc.Schemas[m.Request.Ref].Properties {
+			goType, err := g.goType(p.Schema, m.Request.Ref+"."+p.Name)
+			if err != nil {
+				return err
+			}
+			arg := argName(p.Name)
+			writeComment(&g.buf, "", exportedName(p.Name)+" sets the "+p.Name+" field of the request. "+p.Schema.Description)
+			fmt.Fprintf(&g.buf, "func (c *%s) %s(%s %s) *%s {\n\tc.request.%s = %s\n\treturn c\n}\n\n",
+				callName, exportedName(p.Name), arg, goType, callName, exportedName(p.Name), arg)
+		}
+	}
+
+	body := "nil"
+	if request != "" {
+		body = "c.request"
+	}
+	result, returnNil, returnResult := "", "", ""
+	if m.Response != nil {
+		result = g.typeName(m.Response.Ref)
+		returnNil, returnResult = "nil, ", "&result, "
+	}
+
+	writeComment(&g.buf, "", fmt.Sprintf("Do executes the %s request.", m.ID))
+	if result != "" {
+		fmt.Fprintf(&g.buf, "func (c *%s) Do() (*%s, error) {\n", callName, result)
+	} else {
+		fmt.Fprintf(&g.buf, "func (c *%s) Do() error {\n", callName)
+	}
+	ctx := "c.ctx"
+	if slices.Contains(g.cfg.Policy, m.ID) {
+		ctx = "ctx"
+		fmt.Fprintf(&g.buf, "\tctx, err := c.runPolicy(c.policyRequest())\n\tif err != nil {\n\t\treturn %serr\n\t}\n", returnNil)
+	}
+	fmt.Fprintf(&g.buf, `	if err := c.runPreflight(); err != nil {
+		return %[1]serr
+	}
+
+	path := fmt.Sprintf(%[2]q, c.name)
+	urlStr := buildURL(c.client.baseURL, path, c.params)
+
+	resp, err := c.client.doRequest(%[5]s, %[3]s, urlStr, c.header, %[4]s)
+	if err != nil {
+		return %[1]serr
+	}
+
+`, returnNil, "/"+strings.Replace(m.Path, "{+name}", "%s", 1), httpMethod(m.HTTPMethod), body, ctx)
+	if result != "" {
+		fmt.Fprintf(&g.buf, "\tvar result %s\n\tif err := parseResponse(resp, &result); err != nil {\n\t\treturn nil, err\n\t}\n\n", result)
+	} else {
+		g.buf.WriteString("\tif err := parseResponse(resp, nil); err != nil {\n\t\treturn err\n\t}\n\n")
+	}
+	fmt.Fprintf(&g.buf, "\treturn %snil\n}\n\n", returnResult)
+	return nil
+}
+
+// httpMethod returns the net/http constant of an HTTP method.
+func httpMethod(method string) string {
+	m := strings.ToLower(method)
+	return "http.Method" + strings.ToUpper(m[:1]) + m[1:]
+}
+
+// exportedName returns the exported Go name of a camelCase or snake_case
+// identifier, spelling initialisms such as ID in upper case.
+func exportedName(name string) string {
+	var b strings.Builder
+	for _, word := range splitWords(name) {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + strings.ToLower(word[1:]))
+	}
+	return b.String()
+}
+
+// article returns the indefinite article for a word.
+func article(word string) string {
+	if strings.ContainsRune("AEIOU", rune(word[0])) {
+		return "an"
+	}
+	return "a"
+}
+
+// argName returns a parameter name for a discovery identifier.
+func argName(name string) string {
+	if token.IsKeyword(name) {
+		return name + "Value"
+	}
+	return name
+}
+
+// enumValueName returns the Go name of an enum value without the words it
+// shares with its type, so that STATE_UNSPECIFIED of ItemState becomes
+// Unspecified and STAGED_PUBLISH of PublishType becomes Staged.
+func enumValueName(typeName, value string) string {
+	typeWords := make(map[string]bool)
+	for _, w := range splitWords(typeName) {
+		typeWords[strings.ToUpper(w)] = true
+	}
+	var words []string
+	for _, w := range splitWords(value) {
+		if !typeWords[strings.ToUpper(w)] {
+			words = append(words, w)
+		}
+	}
+	if len(words) == 0 {
+		words = splitWords(value)
+	}
+	return exportedName(strings.Join(words, "_"))
+}
+
+// splitWords splits a camelCase or snake_case identifier into words.
+func splitWords(name string) []string {
+	var words []string
+	var cur []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		if r == '_' || r == '-' || r == '.' {
+			if len(cur) > 0 {
+				words = append(words, string(cur))
+			}
+			cur = nil
+			continue
+		}
+		if unicode.IsUpper(r) && len(cur) > 0 && i > 0 && unicode.IsLower(runes[i-1]) {
+			words = append(words, string(cur))
+			cur = nil
+		}
+		cur = append(cur, r)
+	}
+	if len(cur) > 0 {
+		words = append(words, string(cur))
+	}
+	return words
+}
+
+// writeComment writes text as a doc comment wrapped at 80 columns.
+func writeComment(b *bytes.Buffer, indent, text string) {
+	line := indent + "//"
+	for _, word := range strings.Fields(text) {
+		if len(line)+1+len(word) > 80 && line != indent+"//" {
+			b.WriteString(line + "\n")
+			line = indent + "//"
+		}
+		line += " " + word
+	}
+	b.WriteString(line + "\n")
+}
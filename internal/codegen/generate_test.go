@@ -0,0 +1,226 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+)
+
+const testDocument = `{
+  "name": "test",
+  "version": "v2",
+  "schemas": {
+    "GetThingResponse": {
+      "id": "GetThingResponse",
+      "type": "object",
+      "description": "Response message for GetThing.",
+      "properties": {
+        "zeta": {"type": "string", "description": "Listed first."},
+        "itemId": {"type": "string", "description": "The item ID."},
+        "state": {"type": "string", "enum": ["STATE_UNSPECIFIED", "THING_READY"], "enumDescriptions": ["Unused.", "Ready."]},
+        "parts": {"type": "array", "items": {"$ref": "Part"}}
+      }
+    },
+    "Part": {
+      "id": "Part",
+      "type": "object",
+      "description": "A part.",
+      "properties": {
+        "count": {"type": "integer", "format": "int32"}
+      }
+    },
+    "UpdateThingRequest": {
+      "id": "UpdateThingRequest",
+      "type": "object",
+      "description": "Request message for UpdateThing.",
+      "properties": {
+        "part": {"$ref": "Part", "description": "The part to update."}
+      }
+    }
+  },
+  "resources": {
+    "things": {
+      "methods": {
+        "get": {
+          "id": "test.things.get",
+          "path": "v2/{+name}:get",
+          "httpMethod": "GET",
+          "description": "Get a thing.",
+          "parameters": {
+            "name": {"type": "string", "location": "path", "required": true},
+            "limit": {"type": "integer", "location": "query", "default": "10", "description": "Maximum parts."}
+          },
+          "response": {"$ref": "GetThingResponse"}
+        },
+        "update": {
+          "id": "test.things.update",
+          "path": "v2/{+name}:update",
+          "httpMethod": "POST",
+          "description": "Update a thing.",
+          "parameters": {
+            "name": {"type": "string", "location": "path", "required": true}
+          },
+          "request": {"$ref": "UpdateThingRequest"}
+        }
+      }
+    }
+  }
+}`
+
+func generateTest(t *testing.T, doc, config string) (string, error) {
+	t.Helper()
+	d, err := ParseDocument([]byte(doc))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg, err := ParseConfig([]byte(config))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	src, err := Generate(d, cfg)
+	return string(src), err
+}
+
+func TestGenerate(t *testing.T) {
+	src, err := generateTest(t, testDocument, `{
+  "package": "test",
+  "schemas": {"GetThingResponse": "Thing"},
+  "enums": {"ThingState": {"description": "is the state of a thing.", "properties": ["GetThingResponse.state"]}}
+}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{
+		Header,
+		"package test",
+		"// ThingState is the state of a thing.\ntype ThingState string",
+		`ThingStateUnspecified ThingState = "STATE_UNSPECIFIED"`,
+		`ThingStateReady ThingState = "THING_READY"`,
+		"type Thing struct {\n\tServerResponse `json:\"-\"`",
+		"State ThingState `json:\"state,omitempty\"`",
+		"Parts []Part `json:\"parts,omitempty\"`",
+		"Part *Part `json:\"part,omitempty\"`",
+		"func (s *ThingsService) Get(name ItemName) *GetCall",
+		`c.params.Set("limit", "10")`,
+		"func (c *GetCall) Limit(limit int) *GetCall",
+		`path := fmt.Sprintf("/v2/%s:get", c.name)`,
+		"c.client.doRequest(c.ctx, http.MethodGet, urlStr, c.header, nil)",
+		"func (c *GetCall) Do() (*Thing, error)",
+		"func (c *UpdateCall) Part(part *Part) *UpdateCall",
+		"c.client.doRequest(c.ctx, http.MethodPost, urlStr, c.header, c.request)",
+		"func (c *UpdateCall) Do() error",
+	} {
+		if !strings.Contains(src, expected) {
+			t.Errorf("expected generated code to contain %q", expected)
+		}
+	}
+
+	if strings.Index(src, "Zeta string") > strings.Index(src, "ItemID string") {
+		t.Error("expected fields in document order")
+	}
+}
+
+func TestGenerateHandwritten(t *testing.T) {
+	src, err := generateTest(t, testDocument, `{"package": "test", "handwritten": ["test.things.update"]}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(src, "return newUpdateCall(s.client, name)") {
+		t.Error("expected accessor for hand-written call")
+	}
+	if strings.Contains(src, "type UpdateCall struct") {
+		t.Error("expected no generated type for hand-written call")
+	}
+}
+
+func TestGeneratePolicy(t *testing.T) {
+	src, err := generateTest(t, testDocument, `{"package": "test", "policy": ["test.things.update"]}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := strings.Count(src, "c.runPolicy(c.policyRequest())"); n != 1 {
+		t.Errorf("expected 1 policy check, got %d", n)
+	}
+	if strings.Index(src, "c.runPolicy(") < strings.Index(src, "func (c *UpdateCall) Do()") {
+		t.Error("expected the policy check in UpdateCall.Do")
+	}
+	if n := strings.Count(src, "c.client.doRequest(ctx, "); n != 1 {
+		t.Errorf("expected 1 request sent with the policy context, got %d", n)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		doc    string
+		config string
+		errMsg string
+	}{
+		{
+			name:   "unknown enum property",
+			doc:    testDocument,
+			config: `{"package": "test", "enums": {"State": {"properties": ["Part.state"]}}}`,
+			errMsg: "unknown property",
+		},
+		{
+			name:   "non-enum property",
+			doc:    testDocument,
+			config: `{"package": "test", "enums": {"State": {"properties": ["GetThingResponse.zeta"]}}}`,
+			errMsg: "not a string enum",
+		},
+		{
+			name:   "media upload",
+			doc:    strings.Replace(testDocument, `"httpMethod": "POST",`, `"httpMethod": "POST", "supportsMediaUpload": true,`, 1),
+			config: `{"package": "test"}`,
+			errMsg: "must be hand-written",
+		},
+		{
+			name:   "other path parameter",
+			doc:    strings.Replace(testDocument, `"v2/{+name}:get"`, `"v2/{+parent}/things"`, 1),
+			config: `{"package": "test"}`,
+			errMsg: "does not contain {+name}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generateTest(t, tt.doc, tt.config)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestExportedName(t *testing.T) {
+	tests := map[string]string{
+		"itemId":               "ItemID",
+		"lastAsyncUploadState": "LastAsyncUploadState",
+		"crxVersion":           "CrxVersion",
+		"publishers":           "Publishers",
+		"PENDING_REVIEW":       "PendingReview",
+		"upload_protocol":      "UploadProtocol",
+	}
+	for name, expected := range tests {
+		if got := exportedName(name); got != expected {
+			t.Errorf("exportedName(%q): expected %s, got %s", name, expected, got)
+		}
+	}
+}
+
+func TestEnumValueName(t *testing.T) {
+	tests := []struct {
+		typeName, value, expected string
+	}{
+		{"ItemState", "STATE_UNSPECIFIED", "Unspecified"},
+		{"ItemState", "PUBLISHED_TO_TESTERS", "PublishedToTesters"},
+		{"PublishType", "STAGED_PUBLISH", "Staged"},
+		{"UploadState", "IN_PROGRESS", "InProgress"},
+		{"ItemState", "STATE", "State"},
+	}
+	for _, tt := range tests {
+		if got := enumValueName(tt.typeName, tt.value); got != tt.expected {
+			t.Errorf("enumValueName(%q, %q): expected %s, got %s", tt.typeName, tt.value, tt.expected, got)
+		}
+	}
+}
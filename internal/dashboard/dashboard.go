@@ -0,0 +1,204 @@
+// Package dashboard implements an interactive terminal dashboard showing the
+// status of Chrome Web Store items, refreshed periodically, with key
+// bindings to publish, cancel submissions and raise deploy percentages.
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+const (
+	// DefaultInterval is the default refresh interval.
+	DefaultInterval = 30 * time.Second
+	// DefaultMaxInterval is the default upper bound of the refresh interval
+	// while fetches fail.
+	DefaultMaxInterval = 5 * time.Minute
+)
+
+// Client performs the API calls of the dashboard.
+type Client interface {
+	FetchStatus(ctx context.Context, item chromewebstore.ItemName) (*chromewebstore.ItemStatus, error)
+	Publish(ctx context.Context, item chromewebstore.ItemName) error
+	CancelSubmission(ctx context.Context, item chromewebstore.ItemName) error
+	SetDeployPercentage(ctx context.Context, item chromewebstore.ItemName, percentage int) error
+}
+
+// Dashboard shows the status of items on a terminal.
+type Dashboard struct {
+	// Items are the items shown.
+	Items []chromewebstore.ItemName
+	// Client performs the API calls.
+	Client Client
+	// Interval is the refresh interval. Zero means DefaultInterval.
+	Interval time.Duration
+	// MaxInterval bounds the interval, which doubles after every refresh
+	// with a failed fetch. Zero means DefaultMaxInterval.
+	MaxInterval time.Duration
+	// In receives key presses from a terminal in raw mode.
+	In io.Reader
+	// Out receives the rendered screen.
+	Out io.Writer
+}
+
+// Run shows the dashboard until the user quits, In is closed or ctx is
+// done.
+func (d *Dashboard) Run(ctx context.Context) error {
+	if len(d.Items) == 0 {
+		return fmt.Errorf("dashboard: no items")
+	}
+	backoff := newBackoff(d.Interval, d.MaxInterval)
+	m := newModel(d.Items)
+
+	keys := make(chan string)
+	go readKeys(d.In, keys)
+
+	refresh := func() time.Duration {
+		failed := false
+		for _, item := range d.Items {
+			status, err := d.Client.FetchStatus(ctx, item)
+			if ctx.Err() != nil {
+				return 0
+			}
+			failed = failed || err != nil
+			m.update(item, status, err, time.Now())
+		}
+		return backoff.next(failed)
+	}
+
+	next := time.Now().Add(refresh())
+	timer := time.NewTimer(time.Until(next))
+	defer timer.Stop()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		if _, err := io.WriteString(d.Out, m.render(time.Now(), next)); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			continue
+		case <-timer.C:
+		case key, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			action := m.handleKey(key)
+			if m.quit {
+				return nil
+			}
+			if action == nil {
+				continue
+			}
+			if action.Type != ActionRefresh {
+				m.message = d.perform(ctx, *action)
+			}
+		}
+
+		next = time.Now().Add(refresh())
+		timer.Reset(time.Until(next))
+	}
+}
+
+// perform executes a confirmed action and returns a message describing the
+// outcome.
+func (d *Dashboard) perform(ctx context.Context, a Action) string {
+	var err error
+	var done string
+	switch a.Type {
+	case ActionPublish:
+		err = d.Client.Publish(ctx, a.Item)
+		done = "submitted for publishing"
+	case ActionCancelSubmission:
+		err = d.Client.CancelSubmission(ctx, a.Item)
+		done = "submission cancelled"
+	case ActionSetDeployPercentage:
+		err = d.Client.SetDeployPercentage(ctx, a.Item, a.Percentage)
+		done = fmt.Sprintf("deploy percentage set to %d%%", a.Percentage)
+	}
+	if err != nil {
+		return fmt.Sprintf("%s%s: %s failed: %v%s", red, a.Item.ItemID(), a.Type, err, reset)
+	}
+	return fmt.Sprintf("%s: %s.", a.Item.ItemID(), done)
+}
+
+// backoff computes refresh intervals that double while fetches fail.
+type backoff struct {
+	base, max, current time.Duration
+}
+
+// newBackoff returns a backoff starting at base, applying the defaults
+// for zero values.
+func newBackoff(base, maxInterval time.Duration) *backoff {
+	if base <= 0 {
+		base = DefaultInterval
+	}
+	if maxInterval <= 0 {
+		maxInterval = DefaultMaxInterval
+	}
+	maxInterval = max(maxInterval, base)
+	return &backoff{base: base, max: maxInterval, current: base}
+}
+
+// next returns the interval until the next refresh after a refresh that
+// failed or succeeded.
+func (b *backoff) next(failed bool) time.Duration {
+	if !failed {
+		b.current = b.base
+		return b.current
+	}
+	b.current = min(b.current*2, b.max)
+	return b.current
+}
+
+// readKeys sends the keys read from r to keys until r fails, then closes
+// keys.
+func readKeys(r io.Reader, keys chan<- string) {
+	defer close(keys)
+	buf := make([]byte, 64)
+	for {
+		n, err := r.Read(buf)
+		for _, key := range parseKeys(buf[:n]) {
+			keys <- key
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// parseKeys decodes the bytes read from a terminal in raw mode.
+func parseKeys(b []byte) []string {
+	var keys []string
+	for i := 0; i < len(b); i++ {
+		switch c := b[i]; {
+		case c == 0x1b && i+2 < len(b) && b[i+1] == '[':
+			switch b[i+2] {
+			case 'A':
+				keys = append(keys, keyUp)
+			case 'B':
+				keys = append(keys, keyDown)
+			}
+			i += 2
+		case c == 0x1b:
+			keys = append(keys, keyEsc)
+		case c == 0x03:
+			keys = append(keys, keyCtrlC)
+		case c == '\r' || c == '\n':
+			keys = append(keys, keyEnter)
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, keyBackspace)
+		case c >= 0x20 && c < 0x7f:
+			keys = append(keys, string(rune(c)))
+		}
+	}
+	return keys
+}
@@ -0,0 +1,168 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+var (
+	itemA = chromewebstore.NewItemName("pub", "aaaa")
+	itemB = chromewebstore.NewItemName("pub", "bbbb")
+)
+
+func publishedStatus(version string, percentage int) *chromewebstore.ItemStatus {
+	return &chromewebstore.ItemStatus{
+		LastAsyncUploadState: chromewebstore.UploadStateSucceeded,
+		PublishedItemRevisionStatus: &chromewebstore.ItemRevisionStatus{
+			State:                chromewebstore.ItemStatePublished,
+			DistributionChannels: []chromewebstore.DistributionChannel{{CrxVersion: version, DeployPercentage: percentage}},
+		},
+	}
+}
+
+type fakeClient struct {
+	statuses map[chromewebstore.ItemName]*chromewebstore.ItemStatus
+	fetchErr error
+	calls    []string
+}
+
+func (c *fakeClient) FetchStatus(ctx context.Context, item chromewebstore.ItemName) (*chromewebstore.ItemStatus, error) {
+	c.calls = append(c.calls, "fetch "+item.ItemID())
+	return c.statuses[item], c.fetchErr
+}
+
+func (c *fakeClient) Publish(ctx context.Context, item chromewebstore.ItemName) error {
+	c.calls = append(c.calls, "publish "+item.ItemID())
+	return nil
+}
+
+func (c *fakeClient) CancelSubmission(ctx context.Context, item chromewebstore.ItemName) error {
+	c.calls = append(c.calls, "cancel "+item.ItemID())
+	return nil
+}
+
+func (c *fakeClient) SetDeployPercentage(ctx context.Context, item chromewebstore.ItemName, percentage int) error {
+	c.calls = append(c.calls, "deploy "+item.ItemID())
+	return errors.New("boom")
+}
+
+func TestRun(t *testing.T) {
+	client := &fakeClient{statuses: map[chromewebstore.ItemName]*chromewebstore.ItemStatus{
+		itemA: publishedStatus("1.0", 100),
+		itemB: publishedStatus("2.0", 10),
+	}}
+	var out bytes.Buffer
+	d := &Dashboard{
+		Items:  []chromewebstore.ItemName{itemA, itemB},
+		Client: client,
+		In:     strings.NewReader("pn" + "\x1b[B" + "d50\ry" + "q"),
+		Out:    &out,
+	}
+
+	if err := d.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"fetch aaaa", "fetch bbbb", "deploy bbbb", "fetch aaaa", "fetch bbbb"}
+	if !slices.Equal(client.calls, expected) {
+		t.Errorf("expected calls %v, got %v", expected, client.calls)
+	}
+	if !strings.Contains(out.String(), "bbbb: set-deploy-percentage failed: boom") {
+		t.Error("expected failure message in output")
+	}
+}
+
+func TestModelPrompts(t *testing.T) {
+	m := newModel([]chromewebstore.ItemName{itemA})
+
+	if m.handleKey("c"); m.mode != modeNormal || !strings.Contains(m.message, "no pending submission") {
+		t.Errorf("expected cancel to be refused without a submission, got %q", m.message)
+	}
+
+	m.update(itemA, publishedStatus("1.0", 20), nil, time.Now())
+	m.handleKey("d")
+	if m.mode != modeInput {
+		t.Fatal("expected input mode")
+	}
+	for _, key := range []string{"1", "x", "5", keyBackspace, "0", keyEnter} {
+		m.handleKey(key)
+	}
+	if !strings.Contains(m.message, "must be above 20%") {
+		t.Errorf("expected rejection of 10%%, got %q", m.message)
+	}
+
+	m.handleKey("d")
+	for _, key := range []string{"4", "0", keyEnter} {
+		m.handleKey(key)
+	}
+	if m.mode != modeConfirm || !strings.Contains(m.prompt, "from 20% to 40%") {
+		t.Fatalf("expected confirmation prompt, got %q", m.prompt)
+	}
+	action := m.handleKey("y")
+	if action == nil || action.Type != ActionSetDeployPercentage || action.Percentage != 40 {
+		t.Errorf("unexpected action %+v", action)
+	}
+
+	m.handleKey("p")
+	if action := m.handleKey("n"); action != nil || m.message != "Cancelled." {
+		t.Errorf("expected publish to be cancelled, got %+v", action)
+	}
+
+	m.handleKey(keyCtrlC)
+	if !m.quit {
+		t.Error("expected ctrl+c to quit")
+	}
+}
+
+func TestRender(t *testing.T) {
+	m := newModel([]chromewebstore.ItemName{itemA, itemB})
+	status := publishedStatus("1.0", 50)
+	status.Warned = true
+	status.SubmittedItemRevisionStatus = &chromewebstore.ItemRevisionStatus{
+		State:                chromewebstore.ItemStatePendingReview,
+		DistributionChannels: []chromewebstore.DistributionChannel{{CrxVersion: "1.1"}},
+	}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	m.update(itemA, status, nil, now)
+	m.update(itemB, nil, errors.New("HTTP 429"), now)
+
+	screen := m.render(now, now.Add(30*time.Second))
+	for _, expected := range []string{
+		"next refresh in 30s",
+		reverse + "aaaa  1.0        1.1        PENDING_REVIEW  50%     WARNED  SUCCEEDED  12:00:00" + reset,
+		"bbbb  -          -          -               -               -          never",
+		"bbbb: HTTP 429",
+		help,
+	} {
+		if !strings.Contains(screen, expected) {
+			t.Errorf("expected screen to contain %q:\n%s", expected, screen)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	b := newBackoff(10*time.Second, 35*time.Second)
+	var got []time.Duration
+	for _, failed := range []bool{false, true, true, true, false} {
+		got = append(got, b.next(failed))
+	}
+	expected := []time.Duration{10 * time.Second, 20 * time.Second, 35 * time.Second, 35 * time.Second, 10 * time.Second}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got := parseKeys([]byte("j\x1b[A\x1b[B\x1b\r\x7f\x03"))
+	expected := []string{"j", keyUp, keyDown, keyEsc, keyEnter, keyBackspace, keyCtrlC}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
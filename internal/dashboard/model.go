@@ -0,0 +1,206 @@
+package dashboard
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+// ActionType identifies an operation triggered from the dashboard.
+type ActionType string
+
+const (
+	// ActionRefresh fetches the status of every item immediately.
+	ActionRefresh ActionType = "refresh"
+	// ActionPublish submits the selected item for publishing.
+	ActionPublish ActionType = "publish"
+	// ActionCancelSubmission cancels the pending submission of the selected item.
+	ActionCancelSubmission ActionType = "cancel-submission"
+	// ActionSetDeployPercentage raises the published deploy percentage of
+	// the selected item.
+	ActionSetDeployPercentage ActionType = "set-deploy-percentage"
+)
+
+// Action is an operation confirmed by the user.
+type Action struct {
+	Type ActionType
+	Item chromewebstore.ItemName
+	// Percentage is the new deploy percentage of ActionSetDeployPercentage.
+	Percentage int
+}
+
+// Row is the latest known state of an item.
+type Row struct {
+	Item chromewebstore.ItemName
+	// Status is the last status fetched successfully, or nil.
+	Status *chromewebstore.ItemStatus
+	// Err is the error of the last fetch, or nil.
+	Err error
+	// Updated is when Status was fetched.
+	Updated time.Time
+}
+
+// mode is the input mode of the dashboard.
+type mode int
+
+const (
+	modeNormal mode = iota
+	// modeInput reads a deploy percentage.
+	modeInput
+	// modeConfirm asks for confirmation of the pending action.
+	modeConfirm
+)
+
+// Keys reported by readKeys besides printable characters.
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyEnter     = "enter"
+	keyBackspace = "backspace"
+	keyEsc       = "esc"
+	keyCtrlC     = "ctrl+c"
+)
+
+// model is the state of the dashboard. It is updated by key presses and
+// fetch results and rendered after every change.
+type model struct {
+	rows     []Row
+	selected int
+	mode     mode
+	prompt   string
+	input    string
+	pending  Action
+	message  string
+	quit     bool
+}
+
+// newModel returns a model listing items.
+func newModel(items []chromewebstore.ItemName) *model {
+	m := &model{}
+	for _, item := range items {
+		m.rows = append(m.rows, Row{Item: item})
+	}
+	return m
+}
+
+// handleKey updates the model for a key press and returns the action to
+// perform, if any.
+func (m *model) handleKey(key string) *Action {
+	if key == keyCtrlC {
+		m.quit = true
+		return nil
+	}
+	switch m.mode {
+	case modeInput:
+		return m.handleInput(key)
+	case modeConfirm:
+		m.mode = modeNormal
+		m.prompt = ""
+		if key == "y" || key == "Y" {
+			action := m.pending
+			return &action
+		}
+		m.message = "Cancelled."
+		return nil
+	}
+
+	row := &m.rows[m.selected]
+	switch key {
+	case "q":
+		m.quit = true
+	case keyUp, "k":
+		if m.selected > 0 {
+			m.selected--
+		}
+	case keyDown, "j":
+		if m.selected < len(m.rows)-1 {
+			m.selected++
+		}
+	case "r":
+		return &Action{Type: ActionRefresh}
+	case "p":
+		m.confirm(Action{Type: ActionPublish, Item: row.Item},
+			fmt.Sprintf("Submit %s for publishing?", row.Item.ItemID()))
+	case "c":
+		if row.Status == nil || row.Status.SubmittedItemRevisionStatus == nil {
+			m.message = fmt.Sprintf("%s has no pending submission.", row.Item.ItemID())
+			return nil
+		}
+		m.confirm(Action{Type: ActionCancelSubmission, Item: row.Item},
+			fmt.Sprintf("Cancel the pending submission of %s?", row.Item.ItemID()))
+	case "d":
+		if row.Status == nil {
+			m.message = fmt.Sprintf("The status of %s is not known yet.", row.Item.ItemID())
+			return nil
+		}
+		current, ok := row.Status.PublishedDeployPercentage()
+		if !ok {
+			m.message = fmt.Sprintf("%s has no published revision.", row.Item.ItemID())
+			return nil
+		}
+		m.mode = modeInput
+		m.input = ""
+		m.pending = Action{Type: ActionSetDeployPercentage, Item: row.Item}
+		m.prompt = fmt.Sprintf("New deploy percentage for %s (current %d%%): ", row.Item.ItemID(), current)
+	}
+	return nil
+}
+
+// handleInput handles a key press while a deploy percentage is entered.
+func (m *model) handleInput(key string) *Action {
+	switch key {
+	case keyEsc:
+		m.mode = modeNormal
+		m.prompt = ""
+		m.message = "Cancelled."
+	case keyBackspace:
+		if m.input != "" {
+			m.input = m.input[:len(m.input)-1]
+		}
+	case keyEnter:
+		m.mode = modeNormal
+		m.prompt = ""
+		percentage, err := strconv.Atoi(m.input)
+		if err != nil {
+			m.message = fmt.Sprintf("Invalid deploy percentage %q.", m.input)
+			return nil
+		}
+		current, _ := m.rows[m.selected].Status.PublishedDeployPercentage()
+		if percentage <= current || percentage > 100 {
+			m.message = fmt.Sprintf("The deploy percentage must be above %d%% and at most 100%%.", current)
+			return nil
+		}
+		action := m.pending
+		action.Percentage = percentage
+		m.confirm(action, fmt.Sprintf("Raise the deploy percentage of %s from %d%% to %d%%?", action.Item.ItemID(), current, percentage))
+	default:
+		if len(key) == 1 && key[0] >= '0' && key[0] <= '9' && len(m.input) < 3 {
+			m.input += key
+		}
+	}
+	return nil
+}
+
+// confirm asks for confirmation of action.
+func (m *model) confirm(action Action, question string) {
+	m.mode = modeConfirm
+	m.pending = action
+	m.prompt = question + " [y/N] "
+	m.message = ""
+}
+
+// update records the result of fetching the status of an item.
+func (m *model) update(item chromewebstore.ItemName, status *chromewebstore.ItemStatus, err error, now time.Time) {
+	for i := range m.rows {
+		if m.rows[i].Item != item {
+			continue
+		}
+		m.rows[i].Err = err
+		if err == nil {
+			m.rows[i].Status = status
+			m.rows[i].Updated = now
+		}
+	}
+}
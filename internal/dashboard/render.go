@@ -0,0 +1,124 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+// ANSI escape sequences used for rendering.
+const (
+	clearScreen = "\x1b[H\x1b[2J"
+	reverse     = "\x1b[7m"
+	red         = "\x1b[31m"
+	yellow      = "\x1b[33m"
+	reset       = "\x1b[0m"
+)
+
+// columns are the table columns.
+var columns = []string{"ITEM", "PUBLISHED", "SUBMITTED", "STATE", "DEPLOY", "FLAGS", "UPLOAD", "UPDATED"}
+
+// help lists the key bindings.
+const help = "↑/↓ select  p publish  c cancel submission  d raise deploy %  r refresh  q quit"
+
+// cells returns the table cells of a row.
+func cells(r Row) []string {
+	c := []string{r.Item.ItemID(), "-", "-", "-", "-", "", "-", "never"}
+	if r.Status == nil {
+		return c
+	}
+	s := r.Status
+	if v := revisionVersion(s.PublishedItemRevisionStatus); v != "" {
+		c[1] = v
+	}
+	if v := revisionVersion(s.SubmittedItemRevisionStatus); v != "" {
+		c[2] = v
+	}
+	switch {
+	case s.SubmittedItemRevisionStatus != nil:
+		c[3] = string(s.SubmittedItemRevisionStatus.State)
+	case s.PublishedItemRevisionStatus != nil:
+		c[3] = string(s.PublishedItemRevisionStatus.State)
+	}
+	if p, ok := s.PublishedDeployPercentage(); ok {
+		c[4] = fmt.Sprintf("%d%%", p)
+	}
+	var flags []string
+	if s.Warned {
+		flags = append(flags, "WARNED")
+	}
+	if s.TakenDown {
+		flags = append(flags, "TAKEN DOWN")
+	}
+	c[5] = strings.Join(flags, ",")
+	if s.LastAsyncUploadState != "" {
+		c[6] = string(s.LastAsyncUploadState)
+	}
+	c[7] = r.Updated.Format(time.TimeOnly)
+	return c
+}
+
+// revisionVersion returns the CRX version of a revision, or "".
+func revisionVersion(rev *chromewebstore.ItemRevisionStatus) string {
+	if rev == nil || len(rev.DistributionChannels) == 0 {
+		return ""
+	}
+	return rev.DistributionChannels[0].CrxVersion
+}
+
+// render returns the screen for the model. next is when the statuses are
+// refreshed next. Lines end with CRLF as the terminal is in raw mode.
+func (m *model) render(now, next time.Time) string {
+	table := [][]string{columns}
+	for _, r := range m.rows {
+		table = append(table, cells(r))
+	}
+	widths := make([]int, len(columns))
+	for _, row := range table {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len([]rune(cell)))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(clearScreen)
+	fmt.Fprintf(&b, "cws dashboard — %d items — next refresh in %s\r\n\r\n", len(m.rows), next.Sub(now).Round(time.Second))
+	for i, row := range table {
+		var line strings.Builder
+		for j, cell := range row {
+			if j > 0 {
+				line.WriteString("  ")
+			}
+			line.WriteString(cell + strings.Repeat(" ", widths[j]-len([]rune(cell))))
+		}
+		text := strings.TrimRight(line.String(), " ")
+		switch {
+		case i == 0:
+		case i-1 == m.selected:
+			text = reverse + text + reset
+		case m.rows[i-1].Status != nil && m.rows[i-1].Status.TakenDown:
+			text = red + text + reset
+		case m.rows[i-1].Status != nil && m.rows[i-1].Status.Warned:
+			text = yellow + text + reset
+		}
+		b.WriteString(text + "\r\n")
+	}
+
+	b.WriteString("\r\n")
+	for _, r := range m.rows {
+		if r.Err != nil {
+			fmt.Fprintf(&b, "%s%s: %v%s\r\n", red, r.Item.ItemID(), r.Err, reset)
+		}
+	}
+	if m.message != "" {
+		b.WriteString(m.message + "\r\n")
+	}
+	if m.prompt != "" {
+		b.WriteString(m.prompt + m.input)
+	} else {
+		b.WriteString(help)
+	}
+	return b.String()
+}
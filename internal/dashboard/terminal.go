@@ -0,0 +1,47 @@
+package dashboard
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Terminal control sequences.
+const (
+	enterAltScreen = "\x1b[?1049h\x1b[?25l"
+	exitAltScreen  = "\x1b[?25h\x1b[?1049l"
+)
+
+// IsTerminal reports whether f is a terminal.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// OpenTerminal switches the terminal connected to in to raw mode and out
+// to the alternate screen. The returned function restores both. Raw mode
+// is set with stty, so a Unix terminal is required.
+func OpenTerminal(in *os.File, out io.Writer) (restore func(), err error) {
+	saved, err := stty(in, "-g")
+	if err != nil {
+		return nil, fmt.Errorf("dashboard: failed to read terminal settings: %w", err)
+	}
+	if _, err := stty(in, "raw", "-echo"); err != nil {
+		return nil, fmt.Errorf("dashboard: failed to set raw mode: %w", err)
+	}
+	io.WriteString(out, enterAltScreen)
+	return func() {
+		io.WriteString(out, exitAltScreen)
+		stty(in, strings.TrimSpace(saved))
+	}, nil
+}
+
+// stty runs stty with args on the terminal in.
+func stty(in *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = in
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -0,0 +1,44 @@
+// Package filelock serializes changes to a file between processes with a
+// lock file created next to it.
+package filelock
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+const (
+	// Timeout bounds the wait for a lock file held by another process.
+	Timeout = 10 * time.Second
+	// Stale is the age after which a lock file is considered left behind
+	// by a crashed process and removed.
+	Stale = time.Minute
+)
+
+// Lock creates the lock file at path, waiting while another process holds
+// it, and returns a function removing it. Errors are not prefixed, so that
+// callers can wrap them with their own package name.
+func Lock(path string) (unlock func(), err error) {
+	deadline := time.Now().Add(Timeout)
+	for {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > Stale {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock file %s", path)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,145 @@
+// Package ghactions writes step outputs, job summaries and workflow
+// command annotations when running inside GitHub Actions.
+package ghactions
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Enabled reports whether the process runs inside GitHub Actions.
+func Enabled() bool {
+	return os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+// SetOutputs appends step outputs to the file named by $GITHUB_OUTPUT.
+// Outputs are written in order; empty values are skipped.
+// It does nothing if $GITHUB_OUTPUT is unset.
+func SetOutputs(outputs []Output) error {
+	var b strings.Builder
+	for _, o := range outputs {
+		if o.Value == "" {
+			continue
+		}
+		if strings.ContainsAny(o.Value, "\r\n") {
+			delim := delimiter()
+			fmt.Fprintf(&b, "%s<<%s\n%s\n%s\n", o.Name, delim, o.Value, delim)
+		} else {
+			fmt.Fprintf(&b, "%s=%s\n", o.Name, o.Value)
+		}
+	}
+	return appendEnvFile("GITHUB_OUTPUT", b.String())
+}
+
+// Output is a named step output.
+type Output struct {
+	Name  string
+	Value string
+}
+
+// AppendSummary appends Markdown to the job summary file named by
+// $GITHUB_STEP_SUMMARY. It does nothing if $GITHUB_STEP_SUMMARY is unset.
+func AppendSummary(markdown string) error {
+	if !strings.HasSuffix(markdown, "\n") {
+		markdown += "\n"
+	}
+	return appendEnvFile("GITHUB_STEP_SUMMARY", markdown)
+}
+
+// appendEnvFile appends s to the file named by the environment variable.
+func appendEnvFile(env, s string) error {
+	path := os.Getenv(env)
+	if path == "" || s == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("ghactions: %w", err)
+	}
+	if _, err := f.WriteString(s); err != nil {
+		f.Close()
+		return fmt.Errorf("ghactions: %w", err)
+	}
+	return f.Close()
+}
+
+// delimiter returns a random heredoc delimiter for multi-line values.
+func delimiter() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return "ghadelimiter_" + hex.EncodeToString(b)
+}
+
+// Level is the severity of an annotation.
+type Level string
+
+const (
+	// LevelError creates an error annotation.
+	LevelError Level = "error"
+	// LevelWarning creates a warning annotation.
+	LevelWarning Level = "warning"
+	// LevelNotice creates a notice annotation.
+	LevelNotice Level = "notice"
+)
+
+// Annotation is a workflow command that annotates the run.
+type Annotation struct {
+	// Level is the severity.
+	Level Level
+	// Title is an optional title shown above the message.
+	Title string
+	// File is an optional path the annotation refers to.
+	File string
+	// Message is the annotation text.
+	Message string
+}
+
+// Write writes the annotation as a workflow command to w.
+func (a Annotation) Write(w io.Writer) error {
+	var props []string
+	if a.Title != "" {
+		props = append(props, "title="+escapeProperty(a.Title))
+	}
+	if a.File != "" {
+		props = append(props, "file="+escapeProperty(a.File))
+	}
+
+	cmd := string(a.Level)
+	if len(props) > 0 {
+		cmd += " " + strings.Join(props, ",")
+	}
+	_, err := fmt.Fprintf(w, "::%s::%s\n", cmd, escapeData(a.Message))
+	return err
+}
+
+// escapeData escapes a workflow command message.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a workflow command property value.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// Table renders a two-column Markdown table of the non-empty rows.
+func Table(header [2]string, rows [][2]string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "| %s | %s |\n| --- | --- |\n", header[0], header[1])
+	for _, row := range rows {
+		if row[1] == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "| %s | %s |\n", escapeCell(row[0]), escapeCell(row[1]))
+	}
+	return b.String()
+}
+
+// escapeCell escapes characters that would break a Markdown table cell.
+func escapeCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
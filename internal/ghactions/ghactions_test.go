@@ -0,0 +1,80 @@
+package ghactions
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSetOutputs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output")
+	t.Setenv("GITHUB_OUTPUT", path)
+
+	err := SetOutputs([]Output{
+		{"item-id", "abc"},
+		{"crx-version", ""},
+		{"notes", "line 1\nline 2"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pattern := regexp.MustCompile(`^item-id=abc\nnotes<<(ghadelimiter_[0-9a-f]+)\nline 1\nline 2\n(ghadelimiter_[0-9a-f]+)\n$`)
+	m := pattern.FindStringSubmatch(string(data))
+	if m == nil || m[1] != m[2] {
+		t.Errorf("unexpected output file:\n%s", data)
+	}
+}
+
+func TestSetOutputsWithoutFile(t *testing.T) {
+	t.Setenv("GITHUB_OUTPUT", "")
+	if err := SetOutputs([]Output{{"item-id", "abc"}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestAppendSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary")
+	t.Setenv("GITHUB_STEP_SUMMARY", path)
+
+	if err := AppendSummary("## First"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := AppendSummary(Table([2]string{"Field", "Value"}, [][2]string{{"Version", "1.0"}, {"State", ""}, {"Note", "a|b"}})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "## First\n| Field | Value |\n| --- | --- |\n| Version | 1.0 |\n| Note | a\\|b |\n"
+	if string(data) != expected {
+		t.Errorf("expected %q, got %q", expected, data)
+	}
+}
+
+func TestAnnotation(t *testing.T) {
+	var b strings.Builder
+	a := Annotation{Level: LevelError, Title: "icons: missing", File: "dist/ext.zip", Message: "50% done\nfailed"}
+	if err := a.Write(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "::error title=icons%3A missing,file=dist/ext.zip::50%25 done%0Afailed\n"
+	if b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+
+	b.Reset()
+	Annotation{Level: LevelWarning, Message: "careful"}.Write(&b)
+	if b.String() != "::warning::careful\n" {
+		t.Errorf("unexpected annotation %q", b.String())
+	}
+}
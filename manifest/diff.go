@@ -0,0 +1,210 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// Diff describes the differences between two packages.
+type Diff struct {
+	// Fields contains the top-level manifest fields whose values differ,
+	// including fields not represented in Manifest.
+	Fields []FieldChange `json:"fields,omitempty"`
+	// AddedPermissions are API permissions requested only by the new package.
+	AddedPermissions []string `json:"addedPermissions,omitempty"`
+	// RemovedPermissions are API permissions requested only by the old package.
+	RemovedPermissions []string `json:"removedPermissions,omitempty"`
+	// AddedHostPermissions are host patterns granted only to the new
+	// package, through host_permissions or content_scripts matches.
+	AddedHostPermissions []string `json:"addedHostPermissions,omitempty"`
+	// RemovedHostPermissions are host patterns granted only to the old
+	// package, through host_permissions or content_scripts matches.
+	RemovedHostPermissions []string `json:"removedHostPermissions,omitempty"`
+	// AddedFiles are files present only in the new package.
+	AddedFiles []File `json:"addedFiles,omitempty"`
+	// RemovedFiles are files present only in the old package.
+	RemovedFiles []File `json:"removedFiles,omitempty"`
+	// ChangedFiles are files present in both packages with different sizes.
+	ChangedFiles []FileChange `json:"changedFiles,omitempty"`
+	// OldSize is the size of the old package in bytes.
+	OldSize int64 `json:"oldSize"`
+	// NewSize is the size of the new package in bytes.
+	NewSize int64 `json:"newSize"`
+}
+
+// FieldChange is a changed manifest field. Values are JSON-encoded and empty
+// when the field is absent.
+type FieldChange struct {
+	// Field is the manifest field name.
+	Field string `json:"field"`
+	// Old is the value in the old package.
+	Old string `json:"old,omitempty"`
+	// New is the value in the new package.
+	New string `json:"new,omitempty"`
+}
+
+// FileChange is a file whose size changed.
+type FileChange struct {
+	// Name is the path of the file within the package.
+	Name string `json:"name"`
+	// OldSize is the size in the old package.
+	OldSize int64 `json:"oldSize"`
+	// NewSize is the size in the new package.
+	NewSize int64 `json:"newSize"`
+}
+
+// SizeDelta returns the change in package size in bytes.
+func (d *Diff) SizeDelta() int64 {
+	return d.NewSize - d.OldSize
+}
+
+// HasNewPermissions reports whether the new package requests permissions
+// or host permissions that the old package did not.
+func (d *Diff) HasNewPermissions() bool {
+	return len(d.AddedPermissions) > 0 || len(d.AddedHostPermissions) > 0
+}
+
+// Compare computes the differences between the old and new packages.
+// Both packages must have a loaded manifest.
+func Compare(oldPkg, newPkg *Package) (*Diff, error) {
+	for _, p := range []*Package{oldPkg, newPkg} {
+		if p.ManifestErr != nil {
+			return nil, fmt.Errorf("manifest: %s: %w", p.Path, p.ManifestErr)
+		}
+	}
+
+	d := &Diff{OldSize: oldPkg.Size, NewSize: newPkg.Size}
+
+	var err error
+	d.Fields, err = compareFields(oldPkg, newPkg)
+	if err != nil {
+		return nil, err
+	}
+
+	d.AddedPermissions, d.RemovedPermissions = compareSets(oldPkg.Manifest.Permissions, newPkg.Manifest.Permissions)
+	d.AddedHostPermissions, d.RemovedHostPermissions = compareSets(hostPatterns(oldPkg.Manifest), hostPatterns(newPkg.Manifest))
+
+	oldFiles, err := oldPkg.Files()
+	if err != nil {
+		return nil, err
+	}
+	newFiles, err := newPkg.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	oldSizes := make(map[string]int64, len(oldFiles))
+	for _, f := range oldFiles {
+		oldSizes[f.Name] = f.Size
+	}
+	newSizes := make(map[string]int64, len(newFiles))
+	for _, f := range newFiles {
+		newSizes[f.Name] = f.Size
+		oldSize, ok := oldSizes[f.Name]
+		switch {
+		case !ok:
+			d.AddedFiles = append(d.AddedFiles, f)
+		case oldSize != f.Size:
+			d.ChangedFiles = append(d.ChangedFiles, FileChange{Name: f.Name, OldSize: oldSize, NewSize: f.Size})
+		}
+	}
+	for _, f := range oldFiles {
+		if _, ok := newSizes[f.Name]; !ok {
+			d.RemovedFiles = append(d.RemovedFiles, f)
+		}
+	}
+
+	return d, nil
+}
+
+// compareFields returns the top-level manifest fields that differ.
+func compareFields(oldPkg, newPkg *Package) ([]FieldChange, error) {
+	oldFields, err := fieldMap(oldPkg)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := fieldMap(newPkg)
+	if err != nil {
+		return nil, err
+	}
+
+	names := slices.Collect(maps.Keys(oldFields))
+	for name := range newFields {
+		if _, ok := oldFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var changes []FieldChange
+	for _, name := range names {
+		oldValue, newValue := oldFields[name], newFields[name]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: name, Old: encodeField(oldValue), New: encodeField(newValue)})
+	}
+	return changes, nil
+}
+
+// fieldMap returns the top-level fields of the manifest of p. They are
+// read from the raw manifest.json, so that fields Manifest does not know
+// about are compared too; localized fields take their resolved values.
+func fieldMap(p *Package) (map[string]any, error) {
+	data, err := fs.ReadFile(p.FS, FileName)
+	if err != nil {
+		return nil, fmt.Errorf("manifest: %s: failed to read %s: %w", p.Path, FileName, err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(Clean(data), &fields); err != nil {
+		return nil, fmt.Errorf("manifest: %s: failed to parse %s: %w", p.Path, FileName, err)
+	}
+	for name, value := range map[string]string{
+		"name":        p.Manifest.Name,
+		"short_name":  p.Manifest.ShortName,
+		"description": p.Manifest.Description,
+	} {
+		if _, ok := fields[name]; ok {
+			fields[name] = value
+		}
+	}
+	return fields, nil
+}
+
+// encodeField returns the compact JSON encoding of v, or "" if v is nil.
+func encodeField(v any) string {
+	if v == nil {
+		return ""
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// hostPatterns returns the match patterns m is granted host access to:
+// its host permissions and the pages its content scripts are injected into.
+func hostPatterns(m *Manifest) []string {
+	patterns := slices.Clone(m.HostPermissions)
+	for _, cs := range m.ContentScripts {
+		patterns = append(patterns, cs.Matches...)
+	}
+	return patterns
+}
+
+// compareSets returns the values only in b (added) and only in a (removed).
+func compareSets(a, b []string) (added, removed []string) {
+	for _, v := range b {
+		if !slices.Contains(a, v) && !slices.Contains(added, v) {
+			added = append(added, v)
+		}
+	}
+	for _, v := range a {
+		if !slices.Contains(b, v) && !slices.Contains(removed, v) {
+			removed = append(removed, v)
+		}
+	}
+	return added, removed
+}
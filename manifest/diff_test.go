@@ -0,0 +1,131 @@
+package manifest
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	oldPkg, err := OpenBytes(newTestZip(t, map[string]string{
+		"manifest.json": `{"manifest_version": 3, "name": "x", "version": "1.0", "permissions": ["storage", "tabs"]}`,
+		"app.js":        "old",
+		"legacy.js":     "legacy",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	newPkg, err := OpenBytes(newTestZip(t, map[string]string{
+		"manifest.json": `{"manifest_version": 3, "name": "x", "version": "1.1", "permissions": ["storage", "cookies"], "host_permissions": ["<all_urls>"]}`,
+		"app.js":        "new code",
+		"new.js":        "",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d, err := Compare(oldPkg, newPkg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !d.HasNewPermissions() {
+		t.Error("expected new permissions")
+	}
+	if len(d.AddedPermissions) != 1 || d.AddedPermissions[0] != "cookies" {
+		t.Errorf("unexpected added permissions %v", d.AddedPermissions)
+	}
+	if len(d.RemovedPermissions) != 1 || d.RemovedPermissions[0] != "tabs" {
+		t.Errorf("unexpected removed permissions %v", d.RemovedPermissions)
+	}
+	if len(d.AddedHostPermissions) != 1 {
+		t.Errorf("unexpected added host permissions %v", d.AddedHostPermissions)
+	}
+
+	fields := map[string]FieldChange{}
+	for _, f := range d.Fields {
+		fields[f.Field] = f
+	}
+	if fields["version"].Old != `"1.0"` || fields["version"].New != `"1.1"` {
+		t.Errorf("unexpected version change %+v", fields["version"])
+	}
+	if _, ok := fields["name"]; ok {
+		t.Error("expected unchanged name not to be reported")
+	}
+	if fields["host_permissions"].Old != "" {
+		t.Errorf("expected absent old host_permissions, got %q", fields["host_permissions"].Old)
+	}
+
+	if len(d.AddedFiles) != 1 || d.AddedFiles[0].Name != "new.js" {
+		t.Errorf("unexpected added files %v", d.AddedFiles)
+	}
+	if len(d.RemovedFiles) != 1 || d.RemovedFiles[0].Name != "legacy.js" {
+		t.Errorf("unexpected removed files %v", d.RemovedFiles)
+	}
+	if len(d.ChangedFiles) != 2 {
+		t.Errorf("expected app.js and manifest.json to change, got %v", d.ChangedFiles)
+	}
+	if d.SizeDelta() != newPkg.Size-oldPkg.Size {
+		t.Errorf("unexpected size delta %d", d.SizeDelta())
+	}
+}
+
+func TestCompareContentScriptMatches(t *testing.T) {
+	oldPkg, err := OpenBytes(newTestZip(t, map[string]string{
+		"manifest.json": `{"manifest_version": 3, "name": "x", "version": "1.0", "host_permissions": ["https://a.example/*"], "content_scripts": [{"matches": ["https://b.example/*"], "js": ["cs.js"]}]}`,
+		"cs.js":         "",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	newPkg, err := OpenBytes(newTestZip(t, map[string]string{
+		"manifest.json": `{"manifest_version": 3, "name": "x", "version": "1.1", "content_scripts": [{"matches": ["https://a.example/*", "https://b.example/*"], "js": ["cs.js"]}, {"matches": ["<all_urls>"], "js": ["cs.js"]}]}`,
+		"cs.js":         "",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d, err := Compare(oldPkg, newPkg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !d.HasNewPermissions() {
+		t.Error("expected new content script matches to count as new permissions")
+	}
+	if len(d.AddedHostPermissions) != 1 || d.AddedHostPermissions[0] != "<all_urls>" {
+		t.Errorf("unexpected added host permissions %v", d.AddedHostPermissions)
+	}
+	if len(d.RemovedHostPermissions) != 0 {
+		t.Errorf("expected a pattern moved to content_scripts not to be removed, got %v", d.RemovedHostPermissions)
+	}
+}
+
+func TestCompareUnknownFields(t *testing.T) {
+	oldPkg, err := OpenBytes(newTestZip(t, map[string]string{
+		"manifest.json":             `{"manifest_version": 3, "name": "__MSG_name__", "version": "1.0", "default_locale": "en", "commands": {"run": {}}}`,
+		"_locales/en/messages.json": `{"name": {"message": "Old"}}`,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	newPkg, err := OpenBytes(newTestZip(t, map[string]string{
+		"manifest.json":             `{"manifest_version": 3, "name": "__MSG_name__", "version": "1.0", "default_locale": "en", "commands": {"run": {}}, "optional_host_permissions": ["https://*/*"]}`,
+		"_locales/en/messages.json": `{"name": {"message": "New"}}`,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d, err := Compare(oldPkg, newPkg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(d.Fields) != 2 {
+		t.Fatalf("expected 2 changed fields, got %+v", d.Fields)
+	}
+	if f := d.Fields[0]; f.Field != "name" || f.Old != `"Old"` || f.New != `"New"` {
+		t.Errorf("expected the localized name to change, got %+v", f)
+	}
+	if f := d.Fields[1]; f.Field != "optional_host_permissions" || f.Old != "" || f.New != `["https://*/*"]` {
+		t.Errorf("expected the unknown field to be reported, got %+v", f)
+	}
+}
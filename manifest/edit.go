@@ -0,0 +1,249 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// member is the location of a member of the top-level JSON object.
+type member struct {
+	// keyStart is the offset of the opening quote of the key.
+	keyStart int
+	// valueStart and valueEnd delimit the value.
+	valueStart, valueEnd int
+	// prevComma is the offset of the comma preceding the member, or -1.
+	prevComma int
+	// nextComma is the offset of the comma following the member, or -1.
+	nextComma int
+}
+
+// SetString sets the top-level string member key of manifest.json content to
+// value, leaving the rest of the document, including formatting and comments,
+// untouched. The member must already exist.
+func SetString(data []byte, key, value string) ([]byte, error) {
+	m, ok, err := findMember(data, key)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("manifest: %q not found", key)
+	}
+
+	quoted, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("manifest: failed to encode %q: %w", key, err)
+	}
+
+	out := make([]byte, 0, len(data)+len(quoted))
+	out = append(out, data[:m.valueStart]...)
+	out = append(out, quoted...)
+	out = append(out, data[m.valueEnd:]...)
+	return out, nil
+}
+
+// RemoveMember removes the top-level member key from manifest.json content,
+// leaving the rest of the document untouched. It is a no-op if the member
+// does not exist.
+func RemoveMember(data []byte, key string) ([]byte, error) {
+	m, ok, err := findMember(data, key)
+	if err != nil || !ok {
+		return data, err
+	}
+
+	var start, end int
+	switch {
+	case m.nextComma >= 0:
+		start, end = m.keyStart, m.nextComma+1
+
+		lineStart := start
+		for lineStart > 0 && (data[lineStart-1] == ' ' || data[lineStart-1] == '\t') {
+			lineStart--
+		}
+		lineEnd := end
+		for lineEnd < len(data) && (data[lineEnd] == ' ' || data[lineEnd] == '\t' || data[lineEnd] == '\r') {
+			lineEnd++
+		}
+
+		if (lineStart == 0 || data[lineStart-1] == '\n') && lineEnd < len(data) && data[lineEnd] == '\n' {
+			// The member occupies whole lines; remove them.
+			start, end = lineStart, lineEnd+1
+		} else {
+			for end < len(data) && (data[end] == ' ' || data[end] == '\t') {
+				end++
+			}
+		}
+	case m.prevComma >= 0:
+		start, end = m.prevComma, m.valueEnd
+	default:
+		start, end = m.keyStart, m.valueEnd
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:start]...)
+	out = append(out, data[end:]...)
+	return out, nil
+}
+
+// findMember locates the top-level member key in JSON data that may
+// contain comments and a leading BOM.
+func findMember(data []byte, key string) (member, bool, error) {
+	s := &scanner{data: data}
+	if bytes.HasPrefix(data, []byte("\xef\xbb\xbf")) {
+		s.pos = 3
+	}
+
+	s.skipSpace()
+	if !s.consume('{') {
+		return member{}, false, s.errorf("expected '{'")
+	}
+
+	prevComma := -1
+	for {
+		s.skipSpace()
+		if s.consume('}') {
+			return member{}, false, nil
+		}
+
+		keyStart := s.pos
+		if !s.scanString() {
+			return member{}, false, s.errorf("expected object key")
+		}
+		var name string
+		if err := json.Unmarshal(data[keyStart:s.pos], &name); err != nil {
+			return member{}, false, s.errorf("invalid object key")
+		}
+
+		s.skipSpace()
+		if !s.consume(':') {
+			return member{}, false, s.errorf("expected ':'")
+		}
+		s.skipSpace()
+		valueStart := s.pos
+		if !s.scanValue() {
+			return member{}, false, s.errorf("invalid value")
+		}
+		valueEnd := s.pos
+
+		s.skipSpace()
+		nextComma := -1
+		if s.pos < len(data) && data[s.pos] == ',' {
+			nextComma = s.pos
+		}
+
+		if name == key {
+			return member{keyStart, valueStart, valueEnd, prevComma, nextComma}, true, nil
+		}
+
+		if nextComma < 0 {
+			if !s.consume('}') {
+				return member{}, false, s.errorf("expected ',' or '}'")
+			}
+			return member{}, false, nil
+		}
+		s.pos++
+		prevComma = nextComma
+	}
+}
+
+// scanner is a minimal scanner for JSON with comments.
+type scanner struct {
+	data []byte
+	pos  int
+}
+
+func (s *scanner) errorf(msg string) error {
+	return fmt.Errorf("manifest: failed to parse %s at offset %d: %s", FileName, s.pos, msg)
+}
+
+func (s *scanner) consume(c byte) bool {
+	if s.pos < len(s.data) && s.data[s.pos] == c {
+		s.pos++
+		return true
+	}
+	return false
+}
+
+// skipSpace skips whitespace and comments.
+func (s *scanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch c := s.data[s.pos]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			s.pos++
+		case bytes.HasPrefix(s.data[s.pos:], []byte("//")):
+			for s.pos < len(s.data) && s.data[s.pos] != '\n' {
+				s.pos++
+			}
+		case bytes.HasPrefix(s.data[s.pos:], []byte("/*")):
+			end := bytes.Index(s.data[s.pos+2:], []byte("*/"))
+			if end < 0 {
+				s.pos = len(s.data)
+				return
+			}
+			s.pos += 2 + end + 2
+		default:
+			return
+		}
+	}
+}
+
+// scanString scans a string literal starting at the current position.
+func (s *scanner) scanString() bool {
+	if !s.consume('"') {
+		return false
+	}
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case '\\':
+			s.pos += 2
+		case '"':
+			s.pos++
+			return true
+		default:
+			s.pos++
+		}
+	}
+	return false
+}
+
+// scanValue scans any JSON value starting at the current position.
+func (s *scanner) scanValue() bool {
+	if s.pos >= len(s.data) {
+		return false
+	}
+
+	switch s.data[s.pos] {
+	case '"':
+		return s.scanString()
+	case '{', '[':
+		depth := 0
+		for s.pos < len(s.data) {
+			s.skipSpace()
+			if s.pos >= len(s.data) {
+				return false
+			}
+			switch s.data[s.pos] {
+			case '"':
+				if !s.scanString() {
+					return false
+				}
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+			s.pos++
+			if depth == 0 {
+				return true
+			}
+		}
+		return false
+	default:
+		start := s.pos
+		for s.pos < len(s.data) && !bytes.ContainsRune([]byte(",}] \t\r\n/"), rune(s.data[s.pos])) {
+			s.pos++
+		}
+		return s.pos > start
+	}
+}
@@ -0,0 +1,76 @@
+package manifest
+
+import "testing"
+
+func TestSetString(t *testing.T) {
+	data := []byte(`{
+  // "version": "0.0.0" in a comment is ignored
+  "name": "x",
+  "content_scripts": [{"version": "nested"}],
+  "version"  :  "1.2.3",
+  "key": "abc"
+}
+`)
+
+	out, err := SetString(data, "version", "1.2.4")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `{
+  // "version": "0.0.0" in a comment is ignored
+  "name": "x",
+  "content_scripts": [{"version": "nested"}],
+  "version"  :  "1.2.4",
+  "key": "abc"
+}
+`
+	if string(out) != want {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	if _, err := SetString(data, "missing", "x"); err == nil {
+		t.Error("expected error for missing member")
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			"middle line",
+			"{\n  \"name\": \"x\",\n  \"key\": \"abc\",\n  \"version\": \"1\"\n}\n",
+			"{\n  \"name\": \"x\",\n  \"version\": \"1\"\n}\n",
+		},
+		{
+			"last line",
+			"{\n  \"name\": \"x\",\n  \"key\": \"abc\"\n}\n",
+			"{\n  \"name\": \"x\"\n}\n",
+		},
+		{
+			"compact",
+			`{"key":"abc", "name":"x"}`,
+			`{"name":"x"}`,
+		},
+		{
+			"missing",
+			`{"name":"x"}`,
+			`{"name":"x"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := RemoveMember([]byte(tt.in), "key")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(out) != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, out)
+			}
+		})
+	}
+}
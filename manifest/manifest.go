@@ -0,0 +1,210 @@
+// Package manifest reads and inspects Chrome extension packages and their manifest.json.
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// FileName is the name of the manifest file at the package root.
+const FileName = "manifest.json"
+
+// ErrNotFound is returned when a package has no manifest.json at its root.
+var ErrNotFound = errors.New("manifest: manifest.json not found at package root")
+
+// Manifest represents the fields of manifest.json used by this project.
+type Manifest struct {
+	// ManifestVersion is the manifest file format version (2 or 3).
+	ManifestVersion int `json:"manifest_version"`
+	// Name is the extension name, localized with the default locale.
+	Name string `json:"name"`
+	// ShortName is the short extension name, localized with the default locale.
+	ShortName string `json:"short_name,omitempty"`
+	// Description is the extension description, localized with the default locale.
+	Description string `json:"description,omitempty"`
+	// Version is the extension version (1-4 dot-separated integers).
+	Version string `json:"version"`
+	// VersionName is the descriptive version string shown to users.
+	VersionName string `json:"version_name,omitempty"`
+	// DefaultLocale is the locale used to resolve __MSG_ placeholders.
+	DefaultLocale string `json:"default_locale,omitempty"`
+	// MinimumChromeVersion is the minimum Chrome version required.
+	MinimumChromeVersion string `json:"minimum_chrome_version,omitempty"`
+	// Permissions are the API permissions requested by the extension.
+	Permissions []string `json:"permissions,omitempty"`
+	// OptionalPermissions are the permissions requested at runtime.
+	OptionalPermissions []string `json:"optional_permissions,omitempty"`
+	// HostPermissions are the host match patterns requested by the extension.
+	HostPermissions []string `json:"host_permissions,omitempty"`
+	// ContentScripts are the statically declared content scripts.
+	ContentScripts []ContentScript `json:"content_scripts,omitempty"`
+	// Background is the background page, scripts or service worker.
+	Background *Background `json:"background,omitempty"`
+	// Icons maps icon sizes to file paths within the package.
+	Icons map[string]string `json:"icons,omitempty"`
+	// Key is the base64-encoded public key used during development.
+	Key string `json:"key,omitempty"`
+}
+
+// ContentScript represents a content_scripts entry.
+type ContentScript struct {
+	// Matches are the pages the script is injected into.
+	Matches []string `json:"matches,omitempty"`
+	// ExcludeMatches are the pages excluded from injection.
+	ExcludeMatches []string `json:"exclude_matches,omitempty"`
+	// JS are the JavaScript files to inject.
+	JS []string `json:"js,omitempty"`
+	// CSS are the CSS files to inject.
+	CSS []string `json:"css,omitempty"`
+	// RunAt controls when the scripts are injected.
+	RunAt string `json:"run_at,omitempty"`
+	// AllFrames controls whether the scripts are injected into all frames.
+	AllFrames bool `json:"all_frames,omitempty"`
+}
+
+// Background represents the background entry.
+type Background struct {
+	// ServiceWorker is the service worker script (Manifest V3).
+	ServiceWorker string `json:"service_worker,omitempty"`
+	// Type is the service worker type ("module" or empty).
+	Type string `json:"type,omitempty"`
+	// Scripts are the background scripts (Manifest V2).
+	Scripts []string `json:"scripts,omitempty"`
+	// Page is the background page (Manifest V2).
+	Page string `json:"page,omitempty"`
+	// Persistent reports whether the background page is persistent (Manifest V2).
+	Persistent *bool `json:"persistent,omitempty"`
+}
+
+// Parse parses manifest.json content. A leading UTF-8 BOM and
+// JavaScript-style comments are accepted, as Chrome does.
+// __MSG_ placeholders are left unresolved.
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := json.Unmarshal(Clean(data), &m); err != nil {
+		return nil, fmt.Errorf("manifest: failed to parse %s: %w", FileName, err)
+	}
+	return &m, nil
+}
+
+// Load reads manifest.json from the root of fsys and resolves __MSG_
+// placeholders in the name, short name and description using the
+// default locale's messages.
+func Load(fsys fs.FS) (*Manifest, error) {
+	data, err := fs.ReadFile(fsys, FileName)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("manifest: failed to read %s: %w", FileName, err)
+	}
+
+	m, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.DefaultLocale != "" {
+		messages, err := LoadMessages(fsys, m.DefaultLocale)
+		if err != nil {
+			return nil, err
+		}
+		m.Name = messages.Localize(m.Name)
+		m.ShortName = messages.Localize(m.ShortName)
+		m.Description = messages.Localize(m.Description)
+	}
+
+	return m, nil
+}
+
+// Messages maps lower-cased message names to their localized text.
+type Messages map[string]string
+
+// LoadMessages reads _locales/<locale>/messages.json from fsys.
+func LoadMessages(fsys fs.FS, locale string) (Messages, error) {
+	name := path.Join("_locales", locale, "messages.json")
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("manifest: failed to read %s: %w", name, err)
+	}
+
+	var raw map[string]struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(Clean(data), &raw); err != nil {
+		return nil, fmt.Errorf("manifest: failed to parse %s: %w", name, err)
+	}
+
+	messages := make(Messages, len(raw))
+	for k, v := range raw {
+		messages[strings.ToLower(k)] = v.Message
+	}
+	return messages, nil
+}
+
+// Localize resolves s if it is a __MSG_name__ placeholder.
+// Unknown placeholders are returned unchanged.
+func (m Messages) Localize(s string) string {
+	if !strings.HasPrefix(s, "__MSG_") || !strings.HasSuffix(s, "__") || len(s) <= len("__MSG_")+len("__") {
+		return s
+	}
+	key := strings.ToLower(s[len("__MSG_") : len(s)-len("__")])
+	if msg, ok := m[key]; ok {
+		return msg
+	}
+	return s
+}
+
+// Clean removes a leading UTF-8 BOM and JavaScript-style comments from
+// JSON data, leaving string literals untouched.
+func Clean(data []byte) []byte {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	out := make([]byte, 0, len(data))
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		if c == '/' && i+1 < len(data) {
+			switch data[i+1] {
+			case '/':
+				for i < len(data) && data[i] != '\n' {
+					i++
+				}
+				if i < len(data) {
+					out = append(out, '\n')
+				}
+				continue
+			case '*':
+				end := bytes.Index(data[i+2:], []byte("*/"))
+				if end < 0 {
+					return out
+				}
+				i += 2 + end + 1
+				out = append(out, ' ')
+				continue
+			}
+		}
+
+		if c == '"' {
+			inString = true
+		}
+		out = append(out, c)
+	}
+	return out
+}
@@ -0,0 +1,184 @@
+package manifest
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testManifest = "\xef\xbb\xbf" + `{
+  // Comments are allowed in manifest.json.
+  "manifest_version": 3,
+  "name": "__MSG_appName__",
+  "version": "1.2.3",
+  "version_name": "1.2.3 beta",
+  "default_locale": "en",
+  /* block comment */
+  "description": "Visit https://example.com // not a comment",
+  "permissions": ["storage", "tabs"],
+  "host_permissions": ["https://*.example.com/*"],
+  "content_scripts": [{"matches": ["<all_urls>"], "js": ["content.js"]}],
+  "background": {"service_worker": "sw.js"},
+  "icons": {"128": "icon128.png"},
+  "minimum_chrome_version": "110"
+}`
+
+const testMessages = `{"appName": {"message": "My Extension"}}`
+
+func newTestZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("failed to create zip entry: %v", err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func checkManifest(t *testing.T, m *Manifest) {
+	t.Helper()
+
+	if m.Name != "My Extension" {
+		t.Errorf("expected localized name 'My Extension', got %q", m.Name)
+	}
+	if m.Version != "1.2.3" {
+		t.Errorf("expected version 1.2.3, got %s", m.Version)
+	}
+	if m.ManifestVersion != 3 {
+		t.Errorf("expected manifest version 3, got %d", m.ManifestVersion)
+	}
+	if m.Description != "Visit https://example.com // not a comment" {
+		t.Errorf("unexpected description %q", m.Description)
+	}
+	if len(m.Permissions) != 2 || len(m.HostPermissions) != 1 {
+		t.Errorf("unexpected permissions %v %v", m.Permissions, m.HostPermissions)
+	}
+	if m.Background == nil || m.Background.ServiceWorker != "sw.js" {
+		t.Errorf("unexpected background %+v", m.Background)
+	}
+	if m.Icons["128"] != "icon128.png" {
+		t.Errorf("unexpected icons %v", m.Icons)
+	}
+}
+
+func TestOpenZip(t *testing.T) {
+	data := newTestZip(t, map[string]string{
+		"manifest.json":             testManifest,
+		"_locales/en/messages.json": testMessages,
+		"content.js":                "console.log(1)",
+	})
+
+	p, err := OpenBytes(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Format != FormatZIP {
+		t.Errorf("expected format zip, got %s", p.Format)
+	}
+	if p.ManifestErr != nil {
+		t.Fatalf("unexpected manifest error: %v", p.ManifestErr)
+	}
+	checkManifest(t, p.Manifest)
+
+	files, err := p.Files()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 3 || files[0].Name != "_locales/en/messages.json" {
+		t.Errorf("unexpected files %v", files)
+	}
+}
+
+func TestOpenCRX3(t *testing.T) {
+	zipData := newTestZip(t, map[string]string{
+		"manifest.json":             testManifest,
+		"_locales/en/messages.json": testMessages,
+	})
+
+	header := []byte("fake-header")
+	var buf bytes.Buffer
+	buf.WriteString("Cr24")
+	binary.Write(&buf, binary.LittleEndian, uint32(3))
+	binary.Write(&buf, binary.LittleEndian, uint32(len(header)))
+	buf.Write(header)
+	buf.Write(zipData)
+
+	p, err := OpenBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Format != FormatCRX {
+		t.Errorf("expected format crx, got %s", p.Format)
+	}
+	checkManifest(t, p.Manifest)
+}
+
+func TestOpenDirectory(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "_locales", "en"), 0o755)
+	os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(testManifest), 0o644)
+	os.WriteFile(filepath.Join(dir, "_locales", "en", "messages.json"), []byte(testMessages), 0o644)
+
+	p, err := Open(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Format != FormatDirectory {
+		t.Errorf("expected format directory, got %s", p.Format)
+	}
+	if p.Size != int64(len(testManifest)+len(testMessages)) {
+		t.Errorf("unexpected size %d", p.Size)
+	}
+	checkManifest(t, p.Manifest)
+}
+
+func TestOpenNestedManifest(t *testing.T) {
+	data := newTestZip(t, map[string]string{
+		"dist/manifest.json": `{"name": "x", "version": "1"}`,
+	})
+
+	p, err := OpenBytes(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !errors.Is(p.ManifestErr, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", p.ManifestErr)
+	}
+
+	paths, err := p.FindManifests()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(paths) != 1 || paths[0] != "dist/manifest.json" {
+		t.Errorf("unexpected manifests %v", paths)
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	messages := Messages{"appname": "Localized"}
+
+	tests := map[string]string{
+		"__MSG_appName__": "Localized",
+		"__MSG_APPNAME__": "Localized",
+		"__MSG_unknown__": "__MSG_unknown__",
+		"__MSG___":        "__MSG___",
+		"plain":           "plain",
+	}
+	for in, want := range tests {
+		if got := messages.Localize(in); got != want {
+			t.Errorf("Localize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
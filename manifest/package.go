@@ -0,0 +1,207 @@
+package manifest
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Format is the on-disk format of an extension package.
+type Format string
+
+const (
+	// FormatZIP is a ZIP archive as uploaded to the Chrome Web Store.
+	FormatZIP Format = "zip"
+	// FormatCRX is a signed CRX (version 2 or 3) archive.
+	FormatCRX Format = "crx"
+	// FormatDirectory is an unpacked extension directory.
+	FormatDirectory Format = "directory"
+)
+
+// crxMagic is the magic number at the start of every CRX file.
+const crxMagic = "Cr24"
+
+// Package is an opened extension package.
+type Package struct {
+	// Path is the path the package was opened from.
+	Path string
+	// Format is the format of the package.
+	Format Format
+	// Size is the size of the package file in bytes. For directories it is
+	// the total size of all files.
+	Size int64
+	// FS provides access to the files of the package.
+	FS fs.FS
+	// Manifest is the parsed manifest, or nil if ManifestErr is set.
+	Manifest *Manifest
+	// ManifestErr is the error encountered while loading the manifest.
+	ManifestErr error
+}
+
+// File describes a file within a package.
+type File struct {
+	// Name is the slash-separated path of the file within the package.
+	Name string `json:"name"`
+	// Size is the uncompressed size of the file in bytes.
+	Size int64 `json:"size"`
+}
+
+// Open opens the ZIP file, CRX file or directory at path and loads its
+// manifest. Failing to load the manifest is not an error; it is recorded
+// in ManifestErr so that the package can still be inspected.
+func Open(path string) (*Package, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("manifest: %w", err)
+	}
+
+	p := &Package{Path: path}
+	if info.IsDir() {
+		p.Format = FormatDirectory
+		p.FS = os.DirFS(path)
+	} else {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("manifest: %w", err)
+		}
+		if err := p.openArchive(data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.load(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// OpenBytes opens an in-memory ZIP or CRX package.
+func OpenBytes(data []byte) (*Package, error) {
+	p := &Package{}
+	if err := p.openArchive(data); err != nil {
+		return nil, err
+	}
+	if err := p.load(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// openArchive detects the archive format of data and sets FS.
+func (p *Package) openArchive(data []byte) error {
+	p.Format = FormatZIP
+	p.Size = int64(len(data))
+
+	zipData := data
+	if bytes.HasPrefix(data, []byte(crxMagic)) {
+		offset, err := crxZipOffset(data)
+		if err != nil {
+			return err
+		}
+		p.Format = FormatCRX
+		zipData = data[offset:]
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(zipData), int64(len(zipData)))
+	if err != nil {
+		return fmt.Errorf("manifest: failed to read %s archive: %w", p.Format, err)
+	}
+	p.FS = zr
+	return nil
+}
+
+// load computes the package size for directories and loads the manifest.
+func (p *Package) load() error {
+	if p.Format == FormatDirectory {
+		files, err := p.Files()
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			p.Size += f.Size
+		}
+	}
+
+	p.Manifest, p.ManifestErr = Load(p.FS)
+	return nil
+}
+
+// Files returns the regular files of the package sorted by name.
+func (p *Package) Files() ([]File, error) {
+	var files []File
+	err := fs.WalkDir(p.FS, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, File{Name: name, Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("manifest: failed to list files: %w", err)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, nil
+}
+
+// FindManifests returns the paths of every manifest.json in the package,
+// including nested ones. It is useful to diagnose packages whose manifest
+// is not at the root.
+func (p *Package) FindManifests() ([]string, error) {
+	files, err := p.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, f := range files {
+		if f.Name == FileName || strings.HasSuffix(f.Name, "/"+FileName) {
+			paths = append(paths, f.Name)
+		}
+	}
+	return paths, nil
+}
+
+// crxZipOffset returns the offset of the ZIP archive within CRX data.
+func crxZipOffset(data []byte) (int, error) {
+	if len(data) < 12 {
+		return 0, errors.New("manifest: truncated CRX header")
+	}
+
+	version := binary.LittleEndian.Uint32(data[4:8])
+	switch version {
+	case 2:
+		if len(data) < 16 {
+			return 0, errors.New("manifest: truncated CRX header")
+		}
+		keyLen := binary.LittleEndian.Uint32(data[8:12])
+		sigLen := binary.LittleEndian.Uint32(data[12:16])
+		offset := 16 + int64(keyLen) + int64(sigLen)
+		if offset > int64(len(data)) {
+			return 0, errors.New("manifest: truncated CRX header")
+		}
+		return int(offset), nil
+	case 3:
+		headerLen := binary.LittleEndian.Uint32(data[8:12])
+		offset := 12 + int64(headerLen)
+		if offset > int64(len(data)) {
+			return 0, errors.New("manifest: truncated CRX header")
+		}
+		return int(offset), nil
+	default:
+		return 0, fmt.Errorf("manifest: unsupported CRX version %d", version)
+	}
+}
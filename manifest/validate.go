@@ -0,0 +1,124 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+// DefaultMaxPackageSize is the default maximum package size accepted by Validate.
+const DefaultMaxPackageSize int64 = 2 << 30
+
+// Severity is the severity of a validation problem.
+type Severity string
+
+const (
+	// SeverityError marks a problem that will cause the upload to be rejected.
+	SeverityError Severity = "error"
+	// SeverityWarning marks a problem that should be reviewed.
+	SeverityWarning Severity = "warning"
+)
+
+// Problem is a single validation finding.
+type Problem struct {
+	// Severity is the severity of the problem.
+	Severity Severity `json:"severity"`
+	// Check is the name of the check that reported the problem.
+	Check string `json:"check"`
+	// Message describes the problem.
+	Message string `json:"message"`
+}
+
+// Problems is a list of validation findings.
+type Problems []Problem
+
+// HasErrors reports whether any problem has SeverityError.
+func (ps Problems) HasErrors() bool {
+	for _, p := range ps {
+		if p.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateOptions configures Validate.
+type ValidateOptions struct {
+	// MaxSize is the maximum package size in bytes.
+	// If zero, DefaultMaxPackageSize is used.
+	MaxSize int64
+	// StoreVersion is the highest version known to the store. If set, the
+	// manifest version must be greater than it.
+	StoreVersion string
+}
+
+// Validate checks p for common causes of rejected uploads and returns every
+// problem found.
+func Validate(p *Package, opts ValidateOptions) Problems {
+	var problems Problems
+	add := func(severity Severity, check, format string, args ...any) {
+		problems = append(problems, Problem{Severity: severity, Check: check, Message: fmt.Sprintf(format, args...)})
+	}
+
+	maxSize := opts.MaxSize
+	if maxSize == 0 {
+		maxSize = DefaultMaxPackageSize
+	}
+	if p.Size > maxSize {
+		add(SeverityError, "size", "package is %d bytes, exceeding the limit of %d bytes", p.Size, maxSize)
+	}
+
+	if p.ManifestErr != nil {
+		if errors.Is(p.ManifestErr, ErrNotFound) {
+			nested, _ := p.FindManifests()
+			if len(nested) > 0 {
+				add(SeverityError, "manifest", "manifest.json must be at the package root, found %s", strings.Join(nested, ", "))
+			} else {
+				add(SeverityError, "manifest", "manifest.json not found")
+			}
+		} else {
+			add(SeverityError, "manifest", "%v", p.ManifestErr)
+		}
+		return problems
+	}
+	m := p.Manifest
+
+	switch m.ManifestVersion {
+	case 3:
+	case 2:
+		add(SeverityWarning, "manifest_version", "Manifest V2 is deprecated")
+	default:
+		add(SeverityError, "manifest_version", "unsupported manifest_version %d", m.ManifestVersion)
+	}
+
+	if m.Name == "" {
+		add(SeverityError, "name", "name is missing")
+	}
+
+	version, err := ParseVersion(m.Version)
+	if err != nil {
+		add(SeverityError, "version", "%v", strings.TrimPrefix(err.Error(), "manifest: "))
+	} else if opts.StoreVersion != "" {
+		if store, err := ParseVersion(opts.StoreVersion); err == nil && version.Compare(store) <= 0 {
+			add(SeverityError, "version", "version %s must be greater than the store version %s", m.Version, opts.StoreVersion)
+		}
+	}
+
+	if len(m.Icons) == 0 {
+		add(SeverityWarning, "icons", "no icons are declared")
+	} else if _, ok := m.Icons["128"]; !ok {
+		add(SeverityWarning, "icons", "no 128x128 icon is declared")
+	}
+	for _, size := range slices.Sorted(maps.Keys(m.Icons)) {
+		name := m.Icons[size]
+		if _, err := fs.Stat(p.FS, path.Clean(strings.TrimPrefix(name, "/"))); err != nil {
+			add(SeverityError, "icons", "icon %s (%s) is missing from the package", size, name)
+		}
+	}
+
+	return problems
+}
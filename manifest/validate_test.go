@@ -0,0 +1,62 @@
+package manifest
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	data := newTestZip(t, map[string]string{
+		"manifest.json": `{"manifest_version": 3, "name": "x", "version": "1.2.0", "icons": {"128": "icon128.png", "48": "icon48.png"}}`,
+		"icon128.png":   "png",
+	})
+
+	p, err := OpenBytes(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	problems := Validate(p, ValidateOptions{StoreVersion: "1.2", MaxSize: 10})
+	if !problems.HasErrors() {
+		t.Fatal("expected errors")
+	}
+
+	checks := map[string]bool{}
+	for _, problem := range problems {
+		checks[problem.Check] = true
+	}
+	for _, check := range []string{"size", "version", "icons"} {
+		if !checks[check] {
+			t.Errorf("expected a %s problem, got %+v", check, problems)
+		}
+	}
+}
+
+func TestValidateValidPackage(t *testing.T) {
+	data := newTestZip(t, map[string]string{
+		"manifest.json": `{"manifest_version": 3, "name": "x", "version": "1.3", "icons": {"128": "/icon.png"}}`,
+		"icon.png":      "png",
+	})
+
+	p, err := OpenBytes(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if problems := Validate(p, ValidateOptions{StoreVersion: "1.2.9"}); len(problems) != 0 {
+		t.Errorf("expected no problems, got %+v", problems)
+	}
+}
+
+func TestValidateNestedManifest(t *testing.T) {
+	data := newTestZip(t, map[string]string{
+		"dist/manifest.json": `{"manifest_version": 3, "name": "x", "version": "1"}`,
+	})
+
+	p, err := OpenBytes(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	problems := Validate(p, ValidateOptions{})
+	if len(problems) != 1 || problems[0].Check != "manifest" {
+		t.Errorf("expected a single manifest problem, got %+v", problems)
+	}
+}
@@ -0,0 +1,136 @@
+package manifest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxVersionPart is the largest value allowed in a version component.
+const MaxVersionPart = 65535
+
+// Version is a parsed extension version: one to four dot-separated
+// integers between 0 and 65535.
+type Version []int
+
+// ParseVersion parses and validates an extension version string.
+func ParseVersion(s string) (Version, error) {
+	if s == "" {
+		return nil, fmt.Errorf("manifest: version is empty")
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 4 {
+		return nil, fmt.Errorf("manifest: invalid version %q: at most 4 components are allowed", s)
+	}
+
+	v := make(Version, len(parts))
+	for i, part := range parts {
+		if part == "" || strings.TrimLeft(part, "0123456789") != "" {
+			return nil, fmt.Errorf("manifest: invalid version %q: components must be integers", s)
+		}
+		if len(part) > 1 && part[0] == '0' {
+			return nil, fmt.Errorf("manifest: invalid version %q: components must not have leading zeros", s)
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n > MaxVersionPart {
+			return nil, fmt.Errorf("manifest: invalid version %q: components must be between 0 and %d", s, MaxVersionPart)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+// String returns the dot-separated representation of v.
+func (v Version) String() string {
+	parts := make([]string, len(v))
+	for i, n := range v {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ".")
+}
+
+// Part identifies a version component for Bump.
+type Part int
+
+const (
+	// PartMajor is the first version component.
+	PartMajor Part = iota
+	// PartMinor is the second version component.
+	PartMinor
+	// PartPatch is the third version component.
+	PartPatch
+	// PartBuild is the fourth version component.
+	PartBuild
+)
+
+// ParsePart parses "major", "minor", "patch" or "build".
+func ParsePart(s string) (Part, error) {
+	switch s {
+	case "major":
+		return PartMajor, nil
+	case "minor":
+		return PartMinor, nil
+	case "patch":
+		return PartPatch, nil
+	case "build":
+		return PartBuild, nil
+	default:
+		return 0, fmt.Errorf("manifest: unknown version part %q (want major, minor, patch or build)", s)
+	}
+}
+
+// Bump returns a copy of v with the given component incremented and all
+// following components reset to 0. The version is extended with zeros if
+// it has fewer components than required.
+func (v Version) Bump(part Part) (Version, error) {
+	n := max(len(v), int(part)+1)
+	bumped := make(Version, n)
+	copy(bumped, v)
+
+	if bumped[part] >= MaxVersionPart {
+		return nil, fmt.Errorf("manifest: cannot bump %s: component would exceed %d", v, MaxVersionPart)
+	}
+	bumped[part]++
+	for i := int(part) + 1; i < n; i++ {
+		bumped[i] = 0
+	}
+	return bumped, nil
+}
+
+// Compare returns -1, 0 or 1 if v is lower than, equal to or greater than o.
+// Missing components are treated as 0, so "1.0" equals "1.0.0".
+func (v Version) Compare(o Version) int {
+	for i := 0; i < len(v) || i < len(o); i++ {
+		var a, b int
+		if i < len(v) {
+			a = v[i]
+		}
+		if i < len(o) {
+			b = o[i]
+		}
+		if a < b {
+			return -1
+		}
+		if a > b {
+			return 1
+		}
+	}
+	return 0
+}
+
+// HighestVersion returns the highest valid version among versions.
+// Empty and invalid strings are ignored. It returns nil if none are valid.
+func HighestVersion(versions ...string) Version {
+	var highest Version
+	for _, s := range versions {
+		v, err := ParseVersion(s)
+		if err != nil {
+			continue
+		}
+		if highest == nil || v.Compare(highest) > 0 {
+			highest = v
+		}
+	}
+	return highest
+}
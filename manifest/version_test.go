@@ -0,0 +1,85 @@
+package manifest
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	valid := []string{"1", "1.0", "1.2.3.4", "0.0.1", "65535.0"}
+	for _, s := range valid {
+		v, err := ParseVersion(s)
+		if err != nil {
+			t.Errorf("ParseVersion(%q) returned error: %v", s, err)
+			continue
+		}
+		if v.String() != s {
+			t.Errorf("expected %q, got %q", s, v.String())
+		}
+	}
+
+	invalid := []string{"", "1.", ".1", "1.2.3.4.5", "1.a", "01.2", "65536", "-1", "1.2 beta"}
+	for _, s := range invalid {
+		if _, err := ParseVersion(s); err == nil {
+			t.Errorf("expected ParseVersion(%q) to fail", s)
+		}
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0.0", 0},
+		{"1.2", "1.10", -1},
+		{"2", "1.9.9.9", 1},
+		{"1.0.0.1", "1", 1},
+	}
+	for _, tt := range tests {
+		a, _ := ParseVersion(tt.a)
+		b, _ := ParseVersion(tt.b)
+		if got := a.Compare(b); got != tt.want {
+			t.Errorf("Compare(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestHighestVersion(t *testing.T) {
+	if got := HighestVersion("1.2", "", "invalid", "1.10"); got.String() != "1.10" {
+		t.Errorf("expected 1.10, got %s", got)
+	}
+	if got := HighestVersion("", "x"); got != nil {
+		t.Errorf("expected nil, got %s", got)
+	}
+}
+
+func TestVersionBump(t *testing.T) {
+	tests := []struct {
+		version string
+		part    Part
+		want    string
+	}{
+		{"1.2.3", PartMajor, "2.0.0"},
+		{"1.2.3", PartMinor, "1.3.0"},
+		{"1.2", PartPatch, "1.2.1"},
+		{"1", PartBuild, "1.0.0.1"},
+		{"1.2.3.4", PartPatch, "1.2.4.0"},
+	}
+	for _, tt := range tests {
+		v, _ := ParseVersion(tt.version)
+		got, err := v.Bump(tt.part)
+		if err != nil {
+			t.Errorf("Bump(%s) returned error: %v", tt.version, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("Bump(%s, %d) = %s, want %s", tt.version, tt.part, got, tt.want)
+		}
+		if v.String() != tt.version {
+			t.Errorf("Bump modified the receiver: %s", v)
+		}
+	}
+
+	v, _ := ParseVersion("1.65535")
+	if _, err := v.Bump(PartMinor); err == nil {
+		t.Error("expected overflow error")
+	}
+}
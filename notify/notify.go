@@ -0,0 +1,339 @@
+// Package notify posts item status change events to webhooks such as
+// Slack or Microsoft Teams incoming webhooks.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+// Format selects the payload template of a webhook.
+type Format string
+
+const (
+	// FormatJSON posts the change event as generic JSON.
+	FormatJSON Format = "json"
+	// FormatSlack posts a Slack-compatible {"text": ...} message.
+	FormatSlack Format = "slack"
+	// FormatTeams posts a Microsoft Teams MessageCard.
+	FormatTeams Format = "teams"
+)
+
+// SignatureHeader is the header carrying the HMAC-SHA256 signature of the
+// request body, in the form "sha256=<hex>", when a webhook has a secret.
+const SignatureHeader = "X-CWS-Signature"
+
+const (
+	// DefaultMaxRetries is the number of retries used when Webhook.MaxRetries is zero.
+	DefaultMaxRetries = 3
+	// DefaultRetryWait is the initial delay between retries. It doubles after
+	// every attempt.
+	DefaultRetryWait = time.Second
+)
+
+// Webhook is a notification target.
+type Webhook struct {
+	// URL is the endpoint the payload is posted to.
+	URL string `json:"url"`
+	// Format is the payload template. If empty, FormatJSON is used.
+	Format Format `json:"format,omitempty"`
+	// Secret, if set, is used to sign the request body.
+	Secret string `json:"secret,omitempty"`
+	// Items restricts notifications to the given item IDs or item names.
+	// If empty, events of all items are sent.
+	Items []string `json:"items,omitempty"`
+	// Events restricts notifications to the given change types.
+	// If empty, all change types except ChangeInitial are sent.
+	Events []chromewebstore.ChangeType `json:"events,omitempty"`
+	// States restricts ChangeState notifications to transitions into the
+	// given states, e.g. PUBLISHED or REJECTED. If empty, all are sent.
+	States []chromewebstore.ItemState `json:"states,omitempty"`
+	// MaxRetries is the number of retries after a failed delivery.
+	// If zero, DefaultMaxRetries is used; a negative value disables retries.
+	MaxRetries int `json:"maxRetries,omitempty"`
+}
+
+// Matches reports whether the webhook wants to be notified of event.
+func (w *Webhook) Matches(event chromewebstore.ChangeEvent) bool {
+	if len(w.Items) > 0 && !slices.ContainsFunc(w.Items, func(item string) bool {
+		return item == string(event.Item) || item == event.Item.ItemID()
+	}) {
+		return false
+	}
+
+	if len(w.Events) > 0 {
+		if !slices.Contains(w.Events, event.Type) {
+			return false
+		}
+	} else if event.Type == chromewebstore.ChangeInitial {
+		return false
+	}
+
+	if event.Type == chromewebstore.ChangeState && len(w.States) > 0 {
+		return slices.Contains(w.States, chromewebstore.ItemState(event.New))
+	}
+	return true
+}
+
+// changeTypes and itemStates are the values accepted in Webhook.Events
+// and Webhook.States.
+var (
+	changeTypes = []chromewebstore.ChangeType{
+		chromewebstore.ChangeInitial,
+		chromewebstore.ChangeState,
+		chromewebstore.ChangeVersion,
+		chromewebstore.ChangeDeployPercentage,
+		chromewebstore.ChangeWarned,
+		chromewebstore.ChangeTakenDown,
+		chromewebstore.ChangeUploadState,
+	}
+	itemStates = []chromewebstore.ItemState{
+		chromewebstore.ItemStatePendingReview,
+		chromewebstore.ItemStateStaged,
+		chromewebstore.ItemStatePublished,
+		chromewebstore.ItemStatePublishedToTesters,
+		chromewebstore.ItemStateRejected,
+		chromewebstore.ItemStateCancelled,
+	}
+)
+
+// Validate checks that the webhook has a URL, a known format and only
+// known events and states, which would otherwise never match.
+func (w *Webhook) Validate() error {
+	if w.URL == "" {
+		return errors.New("notify: webhook has no url")
+	}
+	switch w.Format {
+	case "", FormatJSON, FormatSlack, FormatTeams:
+	default:
+		return fmt.Errorf("notify: unknown webhook format %q (use json, slack or teams)", w.Format)
+	}
+	for _, e := range w.Events {
+		if !slices.Contains(changeTypes, e) {
+			return fmt.Errorf("notify: unknown event %q (use %s)", e, joinValues(changeTypes))
+		}
+	}
+	for _, s := range w.States {
+		if !slices.Contains(itemStates, s) {
+			return fmt.Errorf("notify: unknown state %q (use %s)", s, joinValues(itemStates))
+		}
+	}
+	return nil
+}
+
+// joinValues returns values separated by commas.
+func joinValues[T ~string](values []T) string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = string(v)
+	}
+	return strings.Join(s, ", ")
+}
+
+// Payload returns the request body for event.
+func (w *Webhook) Payload(event chromewebstore.ChangeEvent) ([]byte, error) {
+	text := Text(event)
+
+	switch w.Format {
+	case "", FormatJSON:
+		return json.Marshal(struct {
+			Text string `json:"text"`
+			chromewebstore.ChangeEvent
+		}{text, event})
+	case FormatSlack:
+		return json.Marshal(map[string]string{"text": text})
+	case FormatTeams:
+		return json.Marshal(map[string]string{
+			"@type":      "MessageCard",
+			"@context":   "https://schema.org/extensions",
+			"summary":    text,
+			"title":      "Chrome Web Store",
+			"text":       text,
+			"themeColor": themeColor(event),
+		})
+	default:
+		return nil, fmt.Errorf("notify: unknown webhook format %q", w.Format)
+	}
+}
+
+// Text returns a short human-readable message describing event.
+func Text(event chromewebstore.ChangeEvent) string {
+	item := event.Item.ItemID()
+	if event.Type == chromewebstore.ChangeState {
+		switch chromewebstore.ItemState(event.New) {
+		case chromewebstore.ItemStatePublished:
+			return fmt.Sprintf("%s: %s revision was published", item, event.Revision)
+		case chromewebstore.ItemStateRejected:
+			return fmt.Sprintf("%s: %s revision was rejected", item, event.Revision)
+		case chromewebstore.ItemStatePendingReview:
+			return fmt.Sprintf("%s: %s revision is pending review", item, event.Revision)
+		}
+	}
+	if event.Type == chromewebstore.ChangeTakenDown && event.New == "true" {
+		return fmt.Sprintf("%s: item was taken down", item)
+	}
+	if event.Type == chromewebstore.ChangeWarned && event.New == "true" {
+		return fmt.Sprintf("%s: item received a policy warning", item)
+	}
+	return event.String()
+}
+
+// themeColor returns the Teams card color for event.
+func themeColor(event chromewebstore.ChangeEvent) string {
+	switch {
+	case event.Type == chromewebstore.ChangeState && event.New == string(chromewebstore.ItemStatePublished):
+		return "2EB886"
+	case event.Type == chromewebstore.ChangeState && event.New == string(chromewebstore.ItemStateRejected),
+		event.Type == chromewebstore.ChangeTakenDown && event.New == "true":
+		return "D00000"
+	case event.Type == chromewebstore.ChangeWarned && event.New == "true":
+		return "DAA038"
+	default:
+		return "0078D7"
+	}
+}
+
+// Sign returns the value of SignatureHeader for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Notifier delivers change events to webhooks.
+type Notifier struct {
+	// Webhooks are the notification targets.
+	Webhooks []Webhook
+	// Client is the HTTP client used for deliveries.
+	// If nil, http.DefaultClient is used.
+	Client *http.Client
+	// RetryWait is the initial delay between retries.
+	// If zero, DefaultRetryWait is used.
+	RetryWait time.Duration
+}
+
+// Config is the on-disk webhook configuration read by LoadConfig.
+type Config struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// LoadConfig reads a JSON webhook configuration file. Environment variables
+// referenced as $VAR or ${VAR} in URLs and secrets are expanded.
+func LoadConfig(path string) (*Notifier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("notify: failed to read config: %w", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("notify: failed to parse config: %w", err)
+	}
+
+	for i := range config.Webhooks {
+		w := &config.Webhooks[i]
+		w.URL = os.ExpandEnv(w.URL)
+		w.Secret = os.ExpandEnv(w.Secret)
+		if err := w.Validate(); err != nil {
+			return nil, fmt.Errorf("%w (webhook %d)", err, i)
+		}
+	}
+	return &Notifier{Webhooks: config.Webhooks}, nil
+}
+
+// Notify posts event to every matching webhook. Deliveries are attempted for
+// all webhooks, and their errors are joined.
+func (n *Notifier) Notify(ctx context.Context, event chromewebstore.ChangeEvent) error {
+	var errs []error
+	for i := range n.Webhooks {
+		w := &n.Webhooks[i]
+		if !w.Matches(event) {
+			continue
+		}
+		if err := n.deliver(ctx, w, event); err != nil {
+			errs = append(errs, fmt.Errorf("notify: %s: %w", w.URL, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliver posts event to w, retrying on network errors, 429 and 5xx responses.
+func (n *Notifier) deliver(ctx context.Context, w *Webhook, event chromewebstore.ChangeEvent) error {
+	body, err := w.Payload(event)
+	if err != nil {
+		return err
+	}
+
+	retries := w.MaxRetries
+	if retries == 0 {
+		retries = DefaultMaxRetries
+	}
+	wait := n.RetryWait
+	if wait == 0 {
+		wait = DefaultRetryWait
+	}
+
+	for attempt := 0; ; attempt++ {
+		retry, err := n.post(ctx, w, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= retries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// post performs a single delivery and reports whether a failure may be retried.
+func (n *Notifier) post(ctx context.Context, w *Webhook, body []byte) (bool, error) {
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(w.Secret, body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		if s := strings.TrimSpace(string(msg)); s != "" {
+			return retry, fmt.Errorf("HTTP %d: %s", resp.StatusCode, s)
+		}
+		return retry, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return false, nil
+}
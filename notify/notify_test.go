@@ -0,0 +1,203 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+const testItemID = "abcdefghijklmnopabcdefghijklmnop"
+
+func testEvent(t chromewebstore.ChangeType, newValue string) chromewebstore.ChangeEvent {
+	return chromewebstore.ChangeEvent{
+		Item:     chromewebstore.NewItemName("pub", testItemID),
+		Type:     t,
+		Revision: chromewebstore.RevisionSubmitted,
+		Old:      string(chromewebstore.ItemStatePendingReview),
+		New:      newValue,
+		Time:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWebhookMatches(t *testing.T) {
+	approved := testEvent(chromewebstore.ChangeState, string(chromewebstore.ItemStatePublished))
+	rejected := testEvent(chromewebstore.ChangeState, string(chromewebstore.ItemStateRejected))
+	version := testEvent(chromewebstore.ChangeVersion, "1.0.1")
+	initial := testEvent(chromewebstore.ChangeInitial, "")
+
+	tests := []struct {
+		name    string
+		webhook Webhook
+		event   chromewebstore.ChangeEvent
+		want    bool
+	}{
+		{"all", Webhook{}, version, true},
+		{"initial skipped by default", Webhook{}, initial, false},
+		{"initial requested", Webhook{Events: []chromewebstore.ChangeType{chromewebstore.ChangeInitial}}, initial, true},
+		{"event type", Webhook{Events: []chromewebstore.ChangeType{chromewebstore.ChangeState}}, version, false},
+		{"state", Webhook{States: []chromewebstore.ItemState{chromewebstore.ItemStateRejected}}, rejected, true},
+		{"other state", Webhook{States: []chromewebstore.ItemState{chromewebstore.ItemStateRejected}}, approved, false},
+		{"item ID", Webhook{Items: []string{testItemID}}, approved, true},
+		{"item name", Webhook{Items: []string{"publishers/pub/items/" + testItemID}}, approved, true},
+		{"other item", Webhook{Items: []string{"ponmlkjihgfedcbaponmlkjihgfedcba"}}, approved, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.webhook.Matches(tt.event); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestWebhookPayload(t *testing.T) {
+	event := testEvent(chromewebstore.ChangeState, string(chromewebstore.ItemStateRejected))
+	expectedText := testItemID + ": submitted revision was rejected"
+
+	for _, format := range []Format{FormatJSON, FormatSlack, FormatTeams} {
+		body, err := (&Webhook{Format: format}).Payload(event)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+
+		var payload map[string]any
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Fatalf("%s: invalid JSON: %v", format, err)
+		}
+		if payload["text"] != expectedText {
+			t.Errorf("%s: expected text %q, got %v", format, expectedText, payload["text"])
+		}
+
+		switch format {
+		case FormatJSON:
+			if payload["type"] != "STATE" || payload["new"] != "REJECTED" {
+				t.Errorf("json: expected event fields, got %v", payload)
+			}
+		case FormatTeams:
+			if payload["@type"] != "MessageCard" {
+				t.Errorf("teams: expected MessageCard, got %v", payload["@type"])
+			}
+		}
+	}
+
+	if _, err := (&Webhook{Format: "xml"}).Payload(event); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestNotify(t *testing.T) {
+	var calls int
+	var signature string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		signature = r.Header.Get(SignatureHeader)
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	n := &Notifier{
+		Webhooks: []Webhook{
+			{URL: server.URL, Format: FormatSlack, Secret: "s3cret"},
+			{URL: server.URL, Events: []chromewebstore.ChangeType{chromewebstore.ChangeTakenDown}},
+		},
+		RetryWait: time.Millisecond,
+	}
+
+	event := testEvent(chromewebstore.ChangeState, string(chromewebstore.ItemStatePublished))
+	if err := n.Notify(context.Background(), event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+	if expected := Sign("s3cret", body); signature != expected {
+		t.Errorf("expected signature %s, got %s", expected, signature)
+	}
+	if !strings.HasPrefix(signature, "sha256=") {
+		t.Errorf("expected sha256= prefix, got %s", signature)
+	}
+}
+
+func TestNotifyClientError(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "bad payload", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	n := &Notifier{Webhooks: []Webhook{{URL: server.URL}}, RetryWait: time.Millisecond}
+	err := n.Notify(context.Background(), testEvent(chromewebstore.ChangeVersion, "1.0.1"))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "HTTP 400: bad payload") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected no retries, got %d attempts", calls)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("TEST_WEBHOOK_SECRET", "from-env")
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+	config := `{
+  "webhooks": [
+    {"url": "https://hooks.slack.com/services/x", "format": "slack", "secret": "${TEST_WEBHOOK_SECRET}", "events": ["STATE", "TAKEN_DOWN"]}
+  ]
+}`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(n.Webhooks) != 1 {
+		t.Fatalf("expected 1 webhook, got %d", len(n.Webhooks))
+	}
+	w := n.Webhooks[0]
+	if w.Format != FormatSlack || w.Secret != "from-env" || len(w.Events) != 2 {
+		t.Errorf("unexpected webhook: %+v", w)
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		errMsg string
+	}{
+		{"missing url", `{"webhooks": [{"format": "slack"}]}`, "webhook has no url (webhook 0)"},
+		{"unknown format", `{"webhooks": [{"url": "https://example.com"}, {"url": "https://example.com", "format": "discord"}]}`, `unknown webhook format "discord" (use json, slack or teams) (webhook 1)`},
+		{"unknown event", `{"webhooks": [{"url": "https://example.com", "events": ["STATE", "state"]}]}`, `unknown event "state" (use INITIAL, STATE, VERSION, DEPLOY_PERCENTAGE, WARNED, TAKEN_DOWN, UPLOAD_STATE) (webhook 0)`},
+		{"unknown state", `{"webhooks": [{"url": "https://example.com", "states": ["PUBLISHED", "LIVE"]}]}`, `unknown state "LIVE" (use PENDING_REVIEW, STAGED, PUBLISHED, PUBLISHED_TO_TESTERS, REJECTED, CANCELLED) (webhook 0)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "webhooks.json")
+			if err := os.WriteFile(path, []byte(tt.config), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+// Package pack builds deterministic Chrome Web Store ZIP packages from
+// extension source directories.
+//
+// The API client uses pack to upload directories, so neither pack nor
+// manifest may import chromewebstore.
+package pack
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/manifest"
+)
+
+// IgnoreFileName is the name of the ignore file read from the source directory.
+const IgnoreFileName = ".cwsignore"
+
+// DefaultIgnore are the patterns excluded from every package. They can be
+// re-included with negated patterns in .cwsignore.
+var DefaultIgnore = []string{
+	".git/",
+	".hg/",
+	".svn/",
+	".DS_Store",
+	"Thumbs.db",
+	"*.map",
+	IgnoreFileName,
+}
+
+// modTime is the timestamp written for every entry, the earliest
+// time representable in a ZIP file.
+var modTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Options configures packing.
+type Options struct {
+	// StripKey removes the "key" field from manifest.json.
+	StripKey bool
+	// Ignore are additional ignore patterns applied after DefaultIgnore
+	// and .cwsignore.
+	Ignore []string
+	// OnSkip, if set, is called with the paths that are not ignored but
+	// left out because they are not regular files, such as symbolic links,
+	// which are not followed.
+	OnSkip func(name string)
+}
+
+// Files returns the slash-separated paths of the files that would be
+// packed from dir, in archive order. Only regular files are packed; other
+// entries are reported to opts.OnSkip.
+func Files(dir string, opts Options) ([]string, error) {
+	rules, err := loadRules(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	err = fs.WalkDir(os.DirFS(dir), ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == "." {
+			return nil
+		}
+		if rules.ignored(name, d.IsDir()) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		switch {
+		case d.Type().IsRegular():
+			files = append(files, name)
+		case !d.IsDir() && opts.OnSkip != nil:
+			opts.OnSkip(name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pack: failed to walk %s: %w", dir, err)
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// Write writes a deterministic ZIP package of dir to w. Entries are sorted
+// by path, have a fixed timestamp and normalized permissions, so packing the
+// same sources always produces identical bytes.
+func Write(w io.Writer, dir string, opts Options) error {
+	files, err := Files(dir, opts)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	for _, name := range files {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return fmt.Errorf("pack: %w", err)
+		}
+
+		if name == manifest.FileName && opts.StripKey {
+			data, err = manifest.RemoveMember(data, "key")
+			if err != nil {
+				return fmt.Errorf("pack: %w", err)
+			}
+		}
+
+		header := &zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: modTime,
+		}
+		header.SetMode(0o644)
+
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			return fmt.Errorf("pack: failed to add %s: %w", name, err)
+		}
+		if _, err := fw.Write(data); err != nil {
+			return fmt.Errorf("pack: failed to add %s: %w", name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("pack: failed to finish archive: %w", err)
+	}
+	return nil
+}
+
+// Bytes returns a deterministic ZIP package of dir.
+func Bytes(dir string, opts Options) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Write(&buf, dir, opts); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Reader returns a reader streaming a deterministic ZIP package of dir as
+// it is packed, for example as the media of an upload. Packing errors are
+// returned by Read; closing the reader stops packing.
+func Reader(dir string, opts Options) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(Write(pw, dir, opts))
+	}()
+	return pr
+}
+
+// rule is a single ignore pattern.
+type rule struct {
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// rules is an ordered list of ignore patterns; the last matching rule wins.
+type rules []rule
+
+// loadRules combines DefaultIgnore, the .cwsignore file in dir and opts.Ignore.
+func loadRules(dir string, opts Options) (rules, error) {
+	patterns := append([]string{}, DefaultIgnore...)
+
+	f, err := os.Open(filepath.Join(dir, IgnoreFileName))
+	if err == nil {
+		defer f.Close()
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			patterns = append(patterns, sc.Text())
+		}
+		if err := sc.Err(); err != nil {
+			return nil, fmt.Errorf("pack: failed to read %s: %w", IgnoreFileName, err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("pack: %w", err)
+	}
+
+	patterns = append(patterns, opts.Ignore...)
+
+	var rs rules
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" || strings.HasPrefix(p, "#") {
+			continue
+		}
+
+		var r rule
+		if strings.HasPrefix(p, "!") {
+			r.negate = true
+			p = p[1:]
+		}
+		if strings.HasSuffix(p, "/") {
+			r.dirOnly = true
+			p = strings.TrimSuffix(p, "/")
+		}
+		p = strings.TrimPrefix(p, "**/")
+		if strings.Contains(p, "/") {
+			r.anchored = true
+			p = strings.TrimPrefix(p, "/")
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("pack: invalid ignore pattern %q: %w", p, err)
+		}
+		r.pattern = p
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+// ignored reports whether the slash-separated path name is excluded.
+func (rs rules) ignored(name string, isDir bool) bool {
+	ignored := false
+	for _, r := range rs {
+		if r.dirOnly && !isDir {
+			continue
+		}
+
+		target := name
+		if !r.anchored {
+			target = path.Base(name)
+		}
+		if ok, _ := path.Match(r.pattern, target); ok {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,151 @@
+package pack
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"manifest.json":    "{}",
+		"js/app.js":        "",
+		"js/app.js.map":    "",
+		"js/vendor.js.map": "",
+		".git/HEAD":        "",
+		"img/.DS_Store":    "",
+		"docs/README.md":   "",
+		"src/index.ts":     "",
+		"a.txt":            "",
+		IgnoreFileName:     "docs/\n/src\n# comment\n!vendor.js.map\n",
+	})
+
+	files, err := Files(dir, Options{Ignore: []string{"*.txt"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "js/app.js,js/vendor.js.map,manifest.json"
+	if got := strings.Join(files, ","); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestWriteIsDeterministic(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"manifest.json": "{\n  \"name\": \"x\",\n  \"key\": \"abc\",\n  \"version\": \"1\"\n}\n",
+		"b/b.js":        "b",
+		"a.js":          "a",
+	})
+
+	first, err := Bytes(dir, Options{StripKey: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := time.Now()
+	os.Chtimes(filepath.Join(dir, "a.js"), now, now)
+	os.Chmod(filepath.Join(dir, "a.js"), 0o755)
+
+	second, err := Bytes(dir, Options{StripKey: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !bytes.Equal(first, second) {
+		t.Error("expected identical archives")
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(first), int64(len(first)))
+	if err != nil {
+		t.Fatalf("failed to read archive: %v", err)
+	}
+
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+		if f.Mode().Perm() != 0o644 {
+			t.Errorf("expected mode 0644 for %s, got %v", f.Name, f.Mode())
+		}
+		if f.Name == "manifest.json" {
+			rc, _ := f.Open()
+			data, _ := io.ReadAll(rc)
+			rc.Close()
+			if strings.Contains(string(data), "key") {
+				t.Errorf("expected key to be stripped, got %s", data)
+			}
+		}
+	}
+
+	if got := strings.Join(names, ","); got != "a.js,b/b.js,manifest.json" {
+		t.Errorf("unexpected entry order %s", got)
+	}
+}
+
+func TestFilesSkipsSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"manifest.json": "{}", "lib/real.js": ""})
+	if err := os.Symlink("real.js", filepath.Join(dir, "lib", "link.js")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	if err := os.Symlink("lib", filepath.Join(dir, "linked")); err != nil {
+		t.Fatal(err)
+	}
+
+	var skipped []string
+	files, err := Files(dir, Options{OnSkip: func(name string) { skipped = append(skipped, name) }})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(files, ","); got != "lib/real.js,manifest.json" {
+		t.Errorf("expected only regular files, got %s", got)
+	}
+	if got := strings.Join(skipped, ","); got != "lib/link.js,linked" {
+		t.Errorf("expected the symlinks to be reported, got %s", got)
+	}
+}
+
+func TestReader(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"manifest.json": "{}", "a.js": "a"})
+
+	want, err := Bytes(dir, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := Reader(dir, Options{})
+	defer r.Close()
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("expected the streamed package to match Bytes")
+	}
+
+	r = Reader(filepath.Join(dir, "missing"), Options{})
+	defer r.Close()
+	if _, err := io.ReadAll(r); err == nil {
+		t.Error("expected the packing error from Read")
+	}
+}
@@ -0,0 +1,305 @@
+// Package policy enforces organization rules on publishes and deploy
+// percentage changes, such as requiring staged publishing, capping the
+// initial deploy percentage or forbidding releases during freeze windows.
+// An Engine is set on a client with chromewebstore.Client.SetPolicy and
+// blocks violating operations before they are sent, unless a violation is
+// overridden with a reason.
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+// Config is a policy configuration file.
+type Config struct {
+	// Timezone is the IANA time zone of weekdays. If empty, the local time
+	// zone is used.
+	Timezone string `json:"timezone,omitempty"`
+	// Rules are the rules enforced.
+	Rules []Rule `json:"rules"`
+}
+
+// Rule is a named set of conditions. An operation violates the rule if it
+// fails any of its conditions.
+type Rule struct {
+	// Name identifies the rule in violations and overrides.
+	Name string `json:"name"`
+	// Description explains the rule to whoever is blocked by it.
+	Description string `json:"description,omitempty"`
+	// Items restricts the rule to the given item IDs. If empty, the rule
+	// applies to every item.
+	Items []string `json:"items,omitempty"`
+	// Operations restricts the rule to the given operations. If empty, the
+	// rule applies to publishes and deploy percentage changes.
+	Operations []chromewebstore.PolicyOperation `json:"operations,omitempty"`
+	// NoOverride forbids overriding violations of the rule.
+	NoOverride bool `json:"noOverride,omitempty"`
+
+	// NoSkipReview forbids publishing with SkipReview.
+	NoSkipReview bool `json:"noSkipReview,omitempty"`
+	// RequireStaged requires publishing with PublishTypeStaged.
+	RequireStaged bool `json:"requireStaged,omitempty"`
+	// MaxInitialDeployPercentage requires publishes to set an initial
+	// deploy percentage of at most this value.
+	MaxInitialDeployPercentage *int `json:"maxInitialDeployPercentage,omitempty"`
+	// BlockedWeekdays forbids the operations on the given days, such as
+	// "Friday" or "Fri".
+	BlockedWeekdays []string `json:"blockedWeekdays,omitempty"`
+	// FreezeWindows forbid the operations during the given periods.
+	FreezeWindows []FreezeWindow `json:"freezeWindows,omitempty"`
+}
+
+// FreezeWindow is a period in which operations are forbidden.
+type FreezeWindow struct {
+	// Start is the start of the period, inclusive.
+	Start time.Time `json:"start"`
+	// End is the end of the period, exclusive.
+	End time.Time `json:"end"`
+	// Reason explains the freeze.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Violation is a rule violated by an operation.
+type Violation struct {
+	// Rule is the name of the rule.
+	Rule string
+	// Message describes how the operation violates the rule.
+	Message string
+	// Description is the description of the rule.
+	Description string
+	// Overridable reports whether the violation may be overridden.
+	Overridable bool
+}
+
+// String returns the rule, the message and the description.
+func (v Violation) String() string {
+	s := v.Rule + ": " + v.Message
+	if v.Description != "" {
+		s += " (" + v.Description + ")"
+	}
+	if !v.Overridable {
+		s += " [cannot be overridden]"
+	}
+	return s
+}
+
+// ViolationError is returned by Engine.Check for operations blocked by
+// violations that were not overridden.
+type ViolationError struct {
+	// Operation is the operation blocked.
+	Operation chromewebstore.PolicyOperation
+	// Item is the item of the operation.
+	Item chromewebstore.ItemName
+	// Violations are the violations blocking the operation.
+	Violations []Violation
+}
+
+// Error returns the error message.
+func (e *ViolationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "policy: %s of %s blocked", e.Operation, e.Item)
+	for _, v := range e.Violations {
+		b.WriteString("\n  - " + v.String())
+	}
+	return b.String()
+}
+
+// Overridable reports whether every violation may be overridden.
+func (e *ViolationError) Overridable() bool {
+	return !slices.ContainsFunc(e.Violations, func(v Violation) bool { return !v.Overridable })
+}
+
+// Engine evaluates rules. It implements chromewebstore.Policy.
+type Engine struct {
+	// Rules are the rules enforced.
+	Rules []Rule
+	// Location is the time zone of weekdays. If nil, time.Local is used.
+	Location *time.Location
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+}
+
+// LoadConfig reads a JSON policy configuration file and returns its engine.
+// Environment variables in item IDs are expanded.
+func LoadConfig(path string) (*Engine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("policy: failed to read config: %w", err)
+	}
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("policy: failed to parse config: %w", err)
+	}
+	for i := range config.Rules {
+		for j, item := range config.Rules[i].Items {
+			config.Rules[i].Items[j] = os.ExpandEnv(item)
+		}
+	}
+	return NewEngine(config)
+}
+
+// NewEngine validates config and returns its engine.
+func NewEngine(config Config) (*Engine, error) {
+	e := &Engine{Rules: config.Rules, Location: time.Local}
+	if config.Timezone != "" {
+		loc, err := time.LoadLocation(config.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("policy: invalid timezone: %w", err)
+		}
+		e.Location = loc
+	}
+
+	seen := make(map[string]bool)
+	for i, r := range config.Rules {
+		if r.Name == "" {
+			return nil, fmt.Errorf("policy: rule %d has no name", i)
+		}
+		if seen[r.Name] {
+			return nil, fmt.Errorf("policy: duplicate rule %s", r.Name)
+		}
+		seen[r.Name] = true
+		if !r.NoSkipReview && !r.RequireStaged && r.MaxInitialDeployPercentage == nil && len(r.BlockedWeekdays) == 0 && len(r.FreezeWindows) == 0 {
+			return nil, fmt.Errorf("policy: rule %s has no conditions", r.Name)
+		}
+		for _, op := range r.Operations {
+			if op != chromewebstore.PolicyOperationPublish && op != chromewebstore.PolicyOperationSetDeployPercentage {
+				return nil, fmt.Errorf("policy: rule %s: unknown operation %q", r.Name, op)
+			}
+		}
+		if p := r.MaxInitialDeployPercentage; p != nil && (*p < 0 || *p > 100) {
+			return nil, fmt.Errorf("policy: rule %s: maxInitialDeployPercentage must be between 0 and 100", r.Name)
+		}
+		for _, day := range r.BlockedWeekdays {
+			if _, ok := parseWeekday(day); !ok {
+				return nil, fmt.Errorf("policy: rule %s: unknown weekday %q", r.Name, day)
+			}
+		}
+		for _, w := range r.FreezeWindows {
+			if !w.Start.Before(w.End) {
+				return nil, fmt.Errorf("policy: rule %s: freeze window must end after it starts", r.Name)
+			}
+		}
+	}
+	return e, nil
+}
+
+// parseWeekday parses a weekday name or its three-letter abbreviation.
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(s, d.String()) || strings.EqualFold(s, d.String()[:3]) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// Evaluate returns every violation of req, ignoring overrides.
+func (e *Engine) Evaluate(req *chromewebstore.PolicyRequest) []Violation {
+	now := time.Now()
+	if e.Now != nil {
+		now = e.Now()
+	}
+	loc := e.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	now = now.In(loc)
+
+	var violations []Violation
+	for _, r := range e.Rules {
+		if !r.applies(req) {
+			continue
+		}
+		for _, msg := range r.check(req, now) {
+			violations = append(violations, Violation{
+				Rule:        r.Name,
+				Message:     msg,
+				Description: r.Description,
+				Overridable: !r.NoOverride,
+			})
+		}
+	}
+	return violations
+}
+
+// Check implements chromewebstore.Policy. Violations are overridden if
+// req.Override has a reason, the rule allows overrides and the override
+// names the rule or no rules.
+func (e *Engine) Check(ctx context.Context, req *chromewebstore.PolicyRequest) ([]string, error) {
+	var blocking []Violation
+	var overridden []string
+	for _, v := range e.Evaluate(req) {
+		if req.Override != nil && req.Override.Reason != "" && v.Overridable &&
+			(len(req.Override.Rules) == 0 || slices.Contains(req.Override.Rules, v.Rule)) {
+			if !slices.Contains(overridden, v.Rule) {
+				overridden = append(overridden, v.Rule)
+			}
+			continue
+		}
+		blocking = append(blocking, v)
+	}
+	if len(blocking) > 0 {
+		return nil, &ViolationError{Operation: req.Operation, Item: req.Item, Violations: blocking}
+	}
+	return overridden, nil
+}
+
+// applies reports whether the rule applies to the item and operation of
+// req.
+func (r *Rule) applies(req *chromewebstore.PolicyRequest) bool {
+	if len(r.Items) > 0 && !slices.Contains(r.Items, req.Item.ItemID()) && !slices.Contains(r.Items, string(req.Item)) {
+		return false
+	}
+	return len(r.Operations) == 0 || slices.Contains(r.Operations, req.Operation)
+}
+
+// check returns a message for every condition of the rule req fails.
+func (r *Rule) check(req *chromewebstore.PolicyRequest, now time.Time) []string {
+	var msgs []string
+	if p := req.Publish; req.Operation == chromewebstore.PolicyOperationPublish && p != nil {
+		if r.NoSkipReview && p.SkipReview {
+			msgs = append(msgs, "review must not be skipped")
+		}
+		if r.RequireStaged && p.PublishType != chromewebstore.PublishTypeStaged {
+			publishType := p.PublishType
+			if publishType == "" {
+				publishType = chromewebstore.PublishTypeDefault
+			}
+			msgs = append(msgs, fmt.Sprintf("publish type must be %s, not %s", chromewebstore.PublishTypeStaged, publishType))
+		}
+		if max := r.MaxInitialDeployPercentage; max != nil {
+			if len(p.DeployInfos) == 0 {
+				msgs = append(msgs, fmt.Sprintf("an initial deploy percentage of at most %d%% must be set", *max))
+			}
+			for _, info := range p.DeployInfos {
+				if info.DeployPercentage > *max {
+					msgs = append(msgs, fmt.Sprintf("initial deploy percentage %d%% exceeds %d%%", info.DeployPercentage, *max))
+				}
+			}
+		}
+	}
+
+	for _, day := range r.BlockedWeekdays {
+		if d, _ := parseWeekday(day); d == now.Weekday() {
+			msgs = append(msgs, fmt.Sprintf("not allowed on %ss (%s)", d, now.Location()))
+		}
+	}
+	for _, w := range r.FreezeWindows {
+		if !now.Before(w.Start) && now.Before(w.End) {
+			msg := fmt.Sprintf("freeze window until %s", w.End.In(now.Location()).Format(time.RFC3339))
+			if w.Reason != "" {
+				msg += ": " + w.Reason
+			}
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
@@ -0,0 +1,204 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+const (
+	flagshipID = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	otherID    = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+func intPtr(n int) *int { return &n }
+
+func newTestEngine(t *testing.T, now time.Time) *Engine {
+	t.Helper()
+	e, err := NewEngine(Config{Rules: []Rule{
+		{Name: "no-skip-review", Items: []string{flagshipID}, NoSkipReview: true, NoOverride: true},
+		{Name: "staged", Description: "Flagship extensions are staged first", Items: []string{flagshipID}, RequireStaged: true},
+		{Name: "initial-rollout", Operations: []chromewebstore.PolicyOperation{chromewebstore.PolicyOperationPublish}, MaxInitialDeployPercentage: intPtr(10)},
+		{Name: "no-fridays", BlockedWeekdays: []string{"Fri"}},
+		{Name: "year-end", FreezeWindows: []FreezeWindow{{
+			Start:  time.Date(2026, 12, 24, 0, 0, 0, 0, time.UTC),
+			End:    time.Date(2027, 1, 4, 0, 0, 0, 0, time.UTC),
+			Reason: "year-end freeze",
+		}}},
+	}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	e.Location = time.UTC
+	e.Now = func() time.Time { return now }
+	return e
+}
+
+func violatedRules(err error) []string {
+	var verr *ViolationError
+	if !errors.As(err, &verr) {
+		return nil
+	}
+	var rules []string
+	for _, v := range verr.Violations {
+		rules = append(rules, v.Rule)
+	}
+	return rules
+}
+
+func TestEngineCheck(t *testing.T) {
+	monday := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	friday := time.Date(2026, 10, 23, 12, 0, 0, 0, time.UTC)
+	christmas := time.Date(2026, 12, 25, 12, 0, 0, 0, time.UTC)
+	flagship := chromewebstore.NewItemName("pub", flagshipID)
+	other := chromewebstore.NewItemName("pub", otherID)
+
+	tests := []struct {
+		name  string
+		now   time.Time
+		req   chromewebstore.PolicyRequest
+		rules []string
+	}{
+		{
+			name: "allowed publish",
+			now:  monday,
+			req: chromewebstore.PolicyRequest{Operation: chromewebstore.PolicyOperationPublish, Item: flagship, Publish: &chromewebstore.PublishRequest{
+				PublishType: chromewebstore.PublishTypeStaged,
+				DeployInfos: []chromewebstore.DeployInfo{{DeployPercentage: 10}},
+			}},
+		},
+		{
+			name:  "flagship publish",
+			now:   monday,
+			req:   chromewebstore.PolicyRequest{Operation: chromewebstore.PolicyOperationPublish, Item: flagship, Publish: &chromewebstore.PublishRequest{SkipReview: true}},
+			rules: []string{"no-skip-review", "staged", "initial-rollout"},
+		},
+		{
+			name: "other item publish",
+			now:  monday,
+			req: chromewebstore.PolicyRequest{Operation: chromewebstore.PolicyOperationPublish, Item: other, Publish: &chromewebstore.PublishRequest{
+				SkipReview:  true,
+				DeployInfos: []chromewebstore.DeployInfo{{DeployPercentage: 50}},
+			}},
+			rules: []string{"initial-rollout"},
+		},
+		{
+			name:  "deploy percentage on Friday",
+			now:   friday,
+			req:   chromewebstore.PolicyRequest{Operation: chromewebstore.PolicyOperationSetDeployPercentage, Item: other, DeployPercentage: 50},
+			rules: []string{"no-fridays"},
+		},
+		{
+			name:  "deploy percentage in freeze window",
+			now:   christmas,
+			req:   chromewebstore.PolicyRequest{Operation: chromewebstore.PolicyOperationSetDeployPercentage, Item: other, DeployPercentage: 50},
+			rules: []string{"no-fridays", "year-end"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTestEngine(t, tt.now).Check(context.Background(), &tt.req)
+			if len(tt.rules) == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if rules := violatedRules(err); !slices.Equal(rules, tt.rules) {
+				t.Errorf("expected violations of %v, got %v", tt.rules, err)
+			}
+		})
+	}
+}
+
+func TestEngineOverride(t *testing.T) {
+	e := newTestEngine(t, time.Date(2026, 10, 23, 12, 0, 0, 0, time.UTC))
+	flagship := chromewebstore.NewItemName("pub", flagshipID)
+	publish := func(override *chromewebstore.PolicyOverride) chromewebstore.PolicyRequest {
+		return chromewebstore.PolicyRequest{
+			Operation: chromewebstore.PolicyOperationPublish,
+			Item:      flagship,
+			Publish:   &chromewebstore.PublishRequest{DeployInfos: []chromewebstore.DeployInfo{{DeployPercentage: 5}}},
+			Override:  override,
+		}
+	}
+
+	req := publish(&chromewebstore.PolicyOverride{Reason: "security fix"})
+	overridden, err := e.Check(context.Background(), &req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !slices.Equal(overridden, []string{"staged", "no-fridays"}) {
+		t.Errorf("expected staged and no-fridays to be overridden, got %v", overridden)
+	}
+
+	req = publish(&chromewebstore.PolicyOverride{Reason: "security fix", Rules: []string{"no-fridays"}})
+	_, err = e.Check(context.Background(), &req)
+	if rules := violatedRules(err); !slices.Equal(rules, []string{"staged"}) {
+		t.Errorf("expected only staged to block, got %v", err)
+	}
+
+	req = publish(&chromewebstore.PolicyOverride{})
+	_, err = e.Check(context.Background(), &req)
+	if rules := violatedRules(err); len(rules) != 2 {
+		t.Errorf("expected an override without a reason to be ignored, got %v", err)
+	}
+
+	req = chromewebstore.PolicyRequest{
+		Operation: chromewebstore.PolicyOperationPublish,
+		Item:      flagship,
+		Publish:   &chromewebstore.PublishRequest{PublishType: chromewebstore.PublishTypeStaged, SkipReview: true, DeployInfos: []chromewebstore.DeployInfo{{DeployPercentage: 5}}},
+		Override:  &chromewebstore.PolicyOverride{Reason: "security fix"},
+	}
+	_, err = e.Check(context.Background(), &req)
+	var verr *ViolationError
+	if !errors.As(err, &verr) || verr.Overridable() {
+		t.Fatalf("expected a violation that cannot be overridden, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "no-skip-review: review must not be skipped [cannot be overridden]") {
+		t.Errorf("expected the violation to be explained, got %q", err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	config := `{
+  "timezone": "UTC",
+  "rules": [
+    {"name": "no-fridays", "blockedWeekdays": ["Friday"]},
+    {"name": "freeze", "freezeWindows": [{"start": "2026-12-24T00:00:00+09:00", "end": "2027-01-04T00:00:00+09:00"}]}
+  ]
+}`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	e, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(e.Rules) != 2 || e.Location != time.UTC {
+		t.Errorf("unexpected engine %+v", e)
+	}
+
+	invalid := []Config{
+		{Rules: []Rule{{NoSkipReview: true}}},
+		{Rules: []Rule{{Name: "a", NoSkipReview: true}, {Name: "a", RequireStaged: true}}},
+		{Rules: []Rule{{Name: "a"}}},
+		{Rules: []Rule{{Name: "a", BlockedWeekdays: []string{"Caturday"}}}},
+		{Rules: []Rule{{Name: "a", MaxInitialDeployPercentage: intPtr(101)}}},
+		{Rules: []Rule{{Name: "a", NoSkipReview: true, Operations: []chromewebstore.PolicyOperation{"upload"}}}},
+		{Rules: []Rule{{Name: "a", FreezeWindows: []FreezeWindow{{Start: time.Now(), End: time.Now().Add(-time.Hour)}}}}},
+	}
+	for i, c := range invalid {
+		if _, err := NewEngine(c); err == nil {
+			t.Errorf("expected config %d to be invalid", i)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package release
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/H0R15H0/chrome-webstore-api-v2/crx"
+	"github.com/H0R15H0/chrome-webstore-api-v2/manifest"
+	"github.com/H0R15H0/chrome-webstore-api-v2/pack"
+)
+
+// PackageVersion returns the manifest version of the package at path.
+func PackageVersion(path string) (string, error) {
+	pkg, err := manifest.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("release: failed to open package: %w", err)
+	}
+	if pkg.ManifestErr != nil {
+		return "", fmt.Errorf("release: %w", pkg.ManifestErr)
+	}
+	return pkg.Manifest.Version, nil
+}
+
+// Applier executes plans.
+type Applier struct {
+	// Client is the API client.
+	Client *chromewebstore.Client
+	// OnAction, if set, is called after each action with its result: an
+	// *UploadResponse, *PublishResponse or *SetPublishedDeployPercentageResponse.
+	OnAction func(action Action, result any)
+	// OnPoll, if set, is called with every status fetched while waiting.
+	OnPoll func(status *chromewebstore.ItemStatus)
+}
+
+// Apply executes the actions of p and then waits as requested by the wait
+// policy of its spec. When waiting for publication, actions that only
+// become possible once the version is published, such as raising the
+// deploy percentage, are planned and executed afterwards.
+func (a *Applier) Apply(ctx context.Context, p *Plan) error {
+	spec := p.spec
+	if timeout := time.Duration(spec.Wait.Timeout); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	if err := a.execute(ctx, p); err != nil {
+		return err
+	}
+
+	switch spec.waitFor() {
+	case WaitReview:
+		_, err := a.poll(ctx, p, func(status *chromewebstore.ItemStatus) (bool, error) {
+			return reviewDone(status, p.Version)
+		})
+		return err
+	case WaitPublished:
+		status, err := a.poll(ctx, p, func(status *chromewebstore.ItemStatus) (bool, error) {
+			if done, err := reviewDone(status, p.Version); !done || err != nil {
+				return done, err
+			}
+			return revisionOf(status.PublishedItemRevisionStatus).version == p.Version, nil
+		})
+		if err != nil {
+			return err
+		}
+		next, err := NewPlan(spec, p.Item, status, p.Version)
+		if err != nil {
+			return err
+		}
+		p.Notes = append(p.Notes, next.Notes...)
+		return a.execute(ctx, next)
+	}
+	return nil
+}
+
+// execute runs the actions of p in order.
+func (a *Applier) execute(ctx context.Context, p *Plan) error {
+	spec := p.spec
+	for _, action := range p.Actions {
+		var result any
+		var err error
+		switch action.Type {
+		case ActionUpload:
+			result, err = a.upload(ctx, p)
+		case ActionPublish:
+			call := a.Client.Publishers.Items.Publish(p.Item).
+				Context(ctx).
+				SkipReview(spec.SkipReview).
+				Validate(true)
+			if spec.publishType() == PublishStaged {
+				call.PublishType(chromewebstore.PublishTypeStaged)
+			} else {
+				call.PublishType(chromewebstore.PublishTypeDefault)
+			}
+			if spec.DeployPercentage > 0 {
+				call.DeployPercentage(spec.DeployPercentage)
+			}
+			result, err = call.Do()
+		case ActionSetDeployPercentage:
+			result, err = a.Client.Publishers.Items.SetPublishedDeployPercentage(p.Item).
+				Context(ctx).
+				DeployPercentage(spec.DeployPercentage).
+				Validate(true).
+				Do()
+		default:
+			err = fmt.Errorf("release: unknown action %q", action.Type)
+		}
+		if err != nil {
+			return fmt.Errorf("release: %s: %w", action.Type, err)
+		}
+		if a.OnAction != nil {
+			a.OnAction(action, result)
+		}
+	}
+	return nil
+}
+
+// upload uploads the spec's package and waits for asynchronous processing.
+func (a *Applier) upload(ctx context.Context, p *Plan) (*chromewebstore.UploadResponse, error) {
+	path := p.spec.Package
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	call := a.Client.Media.Upload(p.Item).Context(ctx)
+	if info.IsDir() {
+		call.Directory(path, pack.Options{})
+	} else {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		mediaType := chromewebstore.MediaTypeZIP
+		if crx.IsCRX(data) {
+			mediaType = chromewebstore.MediaTypeCRX
+		}
+		call.Media(bytes.NewReader(data), mediaType)
+	}
+
+	resp, err := call.Do()
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.UploadState {
+	case chromewebstore.UploadStateFailed:
+		return resp, fmt.Errorf("upload failed")
+	case chromewebstore.UploadStateInProgress:
+		_, err := a.poll(ctx, p, func(status *chromewebstore.ItemStatus) (bool, error) {
+			switch status.LastAsyncUploadState {
+			case chromewebstore.UploadStateInProgress:
+				return false, nil
+			case chromewebstore.UploadStateFailed:
+				return false, fmt.Errorf("upload failed")
+			}
+			return true, nil
+		})
+		if err != nil {
+			return resp, err
+		}
+	}
+	return resp, nil
+}
+
+// poll fetches the item status until done reports true or an error.
+func (a *Applier) poll(ctx context.Context, p *Plan, done func(*chromewebstore.ItemStatus) (bool, error)) (*chromewebstore.ItemStatus, error) {
+	interval := time.Duration(p.spec.Wait.Interval)
+	if interval <= 0 {
+		interval = DefaultWaitInterval
+	}
+
+	for {
+		status, err := a.Client.Publishers.Items.FetchStatus(p.Item).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		if a.OnPoll != nil {
+			a.OnPoll(status)
+		}
+		ok, err := done(status)
+		if err != nil {
+			return status, fmt.Errorf("release: %w", err)
+		}
+		if ok {
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, fmt.Errorf("release: gave up waiting: %w", ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// reviewDone reports whether version has left review, failing if it was
+// rejected.
+func reviewDone(status *chromewebstore.ItemStatus, version string) (bool, error) {
+	if revisionOf(status.PublishedItemRevisionStatus).version == version {
+		return true, nil
+	}
+	submitted := revisionOf(status.SubmittedItemRevisionStatus)
+	if submitted.version != version {
+		return false, nil
+	}
+	switch submitted.state {
+	case chromewebstore.ItemStatePendingReview:
+		return false, nil
+	case chromewebstore.ItemStateRejected:
+		return false, fmt.Errorf("version %s was rejected", version)
+	case chromewebstore.ItemStateCancelled:
+		return false, fmt.Errorf("submission of version %s was cancelled", version)
+	}
+	return true, nil
+}
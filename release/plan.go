@@ -0,0 +1,158 @@
+package release
+
+import (
+	"fmt"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/H0R15H0/chrome-webstore-api-v2/manifest"
+)
+
+// ActionType identifies an action of a Plan.
+type ActionType string
+
+const (
+	// ActionUpload uploads the package.
+	ActionUpload ActionType = "upload"
+	// ActionPublish submits the uploaded version for publishing.
+	ActionPublish ActionType = "publish"
+	// ActionSetDeployPercentage raises the published deploy percentage.
+	ActionSetDeployPercentage ActionType = "set-deploy-percentage"
+)
+
+// Action is a single step required to reach the spec.
+type Action struct {
+	// Type is the kind of action.
+	Type ActionType `json:"type"`
+	// Description is a human-readable description of the action.
+	Description string `json:"description"`
+}
+
+// Plan is the list of actions that bring an item to the state described
+// by its spec.
+type Plan struct {
+	// Item is the item the plan applies to.
+	Item chromewebstore.ItemName `json:"item"`
+	// Version is the target version.
+	Version string `json:"version"`
+	// Actions are the actions to execute, in order.
+	Actions []Action `json:"actions"`
+	// Notes explain parts of the spec that cannot be acted on yet, such as
+	// a version that is still in review.
+	Notes []string `json:"notes,omitempty"`
+
+	spec *Spec
+}
+
+// Empty reports whether the plan has no actions.
+func (p *Plan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+// NewPlan compares spec with the live status of item and returns the
+// actions required to reach it. packageVersion is the manifest version of
+// the spec's package, or empty if the spec has no package.
+func NewPlan(spec *Spec, item chromewebstore.ItemName, status *chromewebstore.ItemStatus, packageVersion string) (*Plan, error) {
+	submitted := revisionOf(status.SubmittedItemRevisionStatus)
+	published := revisionOf(status.PublishedItemRevisionStatus)
+
+	version := spec.Version
+	if version == "" {
+		version = packageVersion
+	} else if packageVersion != "" && packageVersion != version {
+		return nil, fmt.Errorf("release: package version %s does not match spec version %s", packageVersion, version)
+	}
+	if version == "" {
+		version = published.version
+	}
+	if version == "" {
+		return nil, fmt.Errorf("release: no version: the spec has no version or package and the item is not published")
+	}
+
+	p := &Plan{Item: item, Version: version, spec: spec}
+	isPublished := published.version == version
+	isSubmitted := submitted.version == version
+
+	if !isPublished && !isSubmitted {
+		if spec.Package == "" {
+			return nil, fmt.Errorf("release: version %s is not in the store and the spec has no package", version)
+		}
+		if highest := manifest.HighestVersion(submitted.version, published.version); highest != nil {
+			v, err := manifest.ParseVersion(version)
+			if err != nil {
+				return nil, fmt.Errorf("release: %w", err)
+			}
+			if v.Compare(highest) <= 0 {
+				return nil, fmt.Errorf("release: version %s must be greater than the store version %s", version, highest)
+			}
+		}
+		p.add(ActionUpload, "upload %s (version %s)", spec.Package, version)
+	}
+
+	publishType := spec.publishType()
+	needsPublish := false
+	if publishType != PublishNone && !isPublished {
+		switch {
+		case !isSubmitted, submitted.state == chromewebstore.ItemStateCancelled:
+			needsPublish = true
+		case submitted.state == chromewebstore.ItemStatePendingReview:
+			p.Notes = append(p.Notes, fmt.Sprintf("version %s is pending review", version))
+		case submitted.state == chromewebstore.ItemStateStaged && publishType == PublishStaged:
+			p.Notes = append(p.Notes, fmt.Sprintf("version %s is staged", version))
+		case submitted.state == chromewebstore.ItemStateStaged:
+			needsPublish = true
+		case submitted.state == chromewebstore.ItemStateRejected:
+			return nil, fmt.Errorf("release: version %s was rejected; upload a new version", version)
+		default:
+			needsPublish = true
+		}
+	}
+
+	if needsPublish {
+		desc := fmt.Sprintf("publish %s (%s", version, publishType)
+		if spec.SkipReview {
+			desc += ", skip review"
+		}
+		if spec.DeployPercentage > 0 {
+			desc += fmt.Sprintf(", deploy percentage %d%%", spec.DeployPercentage)
+		}
+		p.add(ActionPublish, "%s)", desc)
+	}
+
+	if spec.DeployPercentage > 0 && !needsPublish {
+		switch {
+		case !isPublished:
+			if publishType != PublishNone {
+				p.Notes = append(p.Notes, fmt.Sprintf("deploy percentage %d%% will be set once version %s is published", spec.DeployPercentage, version))
+			}
+		case published.percentage < spec.DeployPercentage:
+			p.add(ActionSetDeployPercentage, "raise deploy percentage of %s from %d%% to %d%%", version, published.percentage, spec.DeployPercentage)
+		case published.percentage > spec.DeployPercentage:
+			p.Notes = append(p.Notes, fmt.Sprintf("deploy percentage is %d%%, which is above %d%% and cannot be decreased", published.percentage, spec.DeployPercentage))
+		}
+	}
+
+	return p, nil
+}
+
+func (p *Plan) add(t ActionType, format string, args ...any) {
+	p.Actions = append(p.Actions, Action{Type: t, Description: fmt.Sprintf(format, args...)})
+}
+
+// revision is the state, version and deploy percentage of an item revision.
+type revision struct {
+	state      chromewebstore.ItemState
+	version    string
+	percentage int
+}
+
+func revisionOf(r *chromewebstore.ItemRevisionStatus) revision {
+	if r == nil {
+		return revision{}
+	}
+	rev := revision{state: r.State}
+	if len(r.DistributionChannels) > 0 {
+		rev.version = r.DistributionChannels[0].CrxVersion
+		rev.percentage = r.DistributionChannels[0].DeployPercentage
+	}
+	return rev
+}
@@ -0,0 +1,300 @@
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+var testItem = chromewebstore.NewItemName("pub", testItemID)
+
+func status(submitted, published *chromewebstore.ItemRevisionStatus) *chromewebstore.ItemStatus {
+	return &chromewebstore.ItemStatus{
+		SubmittedItemRevisionStatus: submitted,
+		PublishedItemRevisionStatus: published,
+	}
+}
+
+func revisionStatus(state chromewebstore.ItemState, version string, percentage int) *chromewebstore.ItemRevisionStatus {
+	return &chromewebstore.ItemRevisionStatus{
+		State:                state,
+		DistributionChannels: []chromewebstore.DistributionChannel{{CrxVersion: version, DeployPercentage: percentage}},
+	}
+}
+
+func actionTypes(p *Plan) []string {
+	var types []string
+	for _, a := range p.Actions {
+		types = append(types, string(a.Type))
+	}
+	return types
+}
+
+func TestNewPlan(t *testing.T) {
+	published10 := revisionStatus(chromewebstore.ItemStatePublished, "1.0", 100)
+
+	tests := []struct {
+		name    string
+		spec    Spec
+		status  *chromewebstore.ItemStatus
+		pkg     string
+		actions string
+		notes   int
+		err     string
+	}{
+		{
+			name:    "new version",
+			spec:    Spec{Package: "ext.zip", DeployPercentage: 10},
+			status:  status(nil, published10),
+			pkg:     "1.1",
+			actions: "upload,publish",
+		},
+		{
+			name:    "percentage only",
+			spec:    Spec{PublishType: PublishNone, DeployPercentage: 50},
+			status:  status(revisionStatus(chromewebstore.ItemStateStaged, "1.2", 0), revisionStatus(chromewebstore.ItemStatePublished, "1.1", 10)),
+			actions: "set-deploy-percentage",
+		},
+		{
+			name:   "pending review",
+			spec:   Spec{Version: "1.1", DeployPercentage: 10},
+			status: status(revisionStatus(chromewebstore.ItemStatePendingReview, "1.1", 0), published10),
+			notes:  2,
+		},
+		{
+			name:    "publish staged",
+			spec:    Spec{Version: "1.1"},
+			status:  status(revisionStatus(chromewebstore.ItemStateStaged, "1.1", 0), published10),
+			actions: "publish",
+		},
+		{
+			name:   "already staged",
+			spec:   Spec{Version: "1.1", PublishType: PublishStaged},
+			status: status(revisionStatus(chromewebstore.ItemStateStaged, "1.1", 0), published10),
+			notes:  1,
+		},
+		{
+			name:    "raise percentage",
+			spec:    Spec{Version: "1.1", DeployPercentage: 50},
+			status:  status(nil, revisionStatus(chromewebstore.ItemStatePublished, "1.1", 10)),
+			actions: "set-deploy-percentage",
+		},
+		{
+			name:    "percentage of published version",
+			spec:    Spec{DeployPercentage: 50},
+			status:  status(nil, revisionStatus(chromewebstore.ItemStatePublished, "1.1", 10)),
+			actions: "set-deploy-percentage",
+		},
+		{
+			name:   "up to date",
+			spec:   Spec{Package: "ext.zip", DeployPercentage: 50},
+			status: status(nil, revisionStatus(chromewebstore.ItemStatePublished, "1.1", 50)),
+			pkg:    "1.1",
+		},
+		{
+			name:   "cannot decrease",
+			spec:   Spec{Version: "1.1", DeployPercentage: 10},
+			status: status(nil, revisionStatus(chromewebstore.ItemStatePublished, "1.1", 50)),
+			notes:  1,
+		},
+		{
+			name:   "rejected",
+			spec:   Spec{Version: "1.1"},
+			status: status(revisionStatus(chromewebstore.ItemStateRejected, "1.1", 0), published10),
+			err:    "was rejected",
+		},
+		{
+			name:   "version mismatch",
+			spec:   Spec{Version: "1.2", Package: "ext.zip"},
+			status: status(nil, published10),
+			pkg:    "1.1",
+			err:    "does not match",
+		},
+		{
+			name:   "missing package",
+			spec:   Spec{Version: "1.1"},
+			status: status(nil, published10),
+			err:    "has no package",
+		},
+		{
+			name:   "not newer",
+			spec:   Spec{Package: "ext.zip"},
+			status: status(nil, published10),
+			pkg:    "0.9",
+			err:    "must be greater",
+		},
+	}
+
+	for _, tt := range tests {
+		tt.spec.Item = testItemID
+		p, err := NewPlan(&tt.spec, testItem, tt.status, tt.pkg)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got := strings.Join(actionTypes(p), ","); got != tt.actions {
+			t.Errorf("%s: expected actions %q, got %q", tt.name, tt.actions, got)
+		}
+		if len(p.Notes) != tt.notes {
+			t.Errorf("%s: expected %d notes, got %v", tt.name, tt.notes, p.Notes)
+		}
+	}
+}
+
+// fakeStore simulates an item that is approved on the first status poll
+// after publishing.
+type fakeStore struct {
+	mu     sync.Mutex
+	status chromewebstore.ItemStatus
+	draft  string
+	calls  []string
+}
+
+func (s *fakeStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var resp any
+	switch {
+	case strings.HasSuffix(r.URL.Path, ":upload"):
+		s.calls = append(s.calls, "upload")
+		s.draft = "1.1"
+		resp = chromewebstore.UploadResponse{UploadState: chromewebstore.UploadStateSucceeded, CrxVersion: s.draft}
+	case strings.HasSuffix(r.URL.Path, ":publish"):
+		s.calls = append(s.calls, "publish")
+		s.status.SubmittedItemRevisionStatus = revisionStatus(chromewebstore.ItemStatePendingReview, s.draft, 0)
+		resp = chromewebstore.PublishResponse{State: chromewebstore.ItemStatePendingReview}
+	case strings.HasSuffix(r.URL.Path, ":setPublishedDeployPercentage"):
+		percentage, _ := strconv.Atoi(r.URL.Query().Get("deployPercentage"))
+		s.calls = append(s.calls, "set-deploy-percentage")
+		s.status.PublishedItemRevisionStatus.DistributionChannels[0].DeployPercentage = percentage
+		resp = chromewebstore.SetPublishedDeployPercentageResponse{}
+	case strings.HasSuffix(r.URL.Path, ":fetchStatus"):
+		resp = s.status
+		if sub := s.status.SubmittedItemRevisionStatus; sub != nil && sub.State == chromewebstore.ItemStatePendingReview {
+			s.status.PublishedItemRevisionStatus = revisionStatus(chromewebstore.ItemStatePublished, sub.DistributionChannels[0].CrxVersion, 10)
+			s.status.SubmittedItemRevisionStatus = nil
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func TestApply(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(`{"manifest_version": 3, "name": "Test", "version": "1.1"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := &fakeStore{status: *status(nil, revisionStatus(chromewebstore.ItemStatePublished, "1.0", 100))}
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	client := chromewebstore.NewClient(nil)
+	client.SetBaseURL(server.URL)
+	client.SetUploadBaseURL(server.URL)
+
+	spec := &Spec{
+		Item:             testItemID,
+		Package:          dir,
+		DeployPercentage: 50,
+		Wait:             Wait{For: WaitPublished, Interval: Duration(time.Millisecond)},
+	}
+
+	version, err := PackageVersion(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	current, err := client.Publishers.Items.FetchStatus(testItem).Do()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p, err := NewPlan(spec, testItem, current, version)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var applied []ActionType
+	a := &Applier{Client: client, OnAction: func(action Action, result any) {
+		applied = append(applied, action.Type)
+	}}
+	if err := a.Apply(context.Background(), p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := strings.Join(store.calls, ","); got != "upload,publish,set-deploy-percentage" {
+		t.Errorf("unexpected calls: %s", got)
+	}
+	if len(applied) != 3 {
+		t.Errorf("expected 3 applied actions, got %v", applied)
+	}
+
+	current, err = client.Publishers.Items.FetchStatus(testItem).Do()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again, err := NewPlan(spec, testItem, current, version)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !again.Empty() {
+		t.Errorf("expected re-running to be a no-op, got %v", actionTypes(again))
+	}
+}
+
+func TestApplyPublishNone(t *testing.T) {
+	store := &fakeStore{status: *status(nil, revisionStatus(chromewebstore.ItemStatePublished, "1.1", 10))}
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	client := chromewebstore.NewClient(nil)
+	client.SetBaseURL(server.URL)
+
+	spec := &Spec{Item: testItemID, PublishType: PublishNone, DeployPercentage: 50}
+	if err := spec.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	plan := func() *Plan {
+		current, err := client.Publishers.Items.FetchStatus(testItem).Do()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		p, err := NewPlan(spec, testItem, current, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return p
+	}
+
+	if err := (&Applier{Client: client}).Apply(context.Background(), plan()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again := plan(); !again.Empty() {
+		t.Errorf("expected re-running to be a no-op, got %v", actionTypes(again))
+	}
+	if got := strings.Join(store.calls, ","); got != "set-deploy-percentage" {
+		t.Errorf("unexpected calls: %s", got)
+	}
+}
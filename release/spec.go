@@ -0,0 +1,308 @@
+// Package release implements declarative release specs: a description of
+// the desired state of an item that is compared with its live status to
+// plan and apply the required upload, publish and deploy percentage actions.
+package release
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/H0R15H0/chrome-webstore-api-v2/manifest"
+)
+
+// Publish types accepted in Spec.PublishType.
+const (
+	// PublishDefault publishes immediately after approval.
+	PublishDefault = "default"
+	// PublishStaged stages the item after approval.
+	PublishStaged = "staged"
+	// PublishNone does not submit a version, so only the deploy percentage
+	// of the published version is raised. It cannot be combined with a
+	// package: the store does not report the version of an upload that was
+	// not submitted, so every run would upload it again.
+	PublishNone = "none"
+)
+
+// WaitFor selects how far Apply follows a release after its actions.
+type WaitFor string
+
+const (
+	// WaitNone returns as soon as the actions are done.
+	WaitNone WaitFor = "none"
+	// WaitReview waits until the submitted version leaves review.
+	WaitReview WaitFor = "review"
+	// WaitPublished waits until the version is published and the deploy
+	// percentage has been applied.
+	WaitPublished WaitFor = "published"
+)
+
+// DefaultWaitInterval is the polling interval used when Wait.Interval is zero.
+const DefaultWaitInterval = 30 * time.Second
+
+// Spec is the desired state of an item.
+type Spec struct {
+	// Publisher is the publisher ID. If empty, the caller's default is used.
+	Publisher string `json:"publisher,omitempty"`
+	// Item is the item ID.
+	Item string `json:"item"`
+	// Package is the ZIP file, CRX file or directory to upload when the
+	// version is not yet in the store. Relative paths are resolved against
+	// the directory of the spec file.
+	Package string `json:"package,omitempty"`
+	// Version is the expected version. If empty, the package's manifest
+	// version is used; if there is no package either, the published version.
+	Version string `json:"version,omitempty"`
+	// PublishType is PublishDefault, PublishStaged or PublishNone.
+	// If empty, PublishDefault is used.
+	PublishType string `json:"publishType,omitempty"`
+	// SkipReview requests that the submission skip review if eligible.
+	SkipReview bool `json:"skipReview,omitempty"`
+	// DeployPercentage is the target deploy percentage (1-100).
+	// If zero, the deploy percentage is not managed.
+	DeployPercentage int `json:"deployPercentage,omitempty"`
+	// Wait is the wait policy.
+	Wait Wait `json:"wait,omitzero"`
+}
+
+// Wait describes how long Apply follows a release.
+type Wait struct {
+	// For is the milestone to wait for. If empty, WaitNone is used.
+	For WaitFor `json:"for,omitempty"`
+	// Timeout bounds the wait. If zero, the wait is unbounded.
+	Timeout Duration `json:"timeout,omitempty"`
+	// Interval is the polling interval. If zero, DefaultWaitInterval is used.
+	Interval Duration `json:"interval,omitempty"`
+}
+
+// Duration is a time.Duration encoded as a string such as "30m".
+type Duration time.Duration
+
+// MarshalJSON encodes d as a duration string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON decodes a duration string.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30m\"")
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Parse parses a spec in JSON or in the YAML subset described in ParseYAML.
+// Documents starting with "{" are treated as JSON.
+func Parse(data []byte) (*Spec, error) {
+	spec := &Spec{}
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("{")) {
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(spec); err != nil {
+			return nil, fmt.Errorf("release: failed to parse spec: %w", err)
+		}
+	} else {
+		doc, err := ParseYAML(data)
+		if err != nil {
+			return nil, err
+		}
+		if err := spec.fromYAML(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// Load reads and parses the spec file at path and resolves Package relative
+// to the file's directory.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("release: failed to read spec: %w", err)
+	}
+	spec, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if spec.Package != "" && !filepath.IsAbs(spec.Package) {
+		spec.Package = filepath.Join(filepath.Dir(path), spec.Package)
+	}
+	return spec, nil
+}
+
+// Validate checks the spec for invalid values.
+func (s *Spec) Validate() error {
+	if s.Item == "" {
+		return fmt.Errorf("release: item is required")
+	}
+	if err := chromewebstore.ValidateItemID(s.Item); err != nil {
+		return fmt.Errorf("release: %w", err)
+	}
+	if s.Version != "" {
+		if _, err := manifest.ParseVersion(s.Version); err != nil {
+			return fmt.Errorf("release: %w", err)
+		}
+	}
+	switch s.PublishType {
+	case "", PublishDefault, PublishStaged, PublishNone:
+	default:
+		return fmt.Errorf("release: invalid publishType %q: must be default, staged or none", s.PublishType)
+	}
+	if s.PublishType == PublishNone && s.Package != "" {
+		return fmt.Errorf("release: publishType none cannot be combined with a package; use cws upload to upload without publishing")
+	}
+	if s.DeployPercentage < 0 || s.DeployPercentage > 100 {
+		return fmt.Errorf("release: invalid deployPercentage %d: must be between 1 and 100", s.DeployPercentage)
+	}
+	switch s.Wait.For {
+	case "", WaitNone, WaitReview, WaitPublished:
+	default:
+		return fmt.Errorf("release: invalid wait.for %q: must be none, review or published", s.Wait.For)
+	}
+	if s.Wait.For == WaitReview && s.publishType() == PublishNone {
+		return fmt.Errorf("release: wait.for review requires publishType default or staged")
+	}
+	if s.Wait.For == WaitPublished && s.publishType() != PublishDefault {
+		return fmt.Errorf("release: wait.for published requires publishType default")
+	}
+	return nil
+}
+
+// ItemName returns the resource name of the item, using defaultPublisher if
+// the spec does not name a publisher.
+func (s *Spec) ItemName(defaultPublisher string) (chromewebstore.ItemName, error) {
+	publisher := s.Publisher
+	if publisher == "" {
+		publisher = defaultPublisher
+	}
+	if publisher == "" {
+		return "", fmt.Errorf("release: publisher is required")
+	}
+	return chromewebstore.NewItemName(publisher, s.Item), nil
+}
+
+func (s *Spec) publishType() string {
+	if s.PublishType == "" {
+		return PublishDefault
+	}
+	return s.PublishType
+}
+
+func (s *Spec) waitFor() WaitFor {
+	if s.Wait.For == "" {
+		return WaitNone
+	}
+	return s.Wait.For
+}
+
+// fromYAML fills the spec from a parsed YAML document.
+func (s *Spec) fromYAML(doc map[string]any) error {
+	for key, value := range doc {
+		var err error
+		switch key {
+		case "publisher":
+			s.Publisher, err = yamlString(key, value)
+		case "item":
+			s.Item, err = yamlString(key, value)
+		case "package":
+			s.Package, err = yamlString(key, value)
+		case "version":
+			s.Version, err = yamlString(key, value)
+		case "publishType":
+			s.PublishType, err = yamlString(key, value)
+		case "skipReview":
+			s.SkipReview, err = yamlBool(key, value)
+		case "deployPercentage":
+			s.DeployPercentage, err = yamlInt(key, value)
+		case "wait":
+			m, ok := value.(map[string]any)
+			if !ok {
+				return fmt.Errorf("release: wait must be a mapping")
+			}
+			err = s.Wait.fromYAML(m)
+		default:
+			err = fmt.Errorf("release: unknown field %q", key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fromYAML fills the wait policy from a parsed YAML mapping.
+func (w *Wait) fromYAML(doc map[string]any) error {
+	for key, value := range doc {
+		s, err := yamlString("wait."+key, value)
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "for":
+			w.For = WaitFor(s)
+		case "timeout", "interval":
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return fmt.Errorf("release: invalid wait.%s: %w", key, err)
+			}
+			if key == "timeout" {
+				w.Timeout = Duration(d)
+			} else {
+				w.Interval = Duration(d)
+			}
+		default:
+			return fmt.Errorf("release: unknown field %q", "wait."+key)
+		}
+	}
+	return nil
+}
+
+func yamlString(key string, value any) (string, error) {
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("release: %s must be a scalar", key)
+	}
+	return s, nil
+}
+
+func yamlBool(key string, value any) (bool, error) {
+	s, err := yamlString(key, value)
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(s) {
+	case "true", "yes", "on":
+		return true, nil
+	case "false", "no", "off", "":
+		return false, nil
+	}
+	return false, fmt.Errorf("release: %s must be true or false", key)
+}
+
+func yamlInt(key string, value any) (int, error) {
+	s, err := yamlString(key, value)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("release: %s must be an integer", key)
+	}
+	return n, nil
+}
@@ -0,0 +1,103 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testItemID = "abcdefghijklmnopabcdefghijklmnop"
+
+func TestParseYAML(t *testing.T) {
+	data := `---
+# Release of the stable channel
+item: abcdefghijklmnopabcdefghijklmnop
+package: "dist/extension.zip"   # built by CI
+version: '1.10'
+publishType: staged
+skipReview: true
+deployPercentage: 25
+wait:
+  for: review
+  timeout: 2h
+  interval: 1m
+`
+	spec, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if spec.Item != testItemID {
+		t.Errorf("expected item %s, got %s", testItemID, spec.Item)
+	}
+	if spec.Package != "dist/extension.zip" {
+		t.Errorf("expected package dist/extension.zip, got %s", spec.Package)
+	}
+	if spec.Version != "1.10" {
+		t.Errorf("expected version 1.10, got %s", spec.Version)
+	}
+	if spec.PublishType != PublishStaged || !spec.SkipReview || spec.DeployPercentage != 25 {
+		t.Errorf("unexpected publish settings: %+v", spec)
+	}
+	if spec.Wait.For != WaitReview || time.Duration(spec.Wait.Timeout) != 2*time.Hour || time.Duration(spec.Wait.Interval) != time.Minute {
+		t.Errorf("unexpected wait policy: %+v", spec.Wait)
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	data := `{"item": "abcdefghijklmnopabcdefghijklmnop", "version": "2.0", "wait": {"for": "published", "timeout": "30m"}}`
+	spec, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if spec.Version != "2.0" || spec.Wait.For != WaitPublished || time.Duration(spec.Wait.Timeout) != 30*time.Minute {
+		t.Errorf("unexpected spec: %+v", spec)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"version: 1.0\n", "item is required"},
+		{"item: abc\n", "invalid item ID"},
+		{"item: " + testItemID + "\nversion: 1.01\n", "leading zero"},
+		{"item: " + testItemID + "\npublishType: later\n", "invalid publishType"},
+		{"item: " + testItemID + "\npackage: ext.zip\npublishType: none\n", "publishType none cannot be combined with a package"},
+		{"item: " + testItemID + "\ndeployPercentage: 150\n", "invalid deployPercentage"},
+		{"item: " + testItemID + "\ndeployPercentage: half\n", "must be an integer"},
+		{"item: " + testItemID + "\nchannel: beta\n", "unknown field \"channel\""},
+		{"item: " + testItemID + "\nwait:\n  for: lunch\n", "invalid wait.for"},
+		{"item: " + testItemID + "\npublishType: staged\nwait:\n  for: published\n", "requires publishType default"},
+		{"item: " + testItemID + "\nitem: " + testItemID + "\n", "duplicate key"},
+		{"item: " + testItemID + "\npackages:\n  - a.zip\n", "sequences are not supported"},
+		{"item: " + testItemID + "\n  version: 1.0\n", "unexpected indentation"},
+		{`{"item": "` + testItemID + `", "channel": "beta"}`, "unknown field"},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse([]byte(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q): expected error containing %q, got %v", tt.data, tt.want, err)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "release.yaml")
+	if err := os.WriteFile(path, []byte("item: "+testItemID+"\npackage: dist\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := filepath.Join(dir, "dist"); spec.Package != expected {
+		t.Errorf("expected package %s, got %s", expected, spec.Package)
+	}
+}
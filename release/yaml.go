@@ -0,0 +1,156 @@
+package release
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// yamlLine is a non-empty, non-comment line of a YAML document.
+type yamlLine struct {
+	num    int
+	indent int
+	key    string
+	value  string
+	// nested is true when the key has no inline value.
+	nested bool
+}
+
+// ParseYAML parses the YAML subset used by release specs: nested block
+// mappings of scalars indented with spaces, "#" comments and plain,
+// single-quoted or double-quoted scalars. Sequences, flow collections,
+// multi-line scalars, anchors and tags are not supported. All scalars are
+// returned as strings, and nested mappings as map[string]any.
+func ParseYAML(data []byte) (map[string]any, error) {
+	var lines []yamlLine
+	for i, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		num := i + 1
+		text := strings.TrimRight(stripYAMLComment(raw), " \t")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || (num == 1 && trimmed == "---") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("release: line %d: tabs are not allowed for indentation", num)
+		}
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			return nil, fmt.Errorf("release: line %d: sequences are not supported", num)
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok || (value != "" && value[0] != ' ' && value[0] != '\t') {
+			return nil, fmt.Errorf("release: line %d: expected \"key: value\"", num)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		unquotedKey, err := yamlScalar(key)
+		if err != nil {
+			return nil, fmt.Errorf("release: line %d: %w", num, err)
+		}
+		lines = append(lines, yamlLine{
+			num:    num,
+			indent: len(text) - len(trimmed),
+			key:    unquotedKey,
+			value:  value,
+			nested: value == "",
+		})
+	}
+
+	doc, rest, err := parseYAMLMapping(lines, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("release: line %d: unexpected indentation", rest[0].num)
+	}
+	return doc, nil
+}
+
+// parseYAMLMapping parses the mapping whose keys are indented by indent and
+// returns the lines following it.
+func parseYAMLMapping(lines []yamlLine, indent int) (map[string]any, []yamlLine, error) {
+	m := make(map[string]any)
+	for len(lines) > 0 {
+		line := lines[0]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, nil, fmt.Errorf("release: line %d: unexpected indentation", line.num)
+		}
+		lines = lines[1:]
+
+		if _, dup := m[line.key]; dup {
+			return nil, nil, fmt.Errorf("release: line %d: duplicate key %q", line.num, line.key)
+		}
+
+		if !line.nested {
+			value, err := yamlScalar(line.value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("release: line %d: %w", line.num, err)
+			}
+			m[line.key] = value
+			continue
+		}
+
+		if len(lines) == 0 || lines[0].indent <= indent {
+			m[line.key] = ""
+			continue
+		}
+		child, rest, err := parseYAMLMapping(lines, lines[0].indent)
+		if err != nil {
+			return nil, nil, err
+		}
+		m[line.key] = child
+		lines = rest
+	}
+	return m, lines, nil
+}
+
+// yamlScalar returns the value of a single-line scalar.
+func yamlScalar(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return "", fmt.Errorf("invalid double-quoted string %s", s)
+		}
+		return v, nil
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", fmt.Errorf("invalid single-quoted string %s", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	case strings.HasPrefix(s, "{"), strings.HasPrefix(s, "["):
+		return "", fmt.Errorf("flow collections are not supported")
+	case strings.HasPrefix(s, "&"), strings.HasPrefix(s, "*"), strings.HasPrefix(s, "!"),
+		strings.HasPrefix(s, "|"), strings.HasPrefix(s, ">"):
+		return "", fmt.Errorf("unsupported YAML syntax %q", s)
+	case s == "~" || s == "null":
+		return "", nil
+	}
+	return s, nil
+}
+
+// stripYAMLComment removes a trailing "#" comment that is outside quotes
+// and preceded by whitespace or at the start of the line.
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,118 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+// Gate is a health check evaluated before a deploy percentage change.
+// Check returns nil when the gate passes and an error describing
+// the failure otherwise.
+type Gate interface {
+	// Name returns a short human-readable description of the gate.
+	Name() string
+	// Check evaluates the gate.
+	Check(ctx context.Context) error
+}
+
+// HTTPGate passes when a GET request to URL returns a 2xx status code.
+type HTTPGate struct {
+	// URL is the health endpoint to query.
+	URL string
+	// Client is the HTTP client used for the request.
+	// If nil, http.DefaultClient is used.
+	Client *http.Client
+}
+
+// Name returns a description of the gate.
+func (g *HTTPGate) Name() string {
+	return "http " + g.URL
+}
+
+// Check performs the HTTP request.
+func (g *HTTPGate) Check(ctx context.Context) error {
+	client := g.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.URL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if msg := strings.TrimSpace(string(body)); msg != "" {
+			return fmt.Errorf("HTTP %d: %s", resp.StatusCode, msg)
+		}
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// CommandGate passes when a shell command exits with status 0.
+type CommandGate struct {
+	// Command is executed with "sh -c".
+	Command string
+}
+
+// Name returns a description of the gate.
+func (g *CommandGate) Name() string {
+	return "command " + g.Command
+}
+
+// Check runs the command.
+func (g *CommandGate) Check(ctx context.Context) error {
+	output, err := exec.CommandContext(ctx, "sh", "-c", g.Command).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// ItemStatusGate passes when the item is neither warned nor taken down.
+type ItemStatusGate struct {
+	// Items is the service used to fetch the item status.
+	Items *chromewebstore.ItemsService
+	// Item is the item to check.
+	Item chromewebstore.ItemName
+}
+
+// Name returns a description of the gate.
+func (g *ItemStatusGate) Name() string {
+	return "item status " + g.Item.String()
+}
+
+// Check fetches the item status and inspects its policy flags.
+func (g *ItemStatusGate) Check(ctx context.Context) error {
+	status, err := g.Items.FetchStatus(g.Item).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	if status.TakenDown {
+		return fmt.Errorf("item is taken down")
+	}
+	if status.Warned {
+		return fmt.Errorf("item has a policy violation warning")
+	}
+
+	return nil
+}
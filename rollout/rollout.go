@@ -0,0 +1,195 @@
+// Package rollout provides health-gated progression of published deploy percentages.
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+// GateResult is the outcome of a single gate evaluation.
+type GateResult struct {
+	// Gate is the name of the gate.
+	Gate string `json:"gate"`
+	// Passed reports whether the gate passed.
+	Passed bool `json:"passed"`
+	// Message describes the failure when Passed is false.
+	Message string `json:"message,omitempty"`
+}
+
+// Step is the record of a single deploy percentage step.
+type Step struct {
+	// Percentage is the deploy percentage of the step.
+	Percentage int `json:"percentage"`
+	// Checks contains the gate results of every check round for the step.
+	Checks [][]GateResult `json:"checks,omitempty"`
+	// Applied reports whether the deploy percentage was set.
+	Applied bool `json:"applied"`
+}
+
+// Report summarizes a rollout run.
+type Report struct {
+	// Item is the item being rolled out.
+	Item chromewebstore.ItemName `json:"item"`
+	// Steps contains the steps attempted so far.
+	Steps []Step `json:"steps"`
+}
+
+// GateFailedError is returned when a gate fails before a step is applied.
+type GateFailedError struct {
+	// Percentage is the deploy percentage that was not applied.
+	Percentage int
+	// Results contains the gate results of the failed check round.
+	Results []GateResult
+}
+
+// Error returns the error message.
+func (e *GateFailedError) Error() string {
+	var failed []string
+	for _, r := range e.Results {
+		if !r.Passed {
+			failed = append(failed, fmt.Sprintf("%s: %s", r.Gate, r.Message))
+		}
+	}
+	return fmt.Sprintf("rollout: halted before %d%%: %s", e.Percentage, strings.Join(failed, "; "))
+}
+
+// Rollout raises the published deploy percentage of an item step by step,
+// evaluating gates before each change.
+type Rollout struct {
+	// Items is the service used to set the deploy percentage.
+	Items *chromewebstore.ItemsService
+	// Item is the item to roll out.
+	Item chromewebstore.ItemName
+	// Gates are evaluated before each step. All gates must pass.
+	Gates []Gate
+	// ConsecutivePasses is the number of consecutive passing check rounds
+	// required before each step. Values below 1 are treated as 1.
+	ConsecutivePasses int
+	// CheckInterval is the delay between check rounds.
+	CheckInterval time.Duration
+	// Validate enables pre-flight validation of each step against the
+	// current published deploy percentage.
+	Validate bool
+	// OnCheck, if set, is called after every check round.
+	OnCheck func(percentage int, results []GateResult)
+}
+
+// ValidateSteps checks that steps is non-empty, that every step is within
+// 1-100 and that the steps are strictly increasing.
+func ValidateSteps(steps []int) error {
+	if len(steps) == 0 {
+		return fmt.Errorf("rollout: at least one step is required")
+	}
+	for i, percentage := range steps {
+		if percentage < 1 || percentage > 100 {
+			return fmt.Errorf("rollout: step %d%% must be between 1 and 100", percentage)
+		}
+		if i > 0 && percentage <= steps[i-1] {
+			return fmt.Errorf("rollout: step %d%% must be above the previous step %d%%", percentage, steps[i-1])
+		}
+	}
+	return nil
+}
+
+// Run applies each deploy percentage in steps in order. It halts with a
+// *GateFailedError as soon as any gate fails. The returned report describes
+// the steps attempted, including the failed one.
+//
+// Before any gate is evaluated, the steps are checked with ValidateSteps
+// and, if Validate is set, the first step is checked to be above the
+// current published deploy percentage.
+func (r *Rollout) Run(ctx context.Context, steps []int) (*Report, error) {
+	report := &Report{Item: r.Item}
+	if err := ValidateSteps(steps); err != nil {
+		return report, err
+	}
+	if r.Validate {
+		status, err := r.Items.FetchStatus(r.Item).Context(ctx).Do()
+		if err != nil {
+			return report, err
+		}
+		current, ok := status.PublishedDeployPercentage()
+		if !ok {
+			return report, fmt.Errorf("rollout: %s has no published revision", r.Item)
+		}
+		if steps[0] <= current {
+			return report, fmt.Errorf("rollout: step %d%% must be above the current deploy percentage %d%%", steps[0], current)
+		}
+	}
+
+	required := r.ConsecutivePasses
+	if required < 1 {
+		required = 1
+	}
+
+	first := true
+	for _, percentage := range steps {
+		report.Steps = append(report.Steps, Step{Percentage: percentage})
+		step := &report.Steps[len(report.Steps)-1]
+
+		for passes := 0; passes < required; passes++ {
+			if !first {
+				if err := sleep(ctx, r.CheckInterval); err != nil {
+					return report, err
+				}
+			}
+			first = false
+
+			results, ok := r.evaluate(ctx)
+			step.Checks = append(step.Checks, results)
+			if r.OnCheck != nil {
+				r.OnCheck(percentage, results)
+			}
+			if !ok {
+				return report, &GateFailedError{Percentage: percentage, Results: results}
+			}
+		}
+
+		_, err := r.Items.SetPublishedDeployPercentage(r.Item).
+			Context(ctx).
+			DeployPercentage(percentage).
+			Validate(r.Validate).
+			Do()
+		if err != nil {
+			return report, err
+		}
+		step.Applied = true
+	}
+
+	return report, nil
+}
+
+// evaluate runs every gate once and reports whether all of them passed.
+func (r *Rollout) evaluate(ctx context.Context) ([]GateResult, bool) {
+	results := make([]GateResult, 0, len(r.Gates))
+	ok := true
+	for _, g := range r.Gates {
+		result := GateResult{Gate: g.Name(), Passed: true}
+		if err := g.Check(ctx); err != nil {
+			result.Passed = false
+			result.Message = err.Error()
+			ok = false
+		}
+		results = append(results, result)
+	}
+	return results, ok
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
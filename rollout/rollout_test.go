@@ -0,0 +1,221 @@
+package rollout
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+type fakeGate struct {
+	results []error
+	calls   int
+}
+
+func (g *fakeGate) Name() string { return "fake" }
+
+func (g *fakeGate) Check(ctx context.Context) error {
+	err := g.results[g.calls%len(g.results)]
+	g.calls++
+	return err
+}
+
+func newTestClient(t *testing.T, applied *[]string) *chromewebstore.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, ":setPublishedDeployPercentage") {
+			*applied = append(*applied, r.URL.Query().Get("deployPercentage"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(chromewebstore.ItemStatus{Warned: true})
+	}))
+	t.Cleanup(server.Close)
+
+	client := chromewebstore.NewClient(nil)
+	client.SetBaseURL(server.URL)
+	return client
+}
+
+func TestRunAppliesAllSteps(t *testing.T) {
+	var applied []string
+	client := newTestClient(t, &applied)
+
+	gate := &fakeGate{results: []error{nil}}
+	r := &Rollout{
+		Items:             client.Publishers.Items,
+		Item:              chromewebstore.NewItemName("test-publisher", "test-item"),
+		Gates:             []Gate{gate},
+		ConsecutivePasses: 2,
+	}
+
+	report, err := r.Run(context.Background(), []int{10, 50, 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Join(applied, ",") != "10,50,100" {
+		t.Errorf("expected steps 10,50,100 to be applied, got %v", applied)
+	}
+
+	if gate.calls != 6 {
+		t.Errorf("expected 6 gate checks, got %d", gate.calls)
+	}
+
+	for _, step := range report.Steps {
+		if !step.Applied {
+			t.Errorf("expected step %d to be applied", step.Percentage)
+		}
+	}
+}
+
+func TestRunHaltsOnGateFailure(t *testing.T) {
+	var applied []string
+	client := newTestClient(t, &applied)
+
+	gate := &fakeGate{results: []error{nil, nil, errors.New("error rate too high")}}
+	r := &Rollout{
+		Items:             client.Publishers.Items,
+		Item:              chromewebstore.NewItemName("test-publisher", "test-item"),
+		Gates:             []Gate{gate},
+		ConsecutivePasses: 2,
+	}
+
+	report, err := r.Run(context.Background(), []int{10, 50})
+
+	var gateErr *GateFailedError
+	if !errors.As(err, &gateErr) {
+		t.Fatalf("expected *GateFailedError, got %v", err)
+	}
+
+	if gateErr.Percentage != 50 {
+		t.Errorf("expected halt before 50, got %d", gateErr.Percentage)
+	}
+
+	if !strings.Contains(err.Error(), "error rate too high") {
+		t.Errorf("expected error to contain gate message, got %v", err)
+	}
+
+	if strings.Join(applied, ",") != "10" {
+		t.Errorf("expected only step 10 to be applied, got %v", applied)
+	}
+
+	if len(report.Steps) != 2 || report.Steps[1].Applied {
+		t.Errorf("expected report to record unapplied second step, got %+v", report.Steps)
+	}
+}
+
+func TestHTTPGate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("unhealthy"))
+		}
+	}))
+	defer server.Close()
+
+	pass := &HTTPGate{URL: server.URL + "/ok"}
+	if err := pass.Check(context.Background()); err != nil {
+		t.Errorf("expected gate to pass, got %v", err)
+	}
+
+	fail := &HTTPGate{URL: server.URL + "/fail"}
+	err := fail.Check(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "unhealthy") {
+		t.Errorf("expected failure containing body, got %v", err)
+	}
+}
+
+func TestCommandGate(t *testing.T) {
+	if err := (&CommandGate{Command: "true"}).Check(context.Background()); err != nil {
+		t.Errorf("expected gate to pass, got %v", err)
+	}
+
+	if err := (&CommandGate{Command: "echo broken; exit 3"}).Check(context.Background()); err == nil {
+		t.Error("expected gate to fail")
+	}
+}
+
+func TestItemStatusGate(t *testing.T) {
+	var applied []string
+	client := newTestClient(t, &applied)
+
+	gate := &ItemStatusGate{
+		Items: client.Publishers.Items,
+		Item:  chromewebstore.NewItemName("test-publisher", "test-item"),
+	}
+
+	err := gate.Check(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "warning") {
+		t.Errorf("expected warned item to fail, got %v", err)
+	}
+}
+
+func TestRunRejectsInvalidSteps(t *testing.T) {
+	var applied []string
+	client := newTestClient(t, &applied)
+
+	for _, steps := range [][]int{nil, {10, 50, 30}, {10, 150}, {0, 50}, {50, 50}} {
+		gate := &fakeGate{results: []error{nil}}
+		r := &Rollout{
+			Items: client.Publishers.Items,
+			Item:  chromewebstore.NewItemName("test-publisher", "test-item"),
+			Gates: []Gate{gate},
+		}
+
+		if _, err := r.Run(context.Background(), steps); err == nil {
+			t.Errorf("expected an error for steps %v", steps)
+		}
+		if gate.calls != 0 {
+			t.Errorf("expected no gate checks for steps %v, got %d", steps, gate.calls)
+		}
+	}
+	if len(applied) != 0 {
+		t.Errorf("expected no steps to be applied, got %v", applied)
+	}
+}
+
+func TestRunRejectsStepsBelowCurrent(t *testing.T) {
+	var applied []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, ":setPublishedDeployPercentage") {
+			applied = append(applied, r.URL.Query().Get("deployPercentage"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(chromewebstore.ItemStatus{
+			PublishedItemRevisionStatus: &chromewebstore.ItemRevisionStatus{
+				State:                chromewebstore.ItemStatePublished,
+				DistributionChannels: []chromewebstore.DistributionChannel{{DeployPercentage: 20, CrxVersion: "1.0"}},
+			},
+		})
+	}))
+	defer server.Close()
+	client := chromewebstore.NewClient(nil)
+	client.SetBaseURL(server.URL)
+
+	gate := &fakeGate{results: []error{nil}}
+	r := &Rollout{
+		Items:    client.Publishers.Items,
+		Item:     chromewebstore.NewItemName("test-publisher", "test-item"),
+		Gates:    []Gate{gate},
+		Validate: true,
+	}
+
+	_, err := r.Run(context.Background(), []int{20, 50})
+	if err == nil || !strings.Contains(err.Error(), "above the current deploy percentage 20%") {
+		t.Fatalf("expected the first step to be rejected, got %v", err)
+	}
+	if gate.calls != 0 || len(applied) != 0 {
+		t.Errorf("expected no gate checks and no steps applied, got %d checks and %v", gate.calls, applied)
+	}
+
+	if _, err := r.Run(context.Background(), []int{30, 50}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(applied, ",") != "30,50" {
+		t.Errorf("expected steps 30,50 to be applied, got %v", applied)
+	}
+}
@@ -0,0 +1,246 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+// DefaultStaleAfter is the default time after which a running job is
+// considered abandoned by its runner.
+const DefaultStaleAfter = 15 * time.Minute
+
+// Runner executes due jobs of a store.
+type Runner struct {
+	// Items is the service used to fetch statuses and execute jobs.
+	Items *chromewebstore.ItemsService
+	// Store holds the jobs.
+	Store *Store
+	// MaxDelay skips jobs that are overdue by more than this duration,
+	// for example because no runner was active at their time. Zero runs
+	// overdue jobs regardless of their delay.
+	MaxDelay time.Duration
+	// StaleAfter is how long a job may stay running before it is
+	// considered abandoned, for example because its runner was killed.
+	// Abandoned jobs are marked failed, as their request may have been
+	// sent. If zero, DefaultStaleAfter is used.
+	StaleAfter time.Duration
+	// ID identifies the runner in the jobs it claims. If empty, the host
+	// name and process ID are used.
+	ID string
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+	// JobContext, if set, derives the context of the requests of a job
+	// from ctx, for example to attribute them to whoever scheduled it.
+	JobContext func(ctx context.Context, job Job) context.Context
+	// OnStatus, if set, is called with the item status fetched before a
+	// job runs, for example to report the changes the job made.
+	OnStatus func(job Job, status *chromewebstore.ItemStatus)
+	// OnJob, if set, is called after each job run with its new state.
+	OnJob func(job Job)
+}
+
+func (r *Runner) now() time.Time {
+	if r.Now != nil {
+		return r.Now()
+	}
+	return time.Now()
+}
+
+func (r *Runner) staleAfter() time.Duration {
+	if r.StaleAfter > 0 {
+		return r.StaleAfter
+	}
+	return DefaultStaleAfter
+}
+
+func (r *Runner) id() string {
+	if r.ID != "" {
+		return r.ID
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
+// RunDue claims the pending jobs that are due and runs them in order of
+// time. Before a job runs, the item's status is fetched; the job is
+// skipped if the item was taken down or its revisions changed since the
+// job was scheduled. Jobs whose status cannot be fetched are returned to
+// pending and retried by a later run. Running jobs abandoned by another
+// runner are marked failed and reported first. RunDue returns the jobs it
+// ran or failed.
+func (r *Runner) RunDue(ctx context.Context) ([]Job, error) {
+	jobs, abandoned, err := r.Store.claim(r.now(), r.id(), r.staleAfter())
+	if err != nil {
+		return nil, err
+	}
+	if r.OnJob != nil {
+		for _, job := range abandoned {
+			r.OnJob(job)
+		}
+	}
+	for i := range jobs {
+		if ctx.Err() != nil {
+			jobs[i].State = StatePending
+			jobs[i].StartedAt = time.Time{}
+			jobs[i].Runner = ""
+		} else {
+			jobs[i] = r.run(ctx, jobs[i])
+		}
+		if err := r.Store.put(jobs[i]); err != nil {
+			return jobs, err
+		}
+		if r.OnJob != nil && ctx.Err() == nil {
+			r.OnJob(jobs[i])
+		}
+	}
+	return append(abandoned, jobs...), ctx.Err()
+}
+
+// Run calls RunDue when jobs are due until ctx is done, checking the store
+// for new jobs at least every interval. Jobs returned to pending after a
+// failed status fetch are retried after interval rather than immediately.
+func (r *Runner) Run(ctx context.Context, interval time.Duration) error {
+	for {
+		if _, err := r.RunDue(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		wait := interval
+		jobs, err := r.Store.Jobs()
+		if err != nil {
+			return err
+		}
+		now := r.now()
+		for _, j := range jobs {
+			// Pending jobs that are already due were just retried by
+			// RunDue; waking up for them would poll in a tight loop.
+			if j.State == StatePending && j.At.After(now) {
+				wait = min(wait, j.At.Sub(now))
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// run runs a claimed job and returns it with its new state.
+func (r *Runner) run(ctx context.Context, job Job) Job {
+	finish := func(state State, err error) Job {
+		job.State = state
+		job.FinishedAt = r.now()
+		if err != nil {
+			job.Error = err.Error()
+		} else {
+			job.Error = ""
+		}
+		return job
+	}
+
+	if delay := r.now().Sub(job.At); r.MaxDelay > 0 && delay > r.MaxDelay {
+		return finish(StateSkipped, fmt.Errorf("missed by %s, more than the maximum delay of %s", delay.Round(time.Second), r.MaxDelay))
+	}
+	if job.PolicyOverride != nil {
+		ctx = chromewebstore.WithPolicyOverride(ctx, *job.PolicyOverride)
+	}
+	if r.JobContext != nil {
+		ctx = r.JobContext(ctx, job)
+	}
+
+	status, err := r.Items.FetchStatus(job.Item).Context(ctx).Do()
+	if err != nil {
+		job.State = StatePending
+		job.StartedAt = time.Time{}
+		job.Runner = ""
+		job.Error = fmt.Sprintf("failed to fetch status: %v", err)
+		return job
+	}
+	if r.OnStatus != nil {
+		r.OnStatus(job, status)
+	}
+	if reason := check(job, status); reason != "" {
+		return finish(StateSkipped, errors.New(reason))
+	}
+
+	switch job.Operation {
+	case OperationPublish:
+		call := r.Items.Publish(job.Item).Context(ctx)
+		if job.PublishType != "" {
+			call.PublishType(job.PublishType)
+		}
+		if job.DeployPercentage != 0 {
+			call.DeployPercentage(job.DeployPercentage)
+		}
+		result, err := call.Validate(!job.Force).Do()
+		if err != nil {
+			return finish(StateFailed, err)
+		}
+		job.ItemState = result.State
+	case OperationSetDeployPercentage:
+		_, err := r.Items.SetPublishedDeployPercentage(job.Item).
+			Context(ctx).
+			DeployPercentage(job.DeployPercentage).
+			Validate(!job.Force).
+			Do()
+		if err != nil {
+			return finish(StateFailed, err)
+		}
+	default:
+		return finish(StateFailed, fmt.Errorf("unknown operation %q", job.Operation))
+	}
+	return finish(StateDone, nil)
+}
+
+// check returns why job must not run against status, or "" if it may.
+func check(job Job, status *chromewebstore.ItemStatus) string {
+	if status.TakenDown {
+		return "the item was taken down"
+	}
+	current := SnapshotOf(status)
+	if current.PublishedVersion != job.Expect.PublishedVersion {
+		return fmt.Sprintf("the published version changed from %s to %s", versionOrNone(job.Expect.PublishedVersion), versionOrNone(current.PublishedVersion))
+	}
+
+	switch job.Operation {
+	case OperationPublish:
+		if current.SubmittedVersion != job.Expect.SubmittedVersion {
+			return fmt.Sprintf("the submitted version changed from %s to %s", versionOrNone(job.Expect.SubmittedVersion), versionOrNone(current.SubmittedVersion))
+		}
+		if sub := status.SubmittedItemRevisionStatus; sub != nil && sub.State != chromewebstore.ItemStateStaged {
+			return fmt.Sprintf("the submission is %s, not %s", sub.State, chromewebstore.ItemStateStaged)
+		}
+	case OperationSetDeployPercentage:
+		pub := status.PublishedItemRevisionStatus
+		if pub == nil || len(pub.DistributionChannels) == 0 {
+			return "the item is not published"
+		}
+		if p := pub.DistributionChannels[0].DeployPercentage; p >= job.DeployPercentage {
+			return fmt.Sprintf("the deploy percentage is already %d%%", p)
+		}
+	}
+	return ""
+}
+
+// versionOrNone returns v, or "none" if v is empty.
+func versionOrNone(v string) string {
+	if v == "" {
+		return "none"
+	}
+	return v
+}
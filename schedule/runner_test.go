@@ -0,0 +1,288 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+// testStore serves status as the item's status and records the changes
+// made to it.
+type testStore struct {
+	status  chromewebstore.ItemStatus
+	fail    bool
+	fetches int
+	changes []string
+}
+
+func newTestRunner(t *testing.T, ts *testStore, now time.Time) *Runner {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case ts.fail:
+			ts.fetches++
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":{"code":503,"message":"unavailable"}}`))
+		case strings.HasSuffix(r.URL.Path, ":fetchStatus"):
+			ts.fetches++
+			json.NewEncoder(w).Encode(ts.status)
+		case strings.HasSuffix(r.URL.Path, ":publish"):
+			ts.changes = append(ts.changes, "publish")
+			w.Write([]byte(`{"state":"PUBLISHED"}`))
+		default:
+			ts.changes = append(ts.changes, "deploy "+r.URL.Query().Get("deployPercentage"))
+			w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := chromewebstore.NewClient(nil)
+	client.SetBaseURL(server.URL)
+	return &Runner{
+		Items:    client.Publishers.Items,
+		Store:    New(filepath.Join(t.TempDir(), FileName)),
+		MaxDelay: time.Hour,
+		Now:      func() time.Time { return now },
+	}
+}
+
+func revision(state chromewebstore.ItemState, version string, percentage int) *chromewebstore.ItemRevisionStatus {
+	return &chromewebstore.ItemRevisionStatus{
+		State:                state,
+		DistributionChannels: []chromewebstore.DistributionChannel{{CrxVersion: version, DeployPercentage: percentage}},
+	}
+}
+
+func TestRunnerRunDue(t *testing.T) {
+	now := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	ts := &testStore{status: chromewebstore.ItemStatus{
+		SubmittedItemRevisionStatus: revision(chromewebstore.ItemStateStaged, "1.1", 0),
+		PublishedItemRevisionStatus: revision(chromewebstore.ItemStatePublished, "1.0", 100),
+	}}
+	r := newTestRunner(t, ts, now)
+	expect := SnapshotOf(&ts.status)
+
+	due, _ := r.Store.Add(Job{Item: testItem, Operation: OperationPublish, At: now.Add(-time.Minute), PublishType: chromewebstore.PublishTypeDefault, Expect: expect})
+	later, _ := r.Store.Add(Job{Item: testItem, Operation: OperationPublish, At: now.Add(time.Minute), Expect: expect})
+	overdue, _ := r.Store.Add(Job{Item: testItem, Operation: OperationPublish, At: now.Add(-2 * time.Hour), Expect: expect})
+
+	var reported, fetched []int
+	r.OnJob = func(job Job) { reported = append(reported, job.ID) }
+	r.OnStatus = func(job Job, status *chromewebstore.ItemStatus) { fetched = append(fetched, job.ID) }
+	jobs, err := r.RunDue(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].ID != overdue.ID || jobs[1].ID != due.ID || len(reported) != 2 {
+		t.Fatalf("expected the overdue and due jobs to run in order, got %+v", jobs)
+	}
+	if jobs[0].State != StateSkipped || !strings.Contains(jobs[0].Error, "missed by 2h0m0s") {
+		t.Errorf("expected the overdue job to be skipped, got %+v", jobs[0])
+	}
+	if len(fetched) != 1 || fetched[0] != due.ID {
+		t.Errorf("expected the status to be reported for the due job only, got %v", fetched)
+	}
+	if jobs[1].State != StateDone || jobs[1].ItemState != chromewebstore.ItemStatePublished || jobs[1].FinishedAt != now {
+		t.Errorf("expected the due job to be done, got %+v", jobs[1])
+	}
+	if strings.Join(ts.changes, ",") != "publish" {
+		t.Errorf("expected a single publish, got %v", ts.changes)
+	}
+
+	stored, _ := r.Store.Jobs()
+	if stored[1].ID != later.ID || stored[1].State != StatePending {
+		t.Errorf("expected the later job to stay pending, got %+v", stored[1])
+	}
+}
+
+func TestRunnerSafeguards(t *testing.T) {
+	now := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	scheduled := chromewebstore.ItemStatus{
+		SubmittedItemRevisionStatus: revision(chromewebstore.ItemStatePendingReview, "1.1", 0),
+		PublishedItemRevisionStatus: revision(chromewebstore.ItemStatePublished, "1.0", 10),
+	}
+
+	tests := []struct {
+		name   string
+		job    Job
+		status chromewebstore.ItemStatus
+		reason string
+	}{
+		{
+			name: "new submission",
+			job:  Job{Operation: OperationPublish},
+			status: chromewebstore.ItemStatus{
+				SubmittedItemRevisionStatus: revision(chromewebstore.ItemStateStaged, "1.2", 0),
+				PublishedItemRevisionStatus: scheduled.PublishedItemRevisionStatus,
+			},
+			reason: "the submitted version changed from 1.1 to 1.2",
+		},
+		{
+			name:   "still in review",
+			job:    Job{Operation: OperationPublish},
+			status: scheduled,
+			reason: "the submission is PENDING_REVIEW, not STAGED",
+		},
+		{
+			name:   "taken down",
+			job:    Job{Operation: OperationSetDeployPercentage, DeployPercentage: 50},
+			status: chromewebstore.ItemStatus{TakenDown: true},
+			reason: "the item was taken down",
+		},
+		{
+			name: "published elsewhere",
+			job:  Job{Operation: OperationSetDeployPercentage, DeployPercentage: 50},
+			status: chromewebstore.ItemStatus{
+				PublishedItemRevisionStatus: revision(chromewebstore.ItemStatePublished, "1.1", 100),
+			},
+			reason: "the published version changed from 1.0 to 1.1",
+		},
+		{
+			name: "already raised",
+			job:  Job{Operation: OperationSetDeployPercentage, DeployPercentage: 50},
+			status: chromewebstore.ItemStatus{
+				SubmittedItemRevisionStatus: scheduled.SubmittedItemRevisionStatus,
+				PublishedItemRevisionStatus: revision(chromewebstore.ItemStatePublished, "1.0", 50),
+			},
+			reason: "the deploy percentage is already 50%",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := &testStore{status: tt.status}
+			r := newTestRunner(t, ts, now)
+			tt.job.Item, tt.job.At, tt.job.Expect = testItem, now, SnapshotOf(&scheduled)
+			r.Store.Add(tt.job)
+
+			jobs, err := r.RunDue(context.Background())
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if jobs[0].State != StateSkipped || jobs[0].Error != tt.reason {
+				t.Errorf("expected the job to be skipped because %s, got %+v", tt.reason, jobs[0])
+			}
+			if len(ts.changes) > 0 {
+				t.Errorf("expected no changes, got %v", ts.changes)
+			}
+		})
+	}
+}
+
+func TestRunnerRetry(t *testing.T) {
+	now := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	ts := &testStore{
+		status: chromewebstore.ItemStatus{PublishedItemRevisionStatus: revision(chromewebstore.ItemStatePublished, "1.0", 10)},
+		fail:   true,
+	}
+	r := newTestRunner(t, ts, now)
+	r.Store.Add(Job{Item: testItem, Operation: OperationSetDeployPercentage, At: now, DeployPercentage: 50, Expect: SnapshotOf(&ts.status)})
+
+	jobs, err := r.RunDue(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if jobs[0].State != StatePending || !strings.Contains(jobs[0].Error, "failed to fetch status") {
+		t.Errorf("expected the job to be returned to pending, got %+v", jobs[0])
+	}
+
+	ts.fail = false
+	jobs, err = r.RunDue(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].State != StateDone || jobs[0].Error != "" {
+		t.Errorf("expected the job to be done on retry, got %+v", jobs)
+	}
+	if strings.Join(ts.changes, ",") != "deploy 50" {
+		t.Errorf("expected the deploy percentage to be set to 50, got %v", ts.changes)
+	}
+}
+
+func TestRunnerAbandonedJobs(t *testing.T) {
+	now := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	ts := &testStore{status: chromewebstore.ItemStatus{PublishedItemRevisionStatus: revision(chromewebstore.ItemStatePublished, "1.0", 10)}}
+	r := newTestRunner(t, ts, now)
+	r.ID = "second"
+	expect := SnapshotOf(&ts.status)
+	old, _ := r.Store.Add(Job{Item: testItem, Operation: OperationSetDeployPercentage, At: now.Add(-time.Hour), DeployPercentage: 50, Expect: expect})
+	recent, _ := r.Store.Add(Job{Item: testItem, Operation: OperationSetDeployPercentage, At: now.Add(-time.Minute), DeployPercentage: 50, Expect: expect})
+
+	// Simulate runners that stopped after claiming the jobs.
+	r.Store.claim(now.Add(-time.Hour), "first", 24*time.Hour)
+	r.Store.claim(now.Add(-time.Minute), "first", 24*time.Hour)
+
+	var reported []Job
+	r.OnJob = func(job Job) { reported = append(reported, job) }
+	jobs, err := r.RunDue(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != old.ID || len(reported) != 1 {
+		t.Fatalf("expected only the stale job to be reported, got %+v", jobs)
+	}
+	if jobs[0].State != StateFailed || !strings.Contains(jobs[0].Error, "runner first stopped") {
+		t.Errorf("expected the stale job to fail, got %+v", jobs[0])
+	}
+	if len(ts.changes) > 0 {
+		t.Errorf("expected no changes, got %v", ts.changes)
+	}
+
+	stored, _ := r.Store.Jobs()
+	if stored[1].ID != recent.ID || stored[1].State != StateRunning || stored[1].Runner != "first" {
+		t.Errorf("expected the recent job to stay running, got %+v", stored[1])
+	}
+	if !stored[1].Stale(now.Add(DefaultStaleAfter), DefaultStaleAfter) {
+		t.Error("expected the recent job to become stale")
+	}
+}
+
+func TestRunnerPolicyOverride(t *testing.T) {
+	now := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	ts := &testStore{status: chromewebstore.ItemStatus{PublishedItemRevisionStatus: revision(chromewebstore.ItemStatePublished, "1.0", 10)}}
+	r := newTestRunner(t, ts, now)
+	override := &chromewebstore.PolicyOverride{Reason: "hotfix", Rules: []string{"no-fridays"}}
+	r.Store.Add(Job{Item: testItem, Operation: OperationSetDeployPercentage, At: now, DeployPercentage: 50, Expect: SnapshotOf(&ts.status), PolicyOverride: override})
+
+	var got chromewebstore.PolicyOverride
+	r.JobContext = func(ctx context.Context, job Job) context.Context {
+		got, _ = chromewebstore.PolicyOverrideFromContext(ctx)
+		return ctx
+	}
+	if _, err := r.RunDue(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.Reason != "hotfix" || strings.Join(got.Rules, ",") != "no-fridays" {
+		t.Errorf("expected the job's override on its context, got %+v", got)
+	}
+}
+
+func TestRunnerRunRetryWait(t *testing.T) {
+	now := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	ts := &testStore{
+		status: chromewebstore.ItemStatus{PublishedItemRevisionStatus: revision(chromewebstore.ItemStatePublished, "1.0", 10)},
+		fail:   true,
+	}
+	r := newTestRunner(t, ts, now)
+	r.Store.Add(Job{Item: testItem, Operation: OperationSetDeployPercentage, At: now, DeployPercentage: 50, Expect: SnapshotOf(&ts.status)})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := r.Run(ctx, time.Hour); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if ts.fetches != 1 {
+		t.Errorf("expected a single attempt before the next interval, got %d", ts.fetches)
+	}
+	jobs, _ := r.Store.Jobs()
+	if jobs[0].State != StatePending {
+		t.Errorf("expected the job to stay pending, got %+v", jobs[0])
+	}
+}
@@ -0,0 +1,318 @@
+// Package schedule stores publishes and deploy percentage changes to be
+// made at a later time and runs them when they are due. Each job records
+// the item's revisions when it was scheduled, and is skipped if they
+// changed in the meantime.
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+	"github.com/H0R15H0/chrome-webstore-api-v2/internal/filelock"
+)
+
+// Operation identifies what a Job does.
+type Operation string
+
+const (
+	// OperationPublish publishes the item.
+	OperationPublish Operation = "publish"
+	// OperationSetDeployPercentage sets the published deploy percentage.
+	OperationSetDeployPercentage Operation = "set-deploy-percentage"
+)
+
+// State is the state of a Job.
+type State string
+
+const (
+	// StatePending jobs wait for their time.
+	StatePending State = "pending"
+	// StateRunning jobs have been claimed by a runner.
+	StateRunning State = "running"
+	// StateDone jobs were executed successfully.
+	StateDone State = "done"
+	// StateFailed jobs were executed and failed.
+	StateFailed State = "failed"
+	// StateSkipped jobs were not executed because the item changed or the
+	// job was overdue.
+	StateSkipped State = "skipped"
+	// StateCancelled jobs were cancelled before they ran.
+	StateCancelled State = "cancelled"
+)
+
+// Snapshot is the part of an item's status that must not change between
+// scheduling and running a job.
+type Snapshot struct {
+	// SubmittedVersion is the CRX version of the submitted revision, or ""
+	// if there is no submission.
+	SubmittedVersion string `json:"submittedVersion,omitempty"`
+	// PublishedVersion is the CRX version of the published revision, or ""
+	// if the item is not published.
+	PublishedVersion string `json:"publishedVersion,omitempty"`
+}
+
+// SnapshotOf returns the snapshot of status.
+func SnapshotOf(status *chromewebstore.ItemStatus) Snapshot {
+	return Snapshot{
+		SubmittedVersion: revisionVersion(status.SubmittedItemRevisionStatus),
+		PublishedVersion: revisionVersion(status.PublishedItemRevisionStatus),
+	}
+}
+
+// revisionVersion returns the CRX version of the first distribution
+// channel of r, or "" if r is nil.
+func revisionVersion(r *chromewebstore.ItemRevisionStatus) string {
+	if r == nil || len(r.DistributionChannels) == 0 {
+		return ""
+	}
+	return r.DistributionChannels[0].CrxVersion
+}
+
+// Job is a scheduled publish or deploy percentage change.
+type Job struct {
+	// ID identifies the job. It is assigned by Store.Add.
+	ID int `json:"id"`
+	// Item is the item of the job.
+	Item chromewebstore.ItemName `json:"item"`
+	// Operation is what the job does.
+	Operation Operation `json:"operation"`
+	// At is when the job is due.
+	At time.Time `json:"at"`
+	// PublishType is the publish type of OperationPublish.
+	PublishType chromewebstore.PublishType `json:"publishType,omitempty"`
+	// DeployPercentage is the initial deploy percentage of
+	// OperationPublish, if non-zero, or the target of
+	// OperationSetDeployPercentage.
+	DeployPercentage int `json:"deployPercentage,omitempty"`
+	// Force skips pre-flight validation of the deploy percentage.
+	Force bool `json:"force,omitempty"`
+	// PolicyOverride, if set, is requested from the client's policy when
+	// the job runs.
+	PolicyOverride *chromewebstore.PolicyOverride `json:"policyOverride,omitempty"`
+	// Expect is the snapshot of the item when the job was scheduled.
+	Expect Snapshot `json:"expect"`
+	// CreatedAt is when the job was scheduled.
+	CreatedAt time.Time `json:"createdAt"`
+	// CreatedBy is who scheduled the job.
+	CreatedBy string `json:"createdBy,omitempty"`
+
+	// State is the state of the job.
+	State State `json:"state"`
+	// StartedAt is when a runner claimed the job.
+	StartedAt time.Time `json:"startedAt,omitzero"`
+	// Runner identifies the runner that claimed the job.
+	Runner string `json:"runner,omitempty"`
+	// FinishedAt is when the job reached its final state.
+	FinishedAt time.Time `json:"finishedAt,omitzero"`
+	// ItemState is the item state returned by a publish.
+	ItemState chromewebstore.ItemState `json:"itemState,omitempty"`
+	// Error describes why the job failed or was skipped, or the last error
+	// that made a runner retry it.
+	Error string `json:"error,omitempty"`
+}
+
+// Finished reports whether the job reached its final state.
+func (j *Job) Finished() bool {
+	switch j.State {
+	case StateDone, StateFailed, StateSkipped, StateCancelled:
+		return true
+	}
+	return false
+}
+
+// Stale reports whether the job has been running for longer than after at
+// now, which means its runner most likely stopped before finishing it.
+func (j *Job) Stale(now time.Time, after time.Duration) bool {
+	return j.State == StateRunning && now.Sub(j.StartedAt) > after
+}
+
+// FileName is the name of the schedule file in the default location.
+const FileName = "schedule.json"
+
+// DefaultPath returns the default schedule file location:
+// $CWS_SCHEDULE_FILE if set, otherwise cws/schedule.json in the user
+// configuration directory.
+func DefaultPath() (string, error) {
+	if path := os.Getenv("CWS_SCHEDULE_FILE"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("schedule: %w", err)
+	}
+	return filepath.Join(dir, "cws", FileName), nil
+}
+
+// file is the content of the schedule file.
+type file struct {
+	NextID int   `json:"nextId"`
+	Jobs   []Job `json:"jobs"`
+}
+
+// Store is a schedule file. Changes are serialized between processes with
+// a lock file next to it.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// New returns a store backed by the file at path. The file is created on
+// the first Add.
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+// Path returns the path of the schedule file.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Jobs returns all jobs in order of ID. A missing schedule file yields no
+// jobs.
+func (s *Store) Jobs() ([]Job, error) {
+	f, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return f.Jobs, nil
+}
+
+// Add schedules job as pending and returns it with its ID. If
+// job.CreatedAt is zero, the current time is used.
+func (s *Store) Add(job Job) (Job, error) {
+	if job.Operation != OperationPublish && job.Operation != OperationSetDeployPercentage {
+		return Job{}, fmt.Errorf("schedule: unknown operation %q", job.Operation)
+	}
+	if job.At.IsZero() {
+		return Job{}, errors.New("schedule: job has no time")
+	}
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = time.Now()
+	}
+	job.State = StatePending
+	err := s.update(func(f *file) error {
+		f.NextID++
+		job.ID = f.NextID
+		f.Jobs = append(f.Jobs, job)
+		return nil
+	})
+	return job, err
+}
+
+// Cancel cancels the pending job with the given ID.
+func (s *Store) Cancel(id int) (Job, error) {
+	var job Job
+	err := s.update(func(f *file) error {
+		i := slices.IndexFunc(f.Jobs, func(j Job) bool { return j.ID == id })
+		if i < 0 {
+			return fmt.Errorf("schedule: no job %d", id)
+		}
+		if f.Jobs[i].State != StatePending {
+			return fmt.Errorf("schedule: job %d is %s", id, f.Jobs[i].State)
+		}
+		f.Jobs[i].State = StateCancelled
+		f.Jobs[i].FinishedAt = time.Now()
+		job = f.Jobs[i]
+		return nil
+	})
+	return job, err
+}
+
+// claim marks the pending jobs due at now as running by runner and
+// returns them in order of time. Jobs left running for longer than
+// staleAfter are marked failed and returned as abandoned: their runner
+// stopped, possibly after sending the request, so they are not run again.
+func (s *Store) claim(now time.Time, runner string, staleAfter time.Duration) (claimed, abandoned []Job, err error) {
+	err = s.update(func(f *file) error {
+		for i := range f.Jobs {
+			j := &f.Jobs[i]
+			switch {
+			case j.State == StatePending && !j.At.After(now):
+				j.State = StateRunning
+				j.StartedAt = now
+				j.Runner = runner
+				claimed = append(claimed, *j)
+			case j.Stale(now, staleAfter):
+				j.State = StateFailed
+				j.FinishedAt = now
+				j.Error = fmt.Sprintf("runner %s stopped while running the job at %s; check the item's status before scheduling it again", j.Runner, j.StartedAt.Format(time.RFC3339))
+				abandoned = append(abandoned, *j)
+			}
+		}
+		return nil
+	})
+	slices.SortStableFunc(claimed, func(a, b Job) int { return a.At.Compare(b.At) })
+	return claimed, abandoned, err
+}
+
+// put replaces the job with the ID of job.
+func (s *Store) put(job Job) error {
+	return s.update(func(f *file) error {
+		i := slices.IndexFunc(f.Jobs, func(j Job) bool { return j.ID == job.ID })
+		if i < 0 {
+			return fmt.Errorf("schedule: no job %d", job.ID)
+		}
+		f.Jobs[i] = job
+		return nil
+	})
+}
+
+// read reads the schedule file.
+func (s *Store) read() (*file, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &file{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("schedule: %w", err)
+	}
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("schedule: failed to parse %s: %w", s.path, err)
+	}
+	return &f, nil
+}
+
+// update applies fn to the schedule file while holding its lock, and
+// replaces the file if fn succeeds.
+func (s *Store) update(fn func(f *file) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("schedule: %w", err)
+	}
+	unlock, err := filelock.Lock(s.path + ".lock")
+	if err != nil {
+		return fmt.Errorf("schedule: %w", err)
+	}
+	defer unlock()
+
+	f, err := s.read()
+	if err != nil {
+		return err
+	}
+	if err := fn(f); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("schedule: failed to marshal jobs: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("schedule: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("schedule: %w", err)
+	}
+	return nil
+}
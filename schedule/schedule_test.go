@@ -0,0 +1,65 @@
+package schedule
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/H0R15H0/chrome-webstore-api-v2/chromewebstore"
+)
+
+var testItem = chromewebstore.NewItemName("pub", "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+
+func TestStore(t *testing.T) {
+	store := New(filepath.Join(t.TempDir(), FileName))
+	jobs, err := store.Jobs()
+	if err != nil || len(jobs) != 0 {
+		t.Fatalf("expected no jobs, got %v, %v", jobs, err)
+	}
+
+	at := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	first, err := store.Add(Job{Item: testItem, Operation: OperationPublish, At: at, PublishType: chromewebstore.PublishTypeStaged})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	second, err := store.Add(Job{Item: testItem, Operation: OperationSetDeployPercentage, At: at.Add(time.Hour), DeployPercentage: 50})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if first.ID != 1 || second.ID != 2 || first.State != StatePending || first.CreatedAt.IsZero() {
+		t.Errorf("unexpected jobs %+v, %+v", first, second)
+	}
+	if _, err := store.Add(Job{Item: testItem, Operation: "upload", At: at}); err == nil {
+		t.Error("expected an error for an unknown operation")
+	}
+
+	cancelled, err := store.Cancel(first.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cancelled.State != StateCancelled || !cancelled.Finished() {
+		t.Errorf("expected the job to be cancelled, got %+v", cancelled)
+	}
+	if _, err := store.Cancel(first.ID); err == nil {
+		t.Error("expected an error cancelling a cancelled job")
+	}
+	if _, err := store.Cancel(99); err == nil {
+		t.Error("expected an error cancelling an unknown job")
+	}
+
+	claimed, _, err := store.claim(at.Add(time.Hour), "test", DefaultStaleAfter)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != second.ID || claimed[0].State != StateRunning || claimed[0].Runner != "test" {
+		t.Errorf("expected job 2 to be claimed, got %+v", claimed)
+	}
+
+	jobs, err = New(store.Path()).Jobs()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].State != StateCancelled || jobs[1].State != StateRunning || jobs[1].DeployPercentage != 50 {
+		t.Errorf("expected the jobs to be persisted, got %+v", jobs)
+	}
+}